@@ -0,0 +1,79 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/controller/managedcluster"
+	ocinfrav1 "github.com/openshift/api/config/v1"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+//exportImportYAMLCommand writes the import YAML of a managed cluster instead of starting the controller
+const exportImportYAMLCommand = "export-import-yaml"
+
+//runExportImportYAML generates the import YAML of a managed cluster with the controller configuration
+//(environment variables and hub kubeconfig) and writes it to stdout or a file
+func runExportImportYAML(args []string) error {
+	flags := pflag.NewFlagSet(exportImportYAMLCommand, pflag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s --cluster-name NAME [--output FILE]\n\n", exportImportYAMLCommand)
+		fmt.Fprintln(os.Stderr, "Writes the import YAML of a managed cluster, it contains the bootstrap token of the cluster.")
+		fmt.Fprintln(os.Stderr, "When the bootstrap token is requested for audiences, a missing or expiring token is requested")
+		fmt.Fprintf(os.Stderr, "and stored in the <cluster-name>-bootstrap-audience-token secret of the hub.\n\n")
+		flags.PrintDefaults()
+	}
+	clusterName := flags.String("cluster-name", "", "name of the ManagedCluster to export the import YAML for")
+	output := flags.StringP("output", "o", "",
+		"file to write the import YAML to, created readable by the owner only, default stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *clusterName == "" {
+		return fmt.Errorf("--cluster-name is required")
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	s := k8sruntime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		return err
+	}
+	if err := clusterv1.Install(s); err != nil {
+		return err
+	}
+	if err := ocinfrav1.AddToScheme(s); err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		//The import YAML contains the bootstrap token
+		f, err := os.OpenFile(*output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return managedcluster.ExportImportYAML(c, kubeClient, *clusterName, w)
+}
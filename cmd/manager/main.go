@@ -14,7 +14,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime"
+	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
 	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
@@ -24,6 +28,8 @@ import (
 	"k8s.io/klog"
 
 	"github.com/open-cluster-management/managedcluster-import-controller/pkg/controller"
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/controller/managedcluster"
+	managedclusterwebhook "github.com/open-cluster-management/managedcluster-import-controller/pkg/webhook/managedcluster"
 	ocinfrav1 "github.com/openshift/api/config/v1"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
@@ -51,6 +57,9 @@ var (
 
 var log = logf.Log.WithName("cmd")
 
+//enableLocalClusterDeletionWebhookEnvVarName enables the webhook protecting the local-cluster from deletion
+const enableLocalClusterDeletionWebhookEnvVarName = "ENABLE_LOCAL_CLUSTER_DELETION_WEBHOOK"
+
 func printVersion() {
 	log.Info(fmt.Sprintf("Go Version: %s", runtime.Version()))
 	log.Info(fmt.Sprintf("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH))
@@ -58,6 +67,14 @@ func printVersion() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == exportImportYAMLCommand {
+		if err := runExportImportYAML(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	klog.InitFlags(nil)
 	// Add the zap logger flag set to the CLI. The flag set must
 	// be added before calling pflag.Parse().
@@ -160,12 +177,37 @@ func main() {
 
 	//Channel to stop the manager
 	stopMgrCh := make(chan struct{})
+	var stopMgrOnce sync.Once
+	stopMgr := func() {
+		stopMgrOnce.Do(func() { close(stopMgrCh) })
+	}
+
+	//On SIGTERM stop dispatching reconciles, the imports in progress are drained once the manager stopped
+	signalCh := make(chan os.Signal, 2)
+	signal.Notify(signalCh, syscall.SIGTERM, os.Interrupt)
+	go func() {
+		<-signalCh
+		log.Info("Shutdown signal received, stopping the manager")
+		stopMgr()
+		<-signalCh
+		log.Info("Second shutdown signal received, exiting without draining")
+		os.Exit(1)
+	}()
 
 	if err := controller.AddToManager(mgr, missingGVS); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
+	//The webhook server requires a serving certificate, so the webhooks are only served on demand
+	if enabled, _ := strconv.ParseBool(os.Getenv(enableLocalClusterDeletionWebhookEnvVarName)); enabled {
+		log.Info("Add the ManagedCluster webhooks")
+		if err := managedclusterwebhook.Add(mgr); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
 	nbOfMissingGVS := len(missingGVS)
 
 	//If some CRD are not yet installled then we will monitor them
@@ -188,7 +230,7 @@ func main() {
 			//Close the manager
 			log.Error(fmt.Errorf("new CRD discovered %s", ""),
 				"This is an expected behavior, the operator stopped because a new CRD managed by this operator get discovered")
-			close(stopMgrCh)
+			stopMgr()
 		}()
 	}
 
@@ -200,6 +242,9 @@ func main() {
 		log.Error(err, "Manager exited non-zero")
 		os.Exit(1)
 	}
+
+	//The reconciles in progress are not waited by the manager, let the imports complete
+	managedcluster.DrainImports()
 }
 
 // addMetrics will create the Services and Service Monitors to allow the operator export the metrics by using
@@ -12,6 +12,7 @@ require (
 	github.com/openshift/api v3.9.1-0.20191112184635-86def77f6f90+incompatible
 	github.com/openshift/hive v1.0.18
 	github.com/operator-framework/operator-sdk v0.18.1
+	github.com/prometheus/client_golang v1.7.1
 	github.com/spf13/pflag v1.0.5
 	k8s.io/api v0.20.5
 	k8s.io/apimachinery v0.20.5
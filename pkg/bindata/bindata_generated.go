@@ -89,7 +89,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _hubManagedclusterManifestsManagedclusterClusterroleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x90\x31\x4f\xe3\x50\x0c\xc7\xf7\xf7\x29\xac\x74\xbd\xe6\x74\xdb\xe9\x6d\xa7\x0e\x37\x01\x12\x03\x0b\xea\xe0\x24\x26\x31\x4d\x9e\x1f\xb6\xd3\xaa\x54\xfd\xee\xa8\x4d\x40\x40\xe9\x64\x4b\xfe\xc9\xfe\xfd\xbd\x80\x95\xe4\xbd\x72\xdb\x39\xac\x24\xb9\x72\x35\xba\xa8\x81\x0b\x78\x47\x70\x97\x29\xc1\xaa\x1f\xcd\x49\xe1\x06\x13\xb6\x34\x50\x72\xc8\x2a\xcf\x54\x7b\x08\x98\xf9\x81\xd4\x58\x52\x04\xad\xb0\x2e\x71\xf4\x4e\x94\x5f\xd1\x59\x52\xb9\xf9\x6b\x25\xcb\xef\xed\x9f\xb0\xe1\xd4\xc4\xf7\x55\xf7\xd2\x53\x18\xc8\xb1\x41\xc7\x18\x00\x12\x0e\x14\xc1\xf6\xe6\x34\x44\xc9\x94\x96\xf5\x44\x2e\x87\x8f\xa3\x71\x6a\x9b\x79\x12\x2b\x11\x37\x57\xcc\xf1\x70\x80\x72\x92\x6b\xe6\x03\xb7\x38\x10\x1c\x8f\x41\xc7\x9e\x2c\x86\x05\xfc\xeb\x7b\xd9\xc1\xbc\x01\xb0\x3d\x85\x70\x01\x15\x47\x27\x60\x37\xa8\x49\x9d\x9f\xb8\x46\xa7\xb0\x04\xcc\xfc\x5f\x65\xcc\x16\xe1\xb1\xf8\x34\xb2\x39\x52\xb1\x0e\x00\x4a\x26\xa3\xd6\x74\x01\x71\x9b\x38\xb5\x4a\x2f\x23\x99\xdb\x99\xdd\x92\x56\x13\xa7\x84\x4e\xc5\x2f\x28\x5a\xf2\x53\xe9\xd9\xce\x75\x87\x5e\x77\xc5\xfa\xba\x6c\x4b\x7e\x61\x36\xc5\x2d\xaf\xbc\xec\x47\xd1\xaf\x6f\xfc\x66\x37\x3b\xcd\x92\xeb\xf0\x16\x00\x00\xff\xff\xf7\xf9\xea\x7a\x21\x02\x00\x00")
+var _hubManagedclusterManifestsManagedclusterClusterroleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x53\xb1\x6e\xdc\x30\x0c\xdd\xfd\x15\x84\x93\xa2\xcb\x9d\x83\x6e\x81\x80\x0e\x69\x86\x2e\x69\x1b\x74\xe8\x12\x74\x90\x65\x9e\x4f\x3d\x59\x72\x28\xfa\x82\xeb\x21\xff\x5e\xca\xf2\xb5\x35\x9c\xcb\xd4\x2e\x16\x4d\x3e\x91\x8f\xe4\xd3\x05\xdc\x86\xfe\x40\xb6\xdd\xb2\x58\x9e\xc9\xd6\x03\x07\x8a\xc0\x01\x78\x8b\xf0\xa5\x47\x0f\xb7\x6e\x88\x8c\x04\x9f\xb4\xd7\x2d\x76\xe8\x19\x7a\x0a\x3f\xd0\x70\x51\xe8\xde\x7e\x43\x8a\x36\x78\x05\x54\x6b\x53\xe9\x81\xb7\x81\xec\x4f\xcd\xe2\xab\x76\xd7\xb1\xb2\xe1\x6a\xff\xae\xd8\x59\xdf\xa8\x53\xaa\xaf\xc1\x61\xd1\x21\xeb\x46\xb3\x56\x05\x80\xd7\x1d\x2a\x88\x07\x09\x76\x2a\x48\xd1\xb5\xc9\xc8\x75\xf7\xbb\xa8\xca\x66\x33\x45\x54\x1d\x02\x47\x26\xdd\xab\xe3\x11\xaa\x4c\xae\x99\x0a\x7c\x96\x7c\xf0\xfc\x5c\x1c\x8f\x6b\xb0\x1b\xa8\xee\x74\x8d\x2e\x26\x0f\x80\x1b\x6d\x35\xc6\x48\xfb\x16\xe1\x72\x87\x87\x15\x5c\xee\xb5\x1b\x10\xd4\xfb\x39\x1c\x40\xd2\xf7\x64\x3d\x6f\xa0\x7c\xf3\x58\x8e\x68\x09\xa9\x85\x3f\xdf\x9f\xaa\xa2\x6f\x5e\x30\x13\x97\x1b\xef\x03\x8f\xe3\x99\x2a\xe8\x3f\x8e\x57\x59\x2d\x2e\xfe\x33\x6a\x34\x38\x94\xda\x17\x70\xe3\x5c\x78\x82\x69\xd0\x20\x1f\xd9\xb5\x48\x81\x52\x5d\x04\xcb\x11\x0c\x12\xdb\x8d\x35\xf2\x5f\xac\x41\xd6\xff\x91\xc2\xd0\x47\x05\x0f\xe5\x5f\xa1\x38\x6d\xbe\xfc\x2e\x34\x09\x63\x18\xc8\xe0\x02\x64\x5b\x6f\x7d\x4b\xf8\x38\x60\xe4\x38\x62\xf7\x48\x75\xc6\x11\x0a\xa4\x5c\x41\xd9\x22\xa7\xc3\xd9\x38\x9e\x4f\x9a\xcd\x56\xb0\x67\xc9\x0a\x7e\xc1\x2c\xab\xa2\x3a\xa3\xac\x17\x89\xce\xd5\x96\xd8\x9d\x16\xf8\xe1\x24\xbc\x3b\xd4\x91\xef\xc9\xee\xad\xc3\x16\xf3\x4e\x4e\x39\x92\x02\xc7\x3c\x67\xc5\x39\xef\x37\x75\xf9\x4a\x53\x84\xad\x4d\x77\x57\xf3\x02\x60\xb4\x7f\xcb\xc9\x27\x4f\xd7\x30\xe4\xa9\xfd\x9f\xf6\x97\xcb\xc9\x23\x91\x97\x32\xf5\x3e\x6b\x46\x56\x35\x87\x65\xa9\xfd\x02\x11\x7e\x0f\xda\x72\x04\x00\x00")
 
 func hubManagedclusterManifestsManagedclusterClusterroleYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -109,7 +109,7 @@ func hubManagedclusterManifestsManagedclusterClusterroleYaml() (*asset, error) {
 	return a, nil
 }
 
-var _hubManagedclusterManifestsManagedclusterClusterrolebindingYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x90\x31\x6b\x33\x31\x0c\x86\x77\xff\x0a\xc1\x37\xe7\x3e\xba\x15\x6f\x4d\x86\x4e\x6d\x21\x85\xee\x3a\x9f\x9a\xa8\x89\x25\x23\xcb\x81\x34\xe4\xbf\x97\xeb\x5d\x02\x25\xd0\xad\x9b\xe1\x7d\xfd\x3e\x0f\xfa\x07\x2b\x2d\x47\xe3\xcd\xd6\x61\xa5\xe2\xc6\x7d\x73\xb5\x0a\xae\xe0\x5b\x82\x97\x42\x02\xab\x7d\xab\x4e\x06\x4f\x28\xb8\xa1\x4c\xe2\x50\x4c\x3f\x28\x79\x08\x58\xf8\x8d\xac\xb2\x4a\x04\xeb\x31\x75\xd8\x7c\xab\xc6\x9f\xe8\xac\xd2\xed\xee\x6b\xc7\xfa\xff\x70\x17\x76\x2c\x43\xbc\x4c\xad\x75\x4f\x4b\x96\x81\x65\x13\x32\x39\x0e\xe8\x18\x03\x80\x60\xa6\x08\xf5\x58\x9d\x72\xd4\x42\xb2\x48\xd3\x87\x45\xbe\xb2\xe3\xf4\x1c\xe6\x24\xf6\xaa\x5e\xdd\xb0\xc4\xd3\x09\xba\xc9\x71\x98\x39\xcf\x98\x09\xce\xe7\x60\xba\xa7\x35\xbd\x8f\x08\x2c\xfc\x68\xda\xca\x2f\xba\x01\xe0\xc6\xf6\x0f\xe5\x6a\xeb\xc7\x5b\xd6\x18\x16\x33\xf7\x95\xec\xc0\x89\x1e\x52\xd2\x26\x7e\x45\x8f\x13\xcb\xcb\xe0\xcf\xce\x65\x6b\xaa\xd6\x82\x69\xee\xdf\x22\xbf\xc3\xb1\xfb\x15\x00\x00\xff\xff\x10\xfe\x36\xee\xfc\x01\x00\x00")
+var _hubManagedclusterManifestsManagedclusterClusterrolebindingYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x52\xcb\x4e\xc3\x30\x10\xbc\xe7\x2b\x56\xa5\xdc\x48\x10\x37\x64\x89\x43\xdb\x03\x17\x1e\x52\x91\xb8\x6f\x9c\x6d\x6a\x9a\xd8\xc6\x5e\x57\x2a\x51\xff\x1d\xe7\x55\x54\x05\xf5\x04\xb7\xd5\xec\xec\xcc\xc8\xe3\x2b\x58\x19\x7b\x70\xaa\xdc\x72\x9c\x34\x3b\x95\x07\x36\xce\x03\x1b\xe0\x2d\xc1\xab\x25\x0d\xab\x2a\x78\x26\x07\xcf\xa8\xb1\xa4\x9a\x34\x83\x75\xe6\x83\x24\x27\x09\x5a\xf5\x4e\xce\x2b\xa3\x05\xb8\x1c\x65\x86\x81\xb7\xc6\xa9\x2f\xe4\x88\x65\xbb\x7b\x9f\x29\x73\xbb\xbf\x4b\x76\x4a\x17\x62\x94\x5a\x9b\x8a\x96\x11\x50\xba\x4c\x6a\x62\x2c\x90\x51\x24\x00\x1a\x6b\x12\xe0\x0f\x91\x53\x0b\x13\xbd\x53\xd9\x1f\xa4\xf5\xc9\x5b\xf4\x63\x31\x6c\x44\x6e\x0c\x7b\x76\x68\x45\xd3\x40\xd6\x67\x2c\x06\x9f\x97\xa8\x07\xc7\x63\xd2\x34\x29\xa8\x0d\x64\x4f\x98\x53\xe5\x5b\x04\xa0\xea\x66\xd1\xed\x1c\xea\x92\x60\xbe\xa3\xc3\x0d\xcc\xf7\x58\x05\x02\xf1\x70\x4e\x07\x88\xf2\xd6\x29\xcd\x1b\x98\x5d\x7f\xce\x3a\x76\x5c\x89\x09\xde\xdf\x0f\xae\xa4\x8b\x5f\xc6\x36\xcb\x42\x6b\xc3\xdd\x2b\x0d\x0e\xf8\x03\x5c\x4c\x35\x39\xfc\xb3\x68\x2e\xd6\xb2\xa6\x4d\xdb\x44\xec\xf5\xd1\x99\x60\x2f\xb4\x1a\x59\x93\x52\xff\xb1\x43\x1f\xf2\xf6\xcb\xc5\xb7\x49\x07\xdf\x37\x72\x7b\x25\x69\x21\xa5\x09\x9a\x4f\xd6\xad\xc4\x72\x14\x3c\xe7\x8c\x5a\x3d\xd5\x5b\x94\x03\x7f\x6a\xd9\x2d\x5b\xee\x37\x79\xc2\x24\x8a\x23\x03\x00\x00")
 
 func hubManagedclusterManifestsManagedclusterClusterrolebindingYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -129,7 +129,7 @@ func hubManagedclusterManifestsManagedclusterClusterrolebindingYaml() (*asset, e
 	return a, nil
 }
 
-var _hubManagedclusterManifestsManagedclusterServiceAccountYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xce\xb1\x6a\xc4\x30\x10\x84\xe1\x5e\x4f\x31\x38\x7d\x20\xad\xba\xc4\x75\x9c\x22\x90\x7e\x23\x2f\xb6\x92\xd3\xae\x58\xad\x0d\x87\xf1\xbb\x1f\xdc\xd9\xc5\xd5\xf3\x0d\xfc\x2f\xe8\xb5\x5e\x2d\x4f\xb3\xa3\x57\x71\xcb\xbf\x8b\xab\x35\xb8\xc2\x67\xc6\x57\x65\x41\x7f\x59\x9a\xb3\xe1\x93\x84\x26\x2e\x2c\x8e\x6a\xfa\xc7\xc9\x43\xa0\x9a\x7f\xd8\x5a\x56\x89\x58\xdf\xc2\x7f\x96\x31\xe2\x9b\x6d\xcd\x89\xdf\x53\xd2\x45\x3c\x14\x76\x1a\xc9\x29\x06\x40\xa8\x70\x44\xb7\x6d\x78\xfd\x50\xf5\xe6\x46\xf5\x99\x0f\x54\x18\xfb\xde\x1d\xb8\x55\x4a\xe7\xe3\x11\x30\x1e\x3d\xc3\xb9\xde\xf5\x2d\x00\x00\xff\xff\x9b\xd4\xc7\xa7\xca\x00\x00\x00")
+var _hubManagedclusterManifestsManagedclusterServiceAccountYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x90\x31\x4f\xc3\x30\x14\x84\xf7\xfc\x8a\x53\x28\x1b\x54\x62\xb5\xc4\x50\xba\x96\x32\x20\xb1\xbf\x3a\xaf\xa9\x69\xf2\x6c\xec\x97\x48\x55\xd4\xff\x8e\x9b\xa6\x42\x55\x11\x13\xdb\xe9\xee\x3b\xbf\x93\xef\xb0\xf4\xe1\x10\x5d\xbd\xd3\xac\x44\xa3\xdb\x74\xea\x63\x82\x7a\xe8\x8e\xf1\x16\x58\xb0\x6c\xba\xa4\x1c\xf1\x4a\x42\x35\xb7\x2c\x8a\x10\xfd\x27\x5b\x2d\x0a\x0a\xee\x83\x63\x72\x5e\x0c\xfa\xa7\x62\xef\xa4\x32\x78\xe7\xd8\x3b\xcb\x0b\x6b\x7d\x27\x5a\xb4\xac\x54\x91\x92\x29\x00\xa1\x96\x0d\xca\x61\xc0\xfc\xc5\x7b\x4d\x1a\x29\x5c\xe3\xeb\x4c\xe0\x78\x2c\x27\x38\x05\xb2\x97\xc6\x79\x40\x35\xed\x59\x5f\xd2\x91\x1e\x86\x47\xb8\x2d\xe6\x2b\xda\x70\x93\xb2\x95\xfb\xcd\xa8\xcd\x98\x45\x92\x9a\x31\xdb\xf3\xe1\x01\xb3\x9e\x9a\x8e\x61\x9e\xaf\x71\x20\x1f\x09\xd1\x89\x6e\x51\xde\x7f\x95\x23\x9d\x23\x73\xe3\x9f\xfb\xb9\x74\x7a\x99\xa5\xfa\x45\x9e\xb6\x2c\x44\xbc\x92\xe6\xcf\x99\x2e\xd0\x8f\xf1\xe7\xaa\x9b\xe2\xbf\x4d\xfb\x06\x05\x65\xfe\x15\xf1\x01\x00\x00")
 
 func hubManagedclusterManifestsManagedclusterServiceAccountYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -169,7 +169,7 @@ func klusterletBootstrap_secretYaml() (*asset, error) {
 	return a, nil
 }
 
-var _klusterletCluster_roleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x54\x3f\x8f\xdb\x3e\x0c\xdd\xfd\x29\x04\xdf\xfa\x4b\x82\xdf\x56\x78\x2b\x32\x74\x2a\x0a\x74\xe8\x52\x64\x60\x64\xc6\x61\x23\x8b\x02\x49\x25\xbd\x7e\xfa\x42\xce\x9f\xde\xc5\x4e\x9b\x43\x33\x74\xb2\x2d\x89\x8f\xef\x3d\x3f\xf1\xc9\x2d\x39\x3d\x0b\x75\x5b\x73\x4b\x8e\x26\xb4\xce\xc6\xa2\xce\xd8\xd9\x16\xdd\xa7\x84\xd1\x2d\x43\x56\x43\x71\x1f\x21\x42\x87\x3d\x46\x73\x49\xf8\x1b\x7a\xab\x2a\x48\xf4\x05\x45\x89\x63\xe3\x64\x0d\x7e\x0e\xd9\xb6\x2c\xf4\x03\x8c\x38\xce\x77\xef\x74\x4e\xbc\xd8\xff\x5f\xed\x28\xb6\xcd\x19\xea\x33\x07\xac\x7a\x34\x68\xc1\xa0\xa9\x9c\x8b\xd0\x63\xe3\x76\xc7\xdd\x80\x56\x49\x0e\xa8\x4d\xf5\xe4\xde\x87\xc0\x87\x81\x8b\x60\x47\x6a\x32\x00\xcf\x38\xa1\x80\xb1\x14\xa2\x5e\x10\x0c\xdd\x81\x65\x17\x18\xda\x6a\xe6\x20\xd1\x07\xe1\x9c\xb4\x71\x5f\xeb\x7a\x55\x39\x27\xa8\x9c\xc5\xe3\xb0\xa2\xe8\x05\x4d\xeb\xff\x5c\xed\x39\x6e\xa8\xeb\x21\x0d\x5f\x8a\xb2\x27\x8f\xe0\x3d\xe7\x68\x3a\x54\xee\x51\xd6\x43\xd5\xb1\x4d\x39\xd6\xa1\x95\x47\x20\x1d\x9e\x39\xb5\xa7\x8d\x03\x98\xdf\x96\x97\x74\x7e\x69\x31\xa0\x61\xbd\xba\x26\x35\x65\xd3\x04\xd1\xbc\x2e\x36\x83\xf7\xa8\x2a\xb8\x27\x3c\x4c\x93\x1a\xe1\x8f\xb1\x8a\xc5\x9a\xc0\xe3\xbd\xb2\x4e\x62\x6e\x4a\x28\x67\x70\x8f\xd1\xf4\x26\xff\xe3\xf6\xad\x7e\x17\x93\x4e\x06\x8e\x4d\x4a\x49\xc7\xa0\x2d\xa6\xc0\xcf\xfd\xef\x90\x1f\xf0\x83\x6e\x86\x79\x4c\xc8\x1f\x63\x2b\x1c\x70\x4d\xb1\xa5\xd8\x0d\x61\x7a\xf5\xfd\xaf\x11\xbd\x30\x7c\x20\xb5\x92\x07\xf5\x10\x4e\xe7\x8a\xf6\x7a\xf5\xa6\x2b\xec\xa5\xd5\x71\x08\x08\xbf\x1b\xc6\x32\x63\x6e\x27\xcd\x67\x35\xee\xcf\x4b\x2d\x6e\x28\x52\xe9\xf2\x50\xe7\xef\x92\xd2\x0f\x53\xf2\xc5\x2c\x2b\x72\x74\x7e\x2d\xeb\x5c\x32\xe7\x84\x71\x76\xfa\x33\xb3\xfe\x32\x62\x27\x55\xfe\x02\xbd\xd2\x35\x7d\x75\x5f\xca\xfa\x63\x8e\xfe\x96\xd0\x42\x0d\x2c\x5f\xf1\xba\xee\x7f\xa7\x87\x45\xc7\xe2\x58\xbb\x18\x0a\x1d\xa4\x14\x08\xdb\x1e\x22\x6d\x50\xad\xcc\xfa\xb1\xa7\x65\xf5\x4d\xf4\xa7\x50\x5f\x0b\x18\xe7\xe3\x2c\xe4\x67\x00\x00\x00\xff\xff\xee\x8d\xc6\x34\x3b\x07\x00\x00")
+var _klusterletCluster_roleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcd\x54\x3d\x6f\xdb\x30\x10\xdd\xf5\x2b\x08\x65\xad\x65\x74\x2b\xb4\x15\x19\x3a\x14\x6d\x80\x0c\x5d\x8a\x0e\x34\x75\x96\x59\x53\x24\x71\x77\xb4\x9b\x06\xf9\xef\x3d\xca\x92\x9b\x58\x72\xeb\xa0\x1e\x3a\xf1\x48\xde\xc7\x7b\xc7\xc7\xbb\x51\xb7\x21\x3e\xa0\x6d\x37\x2c\x96\x67\xb4\xab\xc4\x01\x49\x71\x50\xbc\x01\x75\x17\xc1\xab\x5b\x97\x88\x01\xd5\x27\xed\x75\x0b\x1d\x78\x56\x11\xc3\x77\x30\x5c\x14\x3a\xda\x2f\x80\x64\x83\xaf\x15\xae\xb4\xa9\x74\xe2\x4d\x40\xfb\x53\xb3\x9c\x55\xdb\x77\x54\xd9\xb0\xdc\xbd\x2d\xb6\xd6\x37\xf5\x98\xea\x3e\x38\x28\x3a\x60\xdd\x68\xd6\x75\xa1\x94\xd7\x1d\xd4\xaa\x7c\x7c\x54\xd5\xc7\x83\x8b\x03\x7e\xe6\xfc\x59\xee\xd5\xd3\x53\x59\x60\x72\x40\x75\x71\xa3\xde\x3b\x17\xf6\x3d\x46\x84\xd6\x12\x63\x5f\x70\x11\x22\x88\x15\x30\x13\x30\x08\x9a\x41\xed\x03\x6e\x5d\xd0\x4d\xb1\x50\x82\xf6\x03\x86\x14\xa9\x56\x5f\xcb\xf2\x9b\x14\x46\xa0\x90\xd0\x40\x7f\x42\x20\x21\x4c\xe5\x1b\x55\x9a\xe0\xd7\xb6\xed\x74\xec\x77\x04\xb8\xb3\x06\xb4\x31\x21\x79\x71\xc8\x91\x3b\xc0\x55\x1f\x75\x28\x93\xdd\x5a\xe0\xbc\x38\x81\x93\xd7\x14\x9b\xe1\x62\xaf\xd9\x6c\xb2\x11\x47\xa3\x01\x21\x08\x92\xe8\x04\xd4\x5c\xfb\x66\x80\xa6\x55\x6e\xbf\xe0\x01\x22\x84\x9d\x85\xfd\x3c\xa8\x49\xfe\x69\xae\xdc\x7a\x8a\x5a\x76\x17\xd2\x1a\xc8\x9c\xa5\x90\x7d\x60\x27\x2a\xa1\xb3\xf8\x0f\xd7\xe7\xea\x1d\x9b\x34\x34\x70\xda\xa4\x18\x69\x9a\xb4\x81\xe8\xc2\x43\xf7\xa7\xcc\x57\x78\xa0\xb3\x22\x9f\x02\x32\x07\xfd\xa2\xe8\x77\x25\xea\xb7\xbe\xed\xc5\xf4\x62\xff\xbf\x01\x3d\x22\xbc\x22\xb4\xac\x07\x32\xda\x0d\x7e\x99\xbb\x24\x7f\xcd\x17\x36\xd8\xd0\x54\x04\x16\x7e\x30\xf8\x3c\x7b\xce\x2b\xcd\x08\xb3\xd0\x8d\x47\x0d\xac\xad\xb7\xb9\xca\x55\x3b\x7f\x11\x95\xae\x9f\x9e\x6a\x7b\x1c\x6f\x99\x0e\x55\xa7\xb4\xc6\x90\x4a\x0c\xbf\x18\x5e\x66\xd1\x1d\x47\xef\x2c\xcb\xdf\x49\x4f\x78\xcd\x7f\xdd\xe7\xb4\xfe\xaa\xa3\x7f\x05\xb4\x24\xd6\x9c\x4e\x70\x9d\xd6\xbf\xb0\x87\x99\xc7\xf2\x10\xbb\xec\x03\x05\x6a\x74\x16\x1a\x81\x63\xd7\x40\x9c\x67\xfd\xb4\xa7\xf9\xf4\x55\xf0\xe7\xb2\xbe\x24\x30\xd5\xc7\x48\xe4\x17\xd7\xbd\x37\x24\x53\x07\x00\x00")
 
 func klusterletCluster_roleYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -189,7 +189,7 @@ func klusterletCluster_roleYaml() (*asset, error) {
 	return a, nil
 }
 
-var _klusterletCluster_role_bindingYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8e\x31\x4f\xc3\x40\x0c\x85\xf7\xfb\x15\x56\x99\x09\x62\x43\xb7\x41\x06\x06\x04\x48\x45\x62\x77\x2e\x26\x31\x49\xec\x93\xcf\x57\x09\xaa\xfe\x77\x54\x95\xb2\x10\x75\xf5\xe7\xf7\xbe\x77\x05\xad\xe6\x2f\xe3\x61\x74\x68\x55\xdc\xb8\xab\xae\x56\xc0\x15\x7c\x24\x78\xcd\x24\xd0\xce\xb5\x38\x19\x3c\xa3\xe0\x40\x0b\x89\x43\x36\xfd\xa4\xe4\x21\x60\xe6\x77\xb2\xc2\x2a\x11\xac\xc3\xd4\x60\xf5\x51\x8d\xbf\xd1\x59\xa5\x99\xee\x4a\xc3\x7a\xb3\xbb\x0d\x13\x4b\x1f\xcf\x55\x5b\x9d\xe9\x81\xa5\x67\x19\xc2\x42\x8e\x3d\x3a\xc6\x00\x20\xb8\x50\x84\xe9\xf4\x34\x93\x07\xd3\x99\xb6\xf4\x71\x64\x98\xf9\xd1\xb4\xe6\x0b\x9e\x00\xf0\x4f\xb3\xd6\x5a\x6a\x77\x5c\x5f\x62\xb8\xfe\x0d\xbc\x91\xed\x38\xd1\x7d\x4a\x5a\xc5\xd7\x32\xa7\x53\xc9\x98\x28\xc2\x66\xbf\x87\xe6\xe9\x0f\xbe\x9c\x09\x1c\x0e\x9b\xf0\x13\x00\x00\xff\xff\xf2\xf1\x30\xe3\x54\x01\x00\x00")
+var _klusterletCluster_role_bindingYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x8f\x41\x4b\x03\x41\x0c\x85\xef\xf3\x2b\x42\x7b\x76\xc5\x5b\x99\x9b\xf6\xe0\x41\x5a\xa1\x82\xf7\xec\x34\xee\x8e\xbb\x9b\x0c\x99\x4c\x41\x4b\xff\xbb\x53\x6d\x8b\x50\x10\xbc\x25\xef\x25\xdf\x4b\xe6\xb0\x94\xf4\xa1\xb1\xeb\xad\x56\x6c\x1a\xdb\x62\xa2\x19\x4c\xc0\x7a\x82\xe7\x44\x0c\xcb\xb1\x64\x23\x85\x15\x32\x76\x34\x11\x1b\x24\x95\x77\x0a\xe6\x1c\xa6\xf8\x4a\x9a\xa3\xb0\x07\x6d\x31\x34\x58\xac\x17\x8d\x9f\x68\x55\x6b\x86\x45\x6e\xa2\xdc\xee\xee\xdc\x10\x79\xeb\xcf\xa8\x8d\x8c\xf4\x50\x85\xc8\x9d\x9b\xc8\x70\x8b\x86\xde\x01\x30\x4e\xe4\x61\xb6\xdf\x43\xf3\xf4\x33\x39\x92\xfd\xda\x59\x57\x1f\x0e\x87\x99\xd3\xda\x6c\xe8\xed\xb8\x53\x2f\x78\x54\x29\xe9\x8f\xfc\x3a\x75\x15\xff\x9f\xb4\x5c\xda\xe3\xb7\xd9\xbb\x9b\x13\xe8\x85\x74\x17\x03\xdd\x87\x20\x85\xed\xc2\x1a\x2e\x98\x93\x94\x13\x86\xeb\x8c\xf5\xd9\xf9\xa6\x7f\x01\x67\xb6\x9b\xb4\x84\x01\x00\x00")
 
 func klusterletCluster_role_bindingYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -249,7 +249,7 @@ func klusterletImage_pull_secretYaml() (*asset, error) {
 	return a, nil
 }
 
-var _klusterletKlusterletYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8f\x41\x4b\xc4\x30\x10\x85\xef\xf9\x15\x03\x9e\x5b\xf1\xda\x6b\x4f\x22\xae\xa2\xa8\xe7\xb1\x1d\xbb\xb1\xcd\x4c\x98\x4c\x15\x29\xfd\xef\x52\xb3\x6b\xb0\x78\xcc\xfb\xbe\x17\xde\x5c\x40\x2b\xf1\x4b\xfd\x70\x34\x68\x85\x4d\xfd\xeb\x6c\xa2\x09\x4c\xc0\x8e\x04\x77\x91\x18\xda\x69\x4e\x46\x0a\xb7\xc8\x38\x50\x20\x36\x88\x2a\xef\xd4\x99\x73\x18\xfd\x33\x69\xf2\xc2\x0d\x48\x24\x45\x13\xad\x25\x12\x57\x5d\x6e\x55\xe1\xb7\x55\x7b\xb9\xfc\xb8\x72\xa3\xe7\xbe\x81\x9b\x8c\x27\x32\x17\xc8\xb0\x47\xc3\xc6\x01\x30\x06\x6a\x60\x2c\x30\x45\xea\x36\xa0\x34\xf8\x64\x8a\xe6\x85\xaf\x03\x0e\x74\x3f\x4f\xd3\xe3\x06\x61\x59\xa0\x7e\xd8\xe3\x03\x06\x82\x75\x75\x00\x9f\xa2\xe3\x3f\x8d\x97\x73\x5c\xcc\xd3\xe4\xc3\xcf\x86\xcd\xc9\x17\xf7\x6d\xc9\x53\xc4\xee\x64\xf3\xf9\x99\xdd\x72\xd0\xce\x5b\x96\x0a\xfc\x1b\xd4\x4f\x89\xca\x0c\xea\x94\x2c\x73\xff\x37\xcc\xbf\xed\xcc\xb2\x71\xfb\x8d\xb8\x87\x75\xfd\x0e\x00\x00\xff\xff\x93\x21\x74\xc1\xbc\x01\x00\x00")
+var _klusterletKlusterletYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x52\xcb\x4e\xc3\x30\x10\xbc\xe7\x2b\x56\xe2\xdc\x20\xae\xb9\x96\x0b\xa2\x0f\x14\x04\x9c\x4d\xb2\x4d\x4d\x1d\xdb\xd8\x9b\xa2\x28\xea\xbf\xe3\xc4\x49\xdd\x98\x4a\x95\xb8\x79\x77\x66\x76\xc6\x6b\xdf\xc1\x52\xe9\xd6\xf0\x6a\x4f\xee\x24\xc9\xf0\xcf\x86\x94\xb1\x40\x0a\x68\x8f\xb0\xd5\x28\x61\x29\x1a\x4b\x68\x60\xcd\x24\xab\xb0\x46\x49\xa0\x8d\xfa\xc2\x82\x92\x84\x69\xfe\x8e\xc6\x72\x25\x33\x50\x1a\x0d\x73\xea\xd4\x1d\xe4\xa2\xf0\xaa\x45\x7d\x56\xa5\x5c\xdd\x1f\x1f\x92\x03\x97\x65\x06\xcf\x1e\x16\x48\x49\x8d\xc4\x4a\x46\x2c\x4b\x00\x24\xab\x31\x83\xae\x83\x34\x10\x36\xae\x07\xa7\x53\x62\x35\x16\x3d\xc7\x60\xc5\x2d\x39\x2b\xe7\xfa\x54\xbb\xe1\x2f\x8d\x10\xaf\x3d\x38\x08\xf3\x18\x9e\xf4\x00\x3f\xca\x1c\xae\x28\x3e\xa6\x76\x60\x8e\xe9\x37\xe7\x38\xfe\xf2\xe5\x32\xf4\xad\x66\xc5\xc8\x96\x53\x79\x2d\xfa\x05\xaf\xeb\x16\xc0\x77\x90\xbe\x59\x0c\x31\xb0\x30\x48\x1e\xe7\xf3\xa6\x9f\x16\x31\x43\xc6\x7e\x1a\xca\x72\x3e\x3a\x78\x3f\xa2\x16\xaa\x5d\xab\x72\xa4\x97\x43\xbd\xd5\xfd\x5e\xfa\x35\x02\xd4\x0e\x8b\x13\xc7\xaa\x1b\x26\x2b\x55\xad\xf0\x88\xc2\xe3\x97\x2f\xe3\xbe\xd3\x8e\x57\x8d\x2f\xbc\x9f\x18\xc9\xb1\xe7\x7c\x48\xff\x48\xff\x16\xdf\x88\x9b\xa3\x55\x8d\x29\xd0\x4e\x79\x7d\x99\xe3\x77\xc3\xcd\xf0\x49\xbd\x19\xb5\xda\x6d\x26\xff\x0b\x0f\xe8\x15\x99\x8d\x63\x45\x4e\x21\xd7\x2f\x70\x9c\x71\xed\x73\x03\x00\x00")
 
 func klusterletKlusterletYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -269,7 +269,7 @@ func klusterletKlusterletYaml() (*asset, error) {
 	return a, nil
 }
 
-var _klusterletKlusterlet_admin_aggregate_clusterroleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8e\x31\x4f\xec\x30\x10\x84\x7b\xff\x8a\x95\x5f\xfb\x92\xa7\xd7\x21\xb7\x57\x50\x21\x24\x0a\x1a\x74\xc5\x26\x19\x25\xe6\x1c\xaf\xb5\x5e\x1f\x82\x5f\x8f\x92\xe3\xb8\x0a\x2a\x8f\x3c\x33\xdf\xce\x1f\x3a\x48\x79\xd7\x38\x2f\x46\x07\xc9\xa6\x71\x68\x26\x5a\xc9\x84\x6c\x01\x3d\x16\x64\x3a\xa4\x56\x0d\x4a\x0f\x9c\x79\xc6\x8a\x6c\x54\x54\x5e\x31\x9a\x73\x5c\xe2\x33\xb4\x46\xc9\x81\x74\xe0\xb1\xe7\x66\x8b\x68\xfc\x60\x8b\x92\xfb\xd3\x5d\xed\xa3\xfc\x3b\xff\x77\xa7\x98\xa7\x70\x45\x3d\x49\x82\x5b\x61\x3c\xb1\x71\x70\x44\x99\x57\x04\x92\x82\xdc\x8d\x97\x48\xb7\x7e\x5f\x0b\xa7\xcb\x57\x82\x75\x3c\xad\x31\x77\x3c\xcf\x8a\x99\x0d\xd7\xb4\x6e\x40\xa2\xc4\x03\x52\xdd\x80\xf4\xcb\x9a\x5b\xdb\xe4\x02\x0c\xe4\x4d\x1b\xbc\xd3\x96\x50\x83\xeb\x88\x4b\xbc\x57\x69\xa5\x06\x7a\xf1\x52\xa0\x6c\xa2\xfd\x0f\x03\xfb\x28\xfe\xe8\x88\x14\x55\x9a\x8e\xd8\x4b\xb7\xd1\x75\x37\xcf\xd0\x61\x37\x66\x98\xff\x4b\x3e\xc5\xba\xbf\x6f\x6c\xe3\xb2\x89\x51\xc1\x86\x4d\xb5\x32\x7d\xa9\x72\x35\x27\x24\x18\xfc\xf1\x33\x00\x00\xff\xff\xc6\xe5\x63\xd1\xb1\x01\x00\x00")
+var _klusterletKlusterlet_admin_aggregate_clusterroleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7d\x90\x31\x4f\xc4\x30\x0c\x85\xf7\xfe\x0a\x2b\xac\xb4\x88\x0d\x75\x3b\xdd\xc0\x80\x0e\x24\x06\x16\x74\x83\x9b\x5a\x6d\x68\x9a\x44\x8e\x73\x08\x4e\xf7\xdf\x71\x7b\xdc\x89\x89\x29\x4f\xb1\xdf\xf7\x6c\xdf\xc0\x36\xa6\x2f\x76\xc3\x28\xaa\x82\xb0\xeb\x8a\x44\xce\x20\x11\x64\x24\x78\x49\x14\x60\xeb\x4b\x16\x62\xd8\x61\xc0\x81\x66\x0a\x02\x89\xe3\x07\x59\xa9\x2a\x4c\xee\x8d\x38\xbb\x18\x5a\xe0\x0e\x6d\x83\x45\xc6\xc8\xee\x1b\x45\xff\x9a\xe9\x21\x37\x2e\xde\x1d\xee\xab\xc9\x85\xbe\xbd\xa0\x5e\xa3\xa7\x6a\x26\xc1\x1e\x05\xdb\x0a\x20\xe0\x4c\x2d\x98\xe3\x11\x9a\xa7\x73\x8b\x27\xd9\xf4\xb3\x0b\x9b\x61\x60\x1a\x50\xe8\x8f\xf5\x59\xbb\xe1\x74\x32\x6a\xf4\xd8\x91\xcf\x0b\x02\xfe\xc9\xc7\x0b\xa4\x96\x58\xe3\x82\xd5\x30\xe1\x42\xa6\xe2\xe2\x49\xfd\x35\xe8\x26\x8f\x1c\x4b\xca\x2d\xbc\x9b\x98\x88\x51\x0f\xd1\xa8\x08\xb5\x3d\x47\xd7\xf3\xf5\x00\x0a\x35\x7b\x0d\x65\xca\xb1\xb0\xa5\xd5\x34\x5d\x27\xcf\x6b\xf1\x40\xdc\xad\x85\x81\xc4\xdc\x82\xf1\x2e\xaf\xef\x27\x8a\x1d\x17\x61\x99\x74\xa6\x45\x95\xd4\xff\xaa\x74\x29\xf6\xa4\x20\x32\xfb\x1f\x1a\x85\x2d\x67\xa3\x01\x00\x00")
 
 func klusterletKlusterlet_admin_aggregate_clusterroleYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -309,7 +309,7 @@ func klusterletNamespaceYaml() (*asset, error) {
 	return a, nil
 }
 
-var _klusterletOperatorYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x91\x41\x6f\xdb\x3c\x0c\x86\xef\xfe\x15\x44\xbe\x73\xbf\x2c\x5b\x0f\x83\x6e\x43\x0a\x6c\xc5\xb6\x36\x58\x8b\xdd\x19\xf9\x9d\xad\x55\x16\x05\x8a\x09\x90\x15\xf9\xef\x83\x9a\xd4\xb5\xd1\xfe\x80\xe9\x64\xf0\x21\x1f\xbc\xa4\xff\xa3\xb5\xe4\x83\x86\xae\x37\x5a\x4b\x32\x0d\xdb\x9d\x89\x16\x32\x21\xeb\x41\xb7\x19\x89\xd6\x71\x57\x0c\x4a\xdf\x39\x71\x87\x01\xc9\x28\xab\xfc\x86\xb7\xa6\x79\x08\xa9\x75\x74\x85\x1c\xe5\x50\x49\xc3\x39\xfc\x84\x96\x20\xc9\x11\xe7\x5c\x96\xfb\x55\x33\xc0\xb8\x65\x63\xd7\x10\x25\x1e\xe0\xe8\xe1\xa4\x8c\xb0\x73\xa9\x64\xf6\x70\xb4\x78\x7c\xa4\xff\xbf\x8e\xf0\xe6\x99\xd0\xf1\xb8\x68\x88\x22\x6f\x11\x4b\xd5\x50\x95\xcf\x3c\x25\xc3\x57\xa2\xc8\x31\x78\x2e\x8e\x56\x0d\x51\x41\x84\x37\xd1\xd3\xcc\xc0\xe6\xfb\x6f\x13\xc9\x6b\x0d\x91\x61\xc8\x91\x0d\xe7\x91\x49\xf6\xfa\xe2\x6c\xfa\xad\x79\xa2\xe7\x28\x4f\xdf\xd0\x7d\xf0\xf8\xe4\xbd\xec\xd2\xd3\x42\xaf\xda\x89\xbc\x24\xe3\x90\xa0\xa3\xf8\xe2\xad\x43\x9d\x5e\x18\xb8\x83\xa3\x7a\xa9\x1f\xe8\x42\x31\x65\x0b\x92\x6e\x33\x94\x4d\xf4\xba\x62\x3a\x1e\xe7\xfd\x9b\x5d\x8c\x1b\x89\xc1\x1f\x1c\x5d\xff\xba\x11\xdb\x28\x4a\xfd\x5f\xe3\x1e\xda\x4d\xb6\xaa\x01\x16\x4b\x9d\xe8\x2f\xe4\xec\x5f\xcc\x9b\x5e\x02\xbe\x80\x18\xf6\x48\x28\x65\xa3\xb2\xc5\x54\xda\x9b\xe5\xcf\xb0\x69\x89\x28\xb3\xf5\x8e\x96\x3d\x38\x5a\xff\x67\x86\x8a\xef\x51\xaf\xf0\xe5\xfe\x7e\x73\x37\x1f\x12\x35\x47\x1f\x2f\x2f\x3f\x4c\xca\x21\x05\x0b\x1c\xaf\x10\xf9\x70\x07\x2f\xa9\x2d\x8e\xde\x4f\x1a\x32\x34\x48\x3b\xa2\xd5\xbb\x91\x29\xb8\x0d\xff\x4e\xe6\xbf\x01\x00\x00\xff\xff\x42\xd3\x7a\x4c\x98\x03\x00\x00")
+var _klusterletOperatorYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcd\x52\x4d\x6f\xdb\x30\x0c\xbd\xe7\x57\x10\xd9\x39\xcd\xb2\xf5\x30\xf8\x56\xb4\x40\x5b\x6c\xeb\x8c\xb6\xd8\x9d\x91\xd9\x58\xab\x2c\x09\x14\x1d\x20\x0b\xfa\xdf\x47\xc5\xf9\x90\xd7\x00\xbb\x4e\x27\x82\x8f\x7c\x7c\x7c\xd4\x07\xb8\x0e\x71\xc3\x76\xd5\x8a\x46\x5e\xd8\x2e\x7b\x09\x9c\x40\x02\x48\x4b\xf0\x23\x92\x87\x6b\xd7\x27\x21\x86\xef\xe8\x71\x45\x1d\x79\x81\xc8\xe1\x17\x19\x99\x4c\x5e\xad\x6f\x2a\xb8\xa1\xe8\xc2\x26\x23\x13\x8c\xf6\x27\x71\xb2\xc1\x57\x80\x31\xa6\xf9\x7a\x31\xe9\x48\xb0\x41\xc1\x6a\x02\xe0\xb1\xa3\x0a\x5e\x07\x4a\x47\xb2\x4f\xa5\x88\x46\xf3\xd3\xed\x16\x2e\xbe\x1e\xc1\x87\x03\x02\x6f\x6f\x53\xad\x74\xb8\x24\x97\x32\x0d\x64\xf2\x11\x4f\x8a\x64\x32\xc2\xaa\xc5\x1a\x4c\x15\x28\x97\x7d\x29\xe9\x1e\xf7\x90\xb2\x8d\xe7\x8c\x01\x1d\x91\x07\x2e\x72\xe8\x1b\x8d\x94\x35\x91\xd3\x7d\x03\x0f\xb3\x3b\x14\xd3\x7e\x2b\xc4\xbc\x97\x03\x20\xd4\x45\x87\x42\xfb\x96\xc2\x83\xfc\xdc\xa8\xfb\x5c\xbf\x0e\xdd\xaf\xb4\x8b\x89\xd7\xd6\xd0\x95\x31\xa1\xf7\x3b\x63\xde\x95\x03\x18\x3d\x21\x5a\xaf\xfe\x1f\xda\x66\xe7\x0c\x1f\x9e\xed\xf4\x9a\x3b\x97\x2e\x1e\x69\x65\x93\x30\x8a\x9e\x4d\x4f\xae\x41\xe0\xfb\x0c\x0f\xcb\x17\xf5\x75\xef\x5c\x1d\xd4\xac\x4d\x05\xf7\x2f\x0f\x41\x6a\xa6\x94\xef\x7e\xdc\x83\x57\xc5\x56\x59\xc0\x74\xce\x05\xfd\x2c\xec\xf9\xa7\xe3\xa2\x93\xc0\x13\xb0\xdd\xce\x80\xd1\xab\x8c\xe2\x56\x57\x3a\xa0\x94\x95\xbb\xf3\x0e\x65\x2e\x37\x1e\x4f\x77\x4a\xfd\xfd\x1b\x52\xe8\xd9\xd0\x88\x8d\x0f\xc9\xc1\x98\x7f\x54\x9f\x99\xe3\xec\x9a\x3c\xa5\x54\x73\x58\x52\x69\x44\x2b\x12\x6f\x49\xca\x14\x40\x44\x69\x2b\x98\xb7\x84\x4e\xda\xdf\x23\x28\x99\x96\xf2\xe5\xee\x9e\x9f\xeb\xa7\x71\x53\x60\xa9\xe0\xcb\xe5\xe5\xe7\x22\x6d\xbd\x15\x8b\xee\x86\x1c\x6e\x9e\x48\x3f\x42\xa3\x2b\x7c\x2a\x0a\xd4\x76\x1b\x9a\x23\xb4\xf8\x58\xec\x8c\x8d\xfd\x7f\x34\xff\x01\x94\x93\x67\x7f\x94\x04\x00\x00")
 
 func klusterletOperatorYamlBytes() ([]byte, error) {
 	return bindataRead(
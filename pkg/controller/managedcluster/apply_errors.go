@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+//The kinds of failures to apply the klusterlet manifests on the managed cluster
+var (
+	//ErrTransientApply the apply failed on a conflict, a timeout or an unavailable webhook, it is retried
+	ErrTransientApply = errors.New("transient apply failure")
+	//ErrInvalidManifests the apply failed as the manifests are rejected, it won't succeed until they change
+	ErrInvalidManifests = errors.New("invalid import manifests")
+	//ErrConnectionFailed the apply failed as the TLS connection to the managed cluster API server failed
+	ErrConnectionFailed = errors.New("managed cluster connection failed")
+)
+
+const (
+	reasonTransientApplyError    = "TransientApplyError"
+	reasonInvalidImportManifests = "InvalidImportManifests"
+	reasonConnectionFailed       = "ManagedClusterConnectionFailed"
+)
+
+//transientApplyMessages identify the transient failures when the applier doesn't keep the API status,
+//the admission and conversion webhooks calls fail with an internal error
+var transientApplyMessages = []string{
+	"failed calling webhook",
+	"conversion webhook",
+	"the object has been modified",
+}
+
+//isTransientApplyStatus returns true for the API errors which may succeed on a retry
+func isTransientApplyStatus(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsInternalError(err)
+}
+
+//isConnectionError returns true for the TLS failures, the cluster was reached but the connection failed
+func isConnectionError(err error) bool {
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var certificateInvalidErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	var systemRootsErr x509.SystemRootsError
+	var recordHeaderErr tls.RecordHeaderError
+	return errors.As(err, &unknownAuthorityErr) || errors.As(err, &certificateInvalidErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &systemRootsErr) || errors.As(err, &recordHeaderErr)
+}
+
+//classifyApplyError sets the kind of an error of the applier: transient, unreachable, connection failure,
+//invalid manifests, or none for the other errors (forbidden...)
+func classifyApplyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if isUnreachableError(err) {
+		return newImportError(ErrUnreachable, err)
+	}
+	if isConnectionError(err) {
+		return newImportError(ErrConnectionFailed, err)
+	}
+	if isTransientApplyStatus(err) {
+		return newImportError(ErrTransientApply, err)
+	}
+	msg := err.Error()
+	for _, m := range transientApplyMessages {
+		if strings.Contains(msg, m) {
+			return newImportError(ErrTransientApply, err)
+		}
+	}
+	if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) || meta.IsNoMatchError(err) {
+		//A rejected manifest
+		return newImportError(ErrInvalidManifests, err)
+	}
+	return err
+}
+
+func isTransientApplyError(err error) bool {
+	return errors.Is(err, ErrTransientApply)
+}
@@ -0,0 +1,121 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func Test_classifyApplyError(t *testing.T) {
+	gr := schema.GroupResource{Group: "operator.open-cluster-management.io", Resource: "klusterlets"}
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:     "conflict",
+			err:      apierrors.NewConflict(gr, "klusterlet", fmt.Errorf("the object has been modified")),
+			wantKind: ErrTransientApply,
+		},
+		{
+			name:     "server timeout",
+			err:      apierrors.NewServerTimeout(gr, "create", 1),
+			wantKind: ErrTransientApply,
+		},
+		{
+			name: "webhook unavailable",
+			err: apierrors.NewInternalError(
+				fmt.Errorf(`failed calling webhook "klusterlet.example.com": connection refused`)),
+			wantKind: ErrTransientApply,
+		},
+		{
+			name:     "webhook unavailable without status",
+			err:      fmt.Errorf(`Internal error occurred: failed calling webhook "klusterlet.example.com"`),
+			wantKind: ErrTransientApply,
+		},
+		{
+			name:     "unreachable",
+			err:      &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")},
+			wantKind: ErrUnreachable,
+		},
+		{
+			name: "invalid manifest",
+			err: apierrors.NewInvalid(schema.GroupKind{Group: gr.Group, Kind: "Klusterlet"}, "klusterlet",
+				field.ErrorList{field.Required(field.NewPath("spec", "registrationImagePullSpec"), "")}),
+			wantKind: ErrInvalidManifests,
+		},
+		{
+			name:     "unknown kind",
+			err:      &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: gr.Group, Kind: "Klusterlet"}},
+			wantKind: ErrInvalidManifests,
+		},
+		{
+			name: "unreachable wrapped by the client",
+			err: &url.Error{
+				Op:  "Post",
+				URL: "https://10.0.0.1:6443/apis/operator.open-cluster-management.io/v1/klusterlets",
+				Err: &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("i/o timeout")},
+			},
+			wantKind: ErrUnreachable,
+		},
+		{
+			name: "x509 error",
+			err: &url.Error{
+				Op:  "Post",
+				URL: "https://10.0.0.1:6443/apis/operator.open-cluster-management.io/v1/klusterlets",
+				Err: x509.UnknownAuthorityError{},
+			},
+			wantKind: ErrConnectionFailed,
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("unexpected error"),
+		},
+		{
+			name: "forbidden",
+			err:  apierrors.NewForbidden(gr, "klusterlet", fmt.Errorf("denied")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyApplyError(tt.err)
+			if (got == nil) != (tt.err == nil) {
+				t.Fatalf("classifyApplyError() = %v, want an error %t", got, tt.err != nil)
+			}
+			for _, kind := range []error{ErrTransientApply, ErrInvalidManifests, ErrUnreachable, ErrConnectionFailed} {
+				if errors.Is(got, kind) != (kind == tt.wantKind) {
+					t.Errorf("classifyApplyError() = %v, errors.Is(%v) = %t", got, kind, errors.Is(got, kind))
+				}
+			}
+			if tt.err != nil && !errors.Is(got, tt.err) {
+				t.Errorf("classifyApplyError() = %v, the cause %v is lost", got, tt.err)
+			}
+		})
+	}
+}
+
+func Test_importErrorReason_applyErrors(t *testing.T) {
+	if got := importErrorReason(newImportError(ErrTransientApply, fmt.Errorf("conflict"))); got != reasonTransientApplyError {
+		t.Errorf("importErrorReason() = %s, want %s", got, reasonTransientApplyError)
+	}
+	if got := importErrorReason(newImportError(ErrInvalidManifests, fmt.Errorf("invalid"))); got != reasonInvalidImportManifests {
+		t.Errorf("importErrorReason() = %s, want %s", got, reasonInvalidImportManifests)
+	}
+	if got := importErrorReason(newImportError(ErrConnectionFailed, x509.UnknownAuthorityError{})); got != reasonConnectionFailed {
+		t.Errorf("importErrorReason() = %s, want %s", got, reasonConnectionFailed)
+	}
+}
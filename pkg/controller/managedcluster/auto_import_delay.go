@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const reasonWaitingForAutoImportDelay = "WaitingForAutoImportDelay"
+
+//autoImportInitialDelay returns the delay before the first auto-import attempt, 0 imports right away
+func autoImportInitialDelay() time.Duration {
+	return getEnvDuration(autoImportInitialDelayEnvVarName, 0)
+}
+
+//autoImportDelayRemaining returns how long the first attempt of the auto-import-secret still waits: the
+//delay starts when the secret was created or when the cluster went offline, whichever is the latest.
+//The retries are not delayed, the first attempt recorded the retry total on the secret.
+func autoImportDelayRemaining(
+	managedCluster *clusterv1.ManagedCluster,
+	autoImportSecret *corev1.Secret,
+	delay time.Duration,
+	now time.Time,
+) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	if _, attempted := autoImportSecret.GetAnnotations()[autoImportRetryTotalAnnotation]; attempted {
+		return 0
+	}
+	start := autoImportSecret.CreationTimestamp.Time
+	available := meta.FindStatusCondition(managedCluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	if available != nil && available.LastTransitionTime.Time.After(start) {
+		start = available.LastTransitionTime.Time
+	}
+	if remaining := start.Add(delay).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+//setConditionWaitingForAutoImportDelay sets the import condition while the first auto-import attempt waits,
+//the message doesn't change with the remaining time to not patch the status on each reconcile
+func (r *ReconcileManagedCluster) setConditionWaitingForAutoImportDelay(
+	managedCluster *clusterv1.ManagedCluster,
+	delay time.Duration,
+) error {
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:   importConditionType(),
+		Status: metav1.ConditionFalse,
+		Reason: reasonWaitingForAutoImportDelay,
+		Message: fmt.Sprintf("Waiting %s before the first auto-import attempt, the cluster endpoint may not be ready yet",
+			delay.String()),
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_autoImportDelayRemaining(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		created     time.Time
+		offlineAt   time.Time
+		attempted   bool
+		delay       time.Duration
+		wantWaiting time.Duration
+	}{
+		{
+			name:        "disabled",
+			created:     now,
+			delay:       0,
+			wantWaiting: 0,
+		},
+		{
+			name:        "secret just created",
+			created:     now.Add(-time.Minute),
+			delay:       5 * time.Minute,
+			wantWaiting: 4 * time.Minute,
+		},
+		{
+			name:        "delay elapsed",
+			created:     now.Add(-10 * time.Minute),
+			delay:       5 * time.Minute,
+			wantWaiting: 0,
+		},
+		{
+			name:        "cluster just went offline",
+			created:     now.Add(-10 * time.Minute),
+			offlineAt:   now.Add(-2 * time.Minute),
+			delay:       5 * time.Minute,
+			wantWaiting: 3 * time.Minute,
+		},
+		{
+			name:        "retry not delayed",
+			created:     now.Add(-time.Minute),
+			attempted:   true,
+			delay:       5 * time.Minute,
+			wantWaiting: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mycluster",
+				},
+			}
+			if !tt.offlineAt.IsZero() {
+				managedCluster.Status.Conditions = []metav1.Condition{
+					{
+						Type:               clusterv1.ManagedClusterConditionAvailable,
+						Status:             metav1.ConditionUnknown,
+						LastTransitionTime: metav1.NewTime(tt.offlineAt),
+					},
+				}
+			}
+			secret := newTestAutoImportSecret(nil)
+			secret.CreationTimestamp = metav1.NewTime(tt.created)
+			if tt.attempted {
+				secret.Annotations = map[string]string{autoImportRetryTotalAnnotation: "2"}
+			}
+			if got := autoImportDelayRemaining(managedCluster, secret, tt.delay, now); got != tt.wantWaiting {
+				t.Errorf("autoImportDelayRemaining() = %v, want %v", got, tt.wantWaiting)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_setConditionWaitingForAutoImportDelay(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	if err := r.setConditionWaitingForAutoImportDelay(managedCluster, 5*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, got); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(got.Status.Conditions, importConditionType())
+	if c == nil || c.Status != metav1.ConditionFalse || c.Reason != reasonWaitingForAutoImportDelay {
+		t.Errorf("expected the condition %s, got %v", reasonWaitingForAutoImportDelay, got.Status.Conditions)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+)
+
+//autoImportSecretHeadersKey holds extra HTTP headers sent to the managed cluster API server, one
+//"Name: value" per line, for clusters fronted by an auth proxy
+const autoImportSecretHeadersKey = "headers"
+
+const reasonInvalidRequestHeaders = "InvalidRequestHeaders"
+
+var errInvalidRequestHeaders = fmt.Errorf("%w: invalid headers in auto-import-secret", ErrInvalidSecret)
+
+//headerNameRegexp matches an HTTP header field name, a token of RFC 7230
+var headerNameRegexp = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+//getAutoImportSecretHeaders returns the extra headers of the auto-import-secret, nil if it has none
+func getAutoImportSecretHeaders(autoImportSecret *corev1.Secret) (http.Header, error) {
+	data, ok := autoImportSecret.Data[autoImportSecretHeadersKey]
+	if !ok {
+		return nil, nil
+	}
+	headers := http.Header{}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: line %d is not \"Name: value\"", errInvalidRequestHeaders, i+1)
+		}
+		name := strings.TrimSpace(parts[0])
+		if !headerNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("%w: line %d has an invalid header name %q", errInvalidRequestHeaders, i+1, name)
+		}
+		headers.Add(name, strings.TrimSpace(parts[1]))
+	}
+	return headers, nil
+}
+
+//addRequestHeaders wraps the transport of the rest config to add the headers to each request
+func addRequestHeaders(config *rest.Config, headers http.Header) {
+	if len(headers) == 0 {
+		return
+	}
+	wrapped := config.WrapTransport
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrapped != nil {
+			rt = wrapped(rt)
+		}
+		return &headersRoundTripper{headers: headers, delegate: rt}
+	}
+}
+
+//headersRoundTripper adds headers to the requests
+type headersRoundTripper struct {
+	headers  http.Header
+	delegate http.RoundTripper
+}
+
+func (rt *headersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = utilnet.CloneRequest(req)
+	for name, values := range rt.headers {
+		req.Header.Del(name)
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	return rt.delegate.RoundTrip(req)
+}
+
+func (rt *headersRoundTripper) WrappedRoundTripper() http.RoundTripper { return rt.delegate }
@@ -0,0 +1,111 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+type recordingRoundTripper struct {
+	req *http.Request
+}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.req = req
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func Test_getAutoImportSecretHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers string
+		want    http.Header
+		wantErr bool
+	}{
+		{
+			name:    "headers",
+			headers: "X-Proxy-Token: abc\r\n\nx-tenant:team-a\nX-Proxy-Token: def\n",
+			want: http.Header{
+				"X-Proxy-Token": []string{"abc", "def"},
+				"X-Tenant":      []string{"team-a"},
+			},
+		},
+		{
+			name:    "value with a colon",
+			headers: "Authorization: Basic dXNlcjpwYXNz:",
+			want:    http.Header{"Authorization": []string{"Basic dXNlcjpwYXNz:"}},
+		},
+		{
+			name:    "missing separator",
+			headers: "X-Proxy-Token abc",
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			headers: "X Proxy Token: abc",
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			headers: ": abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := newTestAutoImportSecret(nil)
+			secret.Data[autoImportSecretHeadersKey] = []byte(tt.headers)
+			got, err := getAutoImportSecretHeaders(secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getAutoImportSecretHeaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if importErrorReason(err) != reasonInvalidRequestHeaders {
+					t.Errorf("importErrorReason() = %s, want %s", importErrorReason(err), reasonInvalidRequestHeaders)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getAutoImportSecretHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	got, err := getAutoImportSecretHeaders(newTestAutoImportSecret(nil))
+	if err != nil || got != nil {
+		t.Errorf("getAutoImportSecretHeaders() = %v, %v, want no headers", got, err)
+	}
+}
+
+func Test_addRequestHeaders(t *testing.T) {
+	config := &rest.Config{}
+	wrapped := false
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		wrapped = true
+		return rt
+	}
+	addRequestHeaders(config, http.Header{"X-Proxy-Token": []string{"abc"}})
+
+	recorder := &recordingRoundTripper{}
+	req, err := http.NewRequest(http.MethodGet, "https://api.mycluster:6443/version", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Proxy-Token", "overridden")
+	if _, err := config.WrapTransport(recorder).RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if !wrapped {
+		t.Error("expected the existing transport wrapper to be kept")
+	}
+	if v := recorder.req.Header.Values("X-Proxy-Token"); !reflect.DeepEqual(v, []string{"abc"}) {
+		t.Errorf("X-Proxy-Token = %v, want [abc]", v)
+	}
+	if req.Header.Get("X-Proxy-Token") != "overridden" {
+		t.Error("expected the original request not to be modified")
+	}
+}
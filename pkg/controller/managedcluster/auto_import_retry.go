@@ -0,0 +1,118 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"strconv"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//autoImportRetryAnnotation sets on the auto-import-secret the number of retries of the import when the
+//secret has no autoImportRetry key, so the creator of the secret chooses the retry budget of the cluster
+const autoImportRetryAnnotation = "import.open-cluster-management.io/auto-import-retry"
+
+//defaultAutoImportRetry is the number of retries when neither the auto-import-secret nor AUTO_IMPORT_RETRY set it
+const defaultAutoImportRetry = 5
+
+//autoImportRetryTotalAnnotation records on the auto-import-secret the autoImportRetry value of the
+//first attempt, so the remaining retries can be reported against it
+const autoImportRetryTotalAnnotation = "import.open-cluster-management.io/auto-import-retry-total"
+
+const (
+	ConditionAutoImportRetriesRemaining string = "AutoImportRetriesRemaining"
+	reasonAutoImportRetriesRemaining    string = "AutoImportRetriesRemaining"
+	reasonAutoImportRetriesExhausted    string = "AutoImportRetriesExhausted"
+	reasonAutoImportSucceeded           string = "AutoImportSucceeded"
+)
+
+//autoImportRetriesMessageFormat is the message of the retries condition, the import status parses it
+const autoImportRetriesMessageFormat = "%d of %d retries remaining"
+
+//getAutoImportRetry returns the retries left of the auto-import-secret, from its autoImportRetry key,
+//its annotation or the controller default
+func getAutoImportRetry(autoImportSecret *corev1.Secret) (int, error) {
+	if v, ok := autoImportSecret.Data[autoImportRetryName]; ok {
+		return strconv.Atoi(string(v))
+	}
+	if v, ok := autoImportSecret.GetAnnotations()[autoImportRetryAnnotation]; ok {
+		retry, err := strconv.Atoi(v)
+		if err == nil && retry >= 0 {
+			return retry, nil
+		}
+		log.Info("Invalid auto-import retry annotation, using the default", "secret", autoImportSecret.Namespace,
+			"annotation", autoImportRetryAnnotation, "value", v)
+	}
+	return getEnvInt(autoImportRetryEnvVarName, defaultAutoImportRetry), nil
+}
+
+//setAutoImportRetry records the retries left where the auto-import-secret defines them,
+//in the annotation if the secret has no autoImportRetry key
+func setAutoImportRetry(autoImportSecret *corev1.Secret, retry int) {
+	if _, ok := autoImportSecret.Data[autoImportRetryName]; ok {
+		autoImportSecret.Data[autoImportRetryName] = []byte(strconv.Itoa(retry))
+		return
+	}
+	annotations := autoImportSecret.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[autoImportRetryAnnotation] = strconv.Itoa(retry)
+	autoImportSecret.SetAnnotations(annotations)
+}
+
+//getAutoImportRetryTotal returns the number of retries the auto-import-secret started with, the current
+//value is used if it was never recorded or if the autoImportRetry was raised since
+func getAutoImportRetryTotal(autoImportSecret *corev1.Secret, autoImportRetry int) int {
+	total, err := strconv.Atoi(autoImportSecret.GetAnnotations()[autoImportRetryTotalAnnotation])
+	if err != nil || total < autoImportRetry {
+		return autoImportRetry
+	}
+	return total
+}
+
+func setAutoImportRetryTotal(autoImportSecret *corev1.Secret, total int) {
+	annotations := autoImportSecret.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[autoImportRetryTotalAnnotation] = strconv.Itoa(total)
+	autoImportSecret.SetAnnotations(annotations)
+}
+
+//newAutoImportRetriesCondition returns the condition reporting the retries left after a failed attempt,
+//a negative remaining means the retries are exhausted
+func newAutoImportRetriesCondition(remaining, total int) metav1.Condition {
+	if remaining < 0 {
+		return metav1.Condition{
+			Type:    ConditionAutoImportRetriesRemaining,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonAutoImportRetriesExhausted,
+			Message: fmt.Sprintf(autoImportRetriesMessageFormat+", the auto-import gave up", 0, total),
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionAutoImportRetriesRemaining,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonAutoImportRetriesRemaining,
+		Message: fmt.Sprintf(autoImportRetriesMessageFormat, remaining, total),
+	}
+}
+
+//clearConditionAutoImportRetries sets the condition to False once the auto-import succeeded,
+//it is only patched if a failed attempt raised it
+func (r *ReconcileManagedCluster) clearConditionAutoImportRetries(managedCluster *clusterv1.ManagedCluster) error {
+	if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionAutoImportRetriesRemaining) {
+		return nil
+	}
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:    ConditionAutoImportRetriesRemaining,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonAutoImportSucceeded,
+		Message: "The auto-import succeeded",
+	})
+}
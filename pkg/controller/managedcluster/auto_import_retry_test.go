@@ -0,0 +1,244 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileManagedCluster_updateAutoImportRetry(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	tests := []struct {
+		name          string
+		retry         string
+		total         string
+		wantStatus    metav1.ConditionStatus
+		wantReason    string
+		wantMessage   string
+		wantRetry     string
+		wantTotal     string
+		wantDeleted   bool
+		wantErr       bool
+		wantCondition bool
+	}{
+		{
+			name:          "first failed attempt",
+			retry:         "5",
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    reasonAutoImportRetriesRemaining,
+			wantMessage:   "4 of 5 retries remaining",
+			wantRetry:     "4",
+			wantTotal:     "5",
+			wantCondition: true,
+		},
+		{
+			name:          "next failed attempt",
+			retry:         "3",
+			total:         "5",
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    reasonAutoImportRetriesRemaining,
+			wantMessage:   "2 of 5 retries remaining",
+			wantRetry:     "2",
+			wantTotal:     "5",
+			wantCondition: true,
+		},
+		{
+			name:          "retry raised by the user",
+			retry:         "8",
+			total:         "5",
+			wantStatus:    metav1.ConditionTrue,
+			wantReason:    reasonAutoImportRetriesRemaining,
+			wantMessage:   "7 of 8 retries remaining",
+			wantRetry:     "7",
+			wantTotal:     "8",
+			wantCondition: true,
+		},
+		{
+			name:          "last attempt failed",
+			retry:         "0",
+			total:         "5",
+			wantStatus:    metav1.ConditionFalse,
+			wantReason:    reasonAutoImportRetriesExhausted,
+			wantMessage:   "0 of 5 retries remaining, the auto-import gave up",
+			wantDeleted:   true,
+			wantCondition: true,
+		},
+		{
+			name:    "invalid retry",
+			retry:   "many",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mycluster",
+				},
+			}
+			secret := newTestAutoImportSecret(nil)
+			secret.Data[autoImportRetryName] = []byte(tt.retry)
+			if tt.total != "" {
+				secret.Annotations = map[string]string{autoImportRetryTotalAnnotation: tt.total}
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, managedCluster, secret),
+				scheme: testscheme,
+			}
+			err := r.updateAutoImportRetry(managedCluster, secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("updateAutoImportRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			c := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionAutoImportRetriesRemaining)
+			if (c != nil) != tt.wantCondition {
+				t.Fatalf("expected condition %v, got %v", tt.wantCondition, managedCluster.Status.Conditions)
+			}
+			if c != nil && (c.Status != tt.wantStatus || c.Reason != tt.wantReason || c.Message != tt.wantMessage) {
+				t.Errorf("condition = %v, want %s %s %q", c, tt.wantStatus, tt.wantReason, tt.wantMessage)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := &corev1.Secret{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, got)
+			if tt.wantDeleted {
+				if err == nil {
+					t.Error("expected the auto-import-secret to be deleted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got.Data[autoImportRetryName]) != tt.wantRetry ||
+				got.Annotations[autoImportRetryTotalAnnotation] != tt.wantTotal {
+				t.Errorf("secret retry = %s total = %s, want %s and %s", got.Data[autoImportRetryName],
+					got.Annotations[autoImportRetryTotalAnnotation], tt.wantRetry, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_clearConditionAutoImportRetries(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	if err := r.clearConditionAutoImportRetries(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	if meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionAutoImportRetriesRemaining) != nil {
+		t.Error("expected no condition when no attempt failed")
+	}
+	meta.SetStatusCondition(&managedCluster.Status.Conditions, newAutoImportRetriesCondition(2, 5))
+	if err := r.clearConditionAutoImportRetries(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionAutoImportRetriesRemaining)
+	if c == nil || c.Status != metav1.ConditionFalse || c.Reason != reasonAutoImportSucceeded {
+		t.Errorf("expected the condition to be cleared, got %v", c)
+	}
+}
+
+func Test_getAutoImportRetry(t *testing.T) {
+	defer os.Unsetenv(autoImportRetryEnvVarName)
+
+	tests := []struct {
+		name           string
+		retry          string
+		annotation     string
+		env            string
+		want           int
+		wantErr        bool
+		wantAnnotation string
+	}{
+		{
+			name:       "secret key takes precedence",
+			retry:      "2",
+			annotation: "8",
+			want:       2,
+		},
+		{
+			name:    "invalid secret key",
+			retry:   "many",
+			wantErr: true,
+		},
+		{
+			name:           "annotation",
+			annotation:     "8",
+			want:           8,
+			wantAnnotation: "7",
+		},
+		{
+			name:           "invalid annotation",
+			annotation:     "-1",
+			want:           defaultAutoImportRetry,
+			wantAnnotation: "4",
+		},
+		{
+			name:           "controller default",
+			want:           defaultAutoImportRetry,
+			wantAnnotation: "4",
+		},
+		{
+			name:           "controller default from the environment",
+			env:            "3",
+			want:           3,
+			wantAnnotation: "2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(autoImportRetryEnvVarName, tt.env)
+			secret := newTestAutoImportSecret(nil)
+			delete(secret.Data, autoImportRetryName)
+			if tt.retry != "" {
+				secret.Data[autoImportRetryName] = []byte(tt.retry)
+			}
+			if tt.annotation != "" {
+				secret.Annotations = map[string]string{autoImportRetryAnnotation: tt.annotation}
+			}
+			got, err := getAutoImportRetry(secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getAutoImportRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("getAutoImportRetry() = %d, want %d", got, tt.want)
+			}
+			setAutoImportRetry(secret, got-1)
+			if tt.retry != "" {
+				if string(secret.Data[autoImportRetryName]) != strconv.Itoa(got-1) {
+					t.Errorf("secret retry = %s, want %d", secret.Data[autoImportRetryName], got-1)
+				}
+				return
+			}
+			if secret.Annotations[autoImportRetryAnnotation] != tt.wantAnnotation {
+				t.Errorf("annotation = %s, want %s", secret.Annotations[autoImportRetryAnnotation], tt.wantAnnotation)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	//keepAutoImportSecretAnnotation set to "true" on the auto-import-secret keeps the secret once it is consumed
+	keepAutoImportSecretAnnotation = "import.open-cluster-management.io/keep-auto-import-secret"
+	//autoImportSecretConsumedAnnotation is the hash of the data of a kept auto-import-secret once consumed
+	autoImportSecretConsumedAnnotation = "import.open-cluster-management.io/consumed-data-hash"
+)
+
+func keepAutoImportSecret(autoImportSecret *corev1.Secret) bool {
+	v, ok := autoImportSecret.GetAnnotations()[keepAutoImportSecretAnnotation]
+	if !ok {
+		return false
+	}
+	keep, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Info("Invalid annotation value, the auto-import-secret will be deleted",
+			"annotation", keepAutoImportSecretAnnotation, "value", v)
+		return false
+	}
+	return keep
+}
+
+//autoImportSecretDataHash returns the hash of the data of the auto-import-secret,
+//the retry counter is not part of it as it is updated by the controller
+func autoImportSecretDataHash(autoImportSecret *corev1.Secret) string {
+	keys := make([]string, 0, len(autoImportSecret.Data))
+	for k := range autoImportSecret.Data {
+		if k != autoImportRetryName {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%x;", k, autoImportSecret.Data[k])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+//isAutoImportSecretConsumed returns true if the auto-import-secret was kept after the import
+//and its data didn't change since, new credentials trigger a new import
+func isAutoImportSecretConsumed(autoImportSecret *corev1.Secret) bool {
+	if !keepAutoImportSecret(autoImportSecret) {
+		return false
+	}
+	return autoImportSecret.GetAnnotations()[autoImportSecretConsumedAnnotation] == autoImportSecretDataHash(autoImportSecret)
+}
+
+//consumeAutoImportSecret deletes the auto-import-secret so the import is not retried, or
+//marks it as consumed if it must be kept
+func (r *ReconcileManagedCluster) consumeAutoImportSecret(autoImportSecret *corev1.Secret) error {
+	if !keepAutoImportSecret(autoImportSecret) {
+		return r.client.Delete(context.TODO(), autoImportSecret)
+	}
+	log.Info("Keeping the consumed auto-import-secret", "namespace", autoImportSecret.Namespace)
+	annotations := autoImportSecret.GetAnnotations()
+	annotations[autoImportSecretConsumedAnnotation] = autoImportSecretDataHash(autoImportSecret)
+	autoImportSecret.SetAnnotations(annotations)
+	return r.client.Update(context.TODO(), autoImportSecret)
+}
@@ -0,0 +1,46 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+//autoImportSecretNameAnnotation overrides the name of the auto-import-secret of the cluster, when a
+//secret named auto-import-secret is already used for other purposes in the cluster namespace
+const autoImportSecretNameAnnotation = "import.open-cluster-management.io/auto-import-secret-name"
+
+const reasonInvalidAutoImportSecretName = "InvalidAutoImportSecretName"
+
+//defaultAutoImportSecretName returns the name of AUTO_IMPORT_SECRET_NAME, auto-import-secret if not
+//set or invalid
+func defaultAutoImportSecretName() string {
+	v := os.Getenv(autoImportSecretNameEnvVarName)
+	if v == "" {
+		return autoImportSecretName
+	}
+	if errs := validation.IsDNS1123Subdomain(v); len(errs) != 0 {
+		log.Info("Invalid secret name, using default", "env", autoImportSecretNameEnvVarName, "value", v,
+			"default", autoImportSecretName)
+		return autoImportSecretName
+	}
+	return v
+}
+
+//getAutoImportSecretName returns the name of the auto-import-secret of the cluster, the annotation
+//takes precedence over AUTO_IMPORT_SECRET_NAME
+func getAutoImportSecretName(managedCluster *clusterv1.ManagedCluster) (string, error) {
+	name, ok := managedCluster.GetAnnotations()[autoImportSecretNameAnnotation]
+	if !ok {
+		return defaultAutoImportSecretName(), nil
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return "", newConfigError(reasonInvalidAutoImportSecretName, "invalid auto-import-secret name: %s %q: %s",
+			autoImportSecretNameAnnotation, name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
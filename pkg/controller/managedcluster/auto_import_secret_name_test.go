@@ -0,0 +1,131 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getAutoImportSecretName(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		annotations map[string]string
+		want        string
+		wantInvalid bool
+	}{
+		{
+			name: "default",
+			want: autoImportSecretName,
+		},
+		{
+			name: "global",
+			env:  "cluster-import-credentials",
+			want: "cluster-import-credentials",
+		},
+		{
+			name: "invalid global",
+			env:  "Not_A_Name",
+			want: autoImportSecretName,
+		},
+		{
+			name:        "per cluster",
+			env:         "cluster-import-credentials",
+			annotations: map[string]string{autoImportSecretNameAnnotation: "mycluster-import-credentials"},
+			want:        "mycluster-import-credentials",
+		},
+		{
+			name:        "invalid per cluster",
+			annotations: map[string]string{autoImportSecretNameAnnotation: "Not_A_Name"},
+			wantInvalid: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(autoImportSecretNameEnvVarName, tt.env)
+			defer os.Unsetenv(autoImportSecretNameEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mycluster",
+					Annotations: tt.annotations,
+				},
+			}
+			got, err := getAutoImportSecretName(managedCluster)
+			if (configErrorReason(err) == reasonInvalidAutoImportSecretName) != tt.wantInvalid {
+				t.Fatalf("getAutoImportSecretName() error = %v, wantInvalid %v", err, tt.wantInvalid)
+			}
+			if got != tt.want {
+				t.Errorf("getAutoImportSecretName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_toBeImported_secretName(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(hivev1.SchemeGroupVersion, &hivev1.ClusterDeployment{})
+
+	//A secret named auto-import-secret used for other purposes
+	other := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      autoImportSecretName,
+			Namespace: "mycluster",
+		},
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mycluster-import-credentials",
+			Namespace: "mycluster",
+		},
+		Data: map[string][]byte{
+			"autoImportRetry": []byte("5"),
+			"token":           []byte("token"),
+			"server":          []byte("https://api.mycluster.example.com:6443"),
+		},
+	}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name: "default name",
+			want: autoImportSecretName,
+		},
+		{
+			name:        "annotation",
+			annotations: map[string]string{autoImportSecretNameAnnotation: credentials.Name},
+			want:        credentials.Name,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mycluster",
+					Annotations: tt.annotations,
+				},
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, managedCluster, other, credentials),
+				scheme: testscheme,
+			}
+			autoImportSecret, _, toImport, err := r.toBeImported(managedCluster)
+			if err != nil {
+				t.Fatalf("toBeImported() error = %v", err)
+			}
+			if !toImport || autoImportSecret == nil || autoImportSecret.Name != tt.want {
+				t.Errorf("expected the secret %s to be imported, got %v", tt.want, autoImportSecret)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestAutoImportSecret(annotations map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        autoImportSecretName,
+			Namespace:   "mycluster",
+			Annotations: annotations,
+		},
+		Data: map[string][]byte{
+			autoImportRetryName: []byte("2"),
+			"token":             []byte("mytoken"),
+			"server":            []byte("https://api.mycluster:6443"),
+		},
+	}
+}
+
+func Test_consumeAutoImportSecret(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantDeleted  bool
+		wantConsumed bool
+	}{
+		{
+			name:        "deleted by default",
+			wantDeleted: true,
+		},
+		{
+			name:         "kept",
+			annotations:  map[string]string{keepAutoImportSecretAnnotation: "true"},
+			wantConsumed: true,
+		},
+		{
+			name:        "invalid annotation",
+			annotations: map[string]string{keepAutoImportSecretAnnotation: "always"},
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := newTestAutoImportSecret(tt.annotations)
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(scheme.Scheme, secret),
+				scheme: scheme.Scheme,
+			}
+			if err := r.consumeAutoImportSecret(secret); err != nil {
+				t.Errorf("consumeAutoImportSecret() error = %v", err)
+				return
+			}
+			got := &corev1.Secret{}
+			err := r.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, got)
+			if tt.wantDeleted {
+				if !errors.IsNotFound(err) {
+					t.Errorf("expected the auto-import-secret to be deleted, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("expected the auto-import-secret to be kept, got %v", err)
+				return
+			}
+			if isAutoImportSecretConsumed(got) != tt.wantConsumed {
+				t.Errorf("isAutoImportSecretConsumed() = %v, want %v", !tt.wantConsumed, tt.wantConsumed)
+			}
+		})
+	}
+}
+
+func Test_isAutoImportSecretConsumed(t *testing.T) {
+	consumed := newTestAutoImportSecret(map[string]string{keepAutoImportSecretAnnotation: "true"})
+	consumed.Annotations[autoImportSecretConsumedAnnotation] = autoImportSecretDataHash(consumed)
+	if !isAutoImportSecretConsumed(consumed) {
+		t.Errorf("expected the kept auto-import-secret to be consumed")
+	}
+
+	resynced := consumed.DeepCopy()
+	resynced.Data[autoImportRetryName] = []byte("5")
+	if !isAutoImportSecretConsumed(resynced) {
+		t.Errorf("expected a reset of the retry counter to not trigger a new import")
+	}
+
+	newCredentials := consumed.DeepCopy()
+	newCredentials.Data["token"] = []byte("newtoken")
+	if isAutoImportSecretConsumed(newCredentials) {
+		t.Errorf("expected new credentials to trigger a new import")
+	}
+
+	notKept := consumed.DeepCopy()
+	delete(notKept.Annotations, keepAutoImportSecretAnnotation)
+	if isAutoImportSecretConsumed(notKept) {
+		t.Errorf("expected an auto-import-secret not kept to never be consumed")
+	}
+}
+
+func TestReconcileManagedCluster_toBeImported_consumed(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(hivev1.SchemeGroupVersion, &hivev1.ClusterDeployment{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	secret := newTestAutoImportSecret(map[string]string{keepAutoImportSecretAnnotation: "true"})
+	secret.Annotations[autoImportSecretConsumedAnnotation] = autoImportSecretDataHash(secret)
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster, secret),
+		scheme: testscheme,
+	}
+	_, _, toImport, err := r.toBeImported(managedCluster)
+	if err != nil {
+		t.Errorf("toBeImported() error = %v", err)
+	}
+	if toImport {
+		t.Errorf("expected a consumed auto-import-secret to not be imported again")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+//availabilityOverrideAnnotation forces the import path of a ManagedCluster regardless of its Available
+//condition: "offline" uses the import secret and the auto-import, "online" the klusterlet manifestworks.
+//It is only honored when the controller allows it, to test both import paths.
+const availabilityOverrideAnnotation = "import.open-cluster-management.io/availability-override"
+
+const (
+	availabilityOverrideOffline = "offline"
+	availabilityOverrideOnline  = "online"
+)
+
+//availabilityOverride returns whether the cluster is forced offline and true if the cluster availability
+//is overridden
+func availabilityOverride(managedCluster *clusterv1.ManagedCluster) (bool, bool) {
+	v, ok := managedCluster.GetAnnotations()[availabilityOverrideAnnotation]
+	if !ok {
+		return false, false
+	}
+	if !getEnvBool(allowAvailabilityOverrideEnvVarName, false) {
+		logSubsystemImport.V(4).Infof("The annotation %s of %s is ignored as %s is not enabled",
+			availabilityOverrideAnnotation, managedCluster.Name, allowAvailabilityOverrideEnvVarName)
+		return false, false
+	}
+	switch v {
+	case availabilityOverrideOffline:
+		return true, true
+	case availabilityOverrideOnline:
+		return false, true
+	}
+	logSubsystemImport.V(4).Infof("Invalid value %q for the annotation %s of %s, it is ignored",
+		v, availabilityOverrideAnnotation, managedCluster.Name)
+	return false, false
+}
@@ -0,0 +1,79 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_checkOffLine_availabilityOverride(t *testing.T) {
+	newManagedCluster := func(override string, available metav1.ConditionStatus) *clusterv1.ManagedCluster {
+		mc := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1",
+			},
+			Status: clusterv1.ManagedClusterStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   clusterv1.ManagedClusterConditionAvailable,
+						Status: available,
+					},
+				},
+			},
+		}
+		if override != "" {
+			mc.SetAnnotations(map[string]string{availabilityOverrideAnnotation: override})
+		}
+		return mc
+	}
+
+	tests := []struct {
+		name           string
+		allow          string
+		managedCluster *clusterv1.ManagedCluster
+		want           bool
+	}{
+		{
+			name:           "forced offline",
+			allow:          "true",
+			managedCluster: newManagedCluster(availabilityOverrideOffline, metav1.ConditionTrue),
+			want:           true,
+		},
+		{
+			name:           "forced online",
+			allow:          "true",
+			managedCluster: newManagedCluster(availabilityOverrideOnline, metav1.ConditionFalse),
+			want:           false,
+		},
+		{
+			name:           "invalid override",
+			allow:          "true",
+			managedCluster: newManagedCluster("maybe", metav1.ConditionTrue),
+			want:           false,
+		},
+		{
+			name:           "override not allowed",
+			managedCluster: newManagedCluster(availabilityOverrideOffline, metav1.ConditionTrue),
+			want:           false,
+		},
+		{
+			name:           "no override",
+			allow:          "true",
+			managedCluster: newManagedCluster("", metav1.ConditionFalse),
+			want:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(allowAvailabilityOverrideEnvVarName, tt.allow)
+			defer os.Unsetenv(allowAvailabilityOverrideEnvVarName)
+			if got := checkOffLine(tt.managedCluster); got != tt.want {
+				t.Errorf("checkOffLine() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//defaultBootstrapCAKey is the key of the CA bundle in the referenced secret or configmap,
+//as in the kube-root-ca.crt configmaps
+const defaultBootstrapCAKey = "ca.crt"
+
+//parseNamespacedName parses a <namespace>/<name> reference
+func parseNamespacedName(v string) (types.NamespacedName, error) {
+	parts := strings.Split(v, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid reference %q, expected <namespace>/<name>", v)
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+//getBootstrapCAFromSource returns the CA bundle of the bootstrap kubeconfig from the secret of
+//BOOTSTRAP_CA_SECRET or the configmap of BOOTSTRAP_CA_CONFIGMAP, or nil if none is set
+func getBootstrapCAFromSource(c client.Client) ([]byte, error) {
+	key := bootstrapCAKey()
+
+	var ca []byte
+	var source string
+	switch {
+	case os.Getenv(bootstrapCASecretEnvVarName) != "":
+		source = os.Getenv(bootstrapCASecretEnvVarName)
+		nsn, err := parseNamespacedName(source)
+		if err != nil {
+			return nil, err
+		}
+		secret := &corev1.Secret{}
+		if err := c.Get(context.TODO(), nsn, secret); err != nil {
+			return nil, err
+		}
+		ca = secret.Data[key]
+	case os.Getenv(bootstrapCAConfigMapEnvVarName) != "":
+		source = os.Getenv(bootstrapCAConfigMapEnvVarName)
+		nsn, err := parseNamespacedName(source)
+		if err != nil {
+			return nil, err
+		}
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(context.TODO(), nsn, configMap); err != nil {
+			return nil, err
+		}
+		ca = []byte(configMap.Data[key])
+	default:
+		return nil, nil
+	}
+
+	if err := validateCABundle(ca); err != nil {
+		return nil, fmt.Errorf("invalid bootstrap CA %s in %s: %v", key, source, err)
+	}
+	return ca, nil
+}
+
+//validateCABundle checks that the bundle holds only PEM encoded certificates, at least one
+func validateCABundle(ca []byte) error {
+	if len(ca) == 0 {
+		return fmt.Errorf("no CA bundle")
+	}
+	count := 0
+	for rest := ca; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("unexpected PEM block %s", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return err
+		}
+		count++
+	}
+	if count == 0 {
+		return fmt.Errorf("no PEM encoded certificate")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getBootstrapCAFromSource(t *testing.T) {
+	ca, _ := newTestClientCertificate(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "router-ca",
+			Namespace: "openshift-ingress-operator",
+		},
+		Data: map[string][]byte{
+			"tls.crt": ca,
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-root-ca.crt",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"ca.crt": string(ca),
+		},
+	}
+	invalid := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"ca.crt": "not a certificate",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		objects []runtime.Object
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "no source",
+			want: nil,
+		},
+		{
+			name: "secret",
+			env: map[string]string{
+				bootstrapCASecretEnvVarName: "openshift-ingress-operator/router-ca",
+				bootstrapCAKeyEnvVarName:    "tls.crt",
+			},
+			objects: []runtime.Object{secret},
+			want:    ca,
+		},
+		{
+			name:    "configmap",
+			env:     map[string]string{bootstrapCAConfigMapEnvVarName: "default/kube-root-ca.crt"},
+			objects: []runtime.Object{configMap},
+			want:    ca,
+		},
+		{
+			name:    "missing key",
+			env:     map[string]string{bootstrapCASecretEnvVarName: "openshift-ingress-operator/router-ca"},
+			objects: []runtime.Object{secret},
+			wantErr: true,
+		},
+		{
+			name:    "missing configmap",
+			env:     map[string]string{bootstrapCAConfigMapEnvVarName: "default/kube-root-ca.crt"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid reference",
+			env:     map[string]string{bootstrapCAConfigMapEnvVarName: "kube-root-ca.crt"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid certificate",
+			env:     map[string]string{bootstrapCAConfigMapEnvVarName: "default/invalid"},
+			objects: []runtime.Object{invalid},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			got, err := getBootstrapCAFromSource(fake.NewFakeClient(tt.objects...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getBootstrapCAFromSource() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("getBootstrapCAFromSource() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//bootstrapKubeconfigSecretAnnotation references a secret in the cluster namespace containing
+//a bootstrap kubeconfig to use verbatim instead of the generated one
+const bootstrapKubeconfigSecretAnnotation = "import.open-cluster-management.io/bootstrap-kubeconfig-secret"
+
+/* #nosec */
+const bootstrapKubeconfigSecretKey = "kubeconfig"
+
+//The names of the cluster and the context of the generated bootstrap kubeconfig, when not configured
+const (
+	defaultBootstrapKubeconfigClusterName = "default-cluster"
+	defaultBootstrapKubeconfigContextName = "default-context"
+)
+
+//bootstrapKubeconfigClusterName returns the name of the cluster of the generated bootstrap kubeconfig,
+//for the tooling of the managed clusters expecting a given name
+func bootstrapKubeconfigClusterName() string {
+	if v := strings.TrimSpace(os.Getenv(bootstrapKubeconfigClusterNameEnvVarName)); v != "" {
+		return v
+	}
+	return defaultBootstrapKubeconfigClusterName
+}
+
+//bootstrapKubeconfigContextName returns the name of the context of the generated bootstrap kubeconfig
+func bootstrapKubeconfigContextName() string {
+	if v := strings.TrimSpace(os.Getenv(bootstrapKubeconfigContextNameEnvVarName)); v != "" {
+		return v
+	}
+	return defaultBootstrapKubeconfigContextName
+}
+
+//getBootstrapKubeconfigData returns the user supplied bootstrap kubeconfig if the ManagedCluster
+//references one, otherwise the kubeconfig is generated from the bootstrap service account token
+func getBootstrapKubeconfigData(
+	client client.Client,
+	kubeClient kubernetes.Interface,
+	managedCluster *clusterv1.ManagedCluster,
+) ([]byte, error) {
+	if secretName, ok := managedCluster.GetAnnotations()[bootstrapKubeconfigSecretAnnotation]; ok {
+		log.Info("Use the supplied bootstrap kubeconfig", "managedcluster", managedCluster.Name, "secret", secretName)
+		return getSuppliedBootstrapKubeconfig(client, managedCluster, secretName)
+	}
+
+	bootStrapSecret, err := getBootstrapSecret(client, managedCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if audiences := getBootstrapTokenAudiences(managedCluster); len(audiences) > 0 {
+		bootStrapSecret, err = withAudienceBoundToken(client, kubeClient, managedCluster, bootStrapSecret, audiences)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info("createKubeconfigData for bootstrapSecret", "secret", bootStrapSecret.Name)
+	return createKubeconfigData(client, bootStrapSecret)
+}
+
+func getSuppliedBootstrapKubeconfig(client client.Client, managedCluster *clusterv1.ManagedCluster, secretName string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := client.Get(context.TODO(), types.NamespacedName{
+		Name:      secretName,
+		Namespace: managedCluster.Name,
+	}, secret); err != nil {
+		return nil, err
+	}
+	data, ok := secret.Data[bootstrapKubeconfigSecretKey]
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("the secret %s/%s has no %s", secret.Namespace, secret.Name, bootstrapKubeconfigSecretKey)
+	}
+	if err := validateBootstrapKubeconfig(data); err != nil {
+		return nil, fmt.Errorf("the secret %s/%s contains an invalid kubeconfig: %v", secret.Namespace, secret.Name, err)
+	}
+	return data, nil
+}
+
+//validateBootstrapKubeconfig checks the kubeconfig can be loaded and its current context is usable
+func validateBootstrapKubeconfig(data []byte) error {
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return err
+	}
+	if config.CurrentContext == "" {
+		return fmt.Errorf("no current context")
+	}
+	return clientcmd.Validate(*config)
+}
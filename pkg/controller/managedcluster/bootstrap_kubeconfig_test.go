@@ -0,0 +1,204 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	ocinfrav1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getBootstrapKubeconfigData_supplied(t *testing.T) {
+	validKubeconfig, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{"hub": {
+			Server: "https://hub.example.com:6443",
+		}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"bootstrap": {
+			Token: "fake-token",
+		}},
+		Contexts: map[string]*clientcmdapi.Context{"bootstrap": {
+			Cluster:  "hub",
+			AuthInfo: "bootstrap",
+		}},
+		CurrentContext: "bootstrap",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noContextKubeconfig, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{"hub": {
+			Server: "https://hub.example.com:6443",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+			Annotations: map[string]string{
+				bootstrapKubeconfigSecretAnnotation: "my-bootstrap",
+			},
+		},
+	}
+	newSecret := func(data []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-bootstrap",
+				Namespace: "mycluster",
+			},
+			Data: map[string][]byte{
+				bootstrapKubeconfigSecretKey: data,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		client  client.Client
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name:    "secret missing",
+			client:  fake.NewFakeClientWithScheme(scheme.Scheme),
+			wantErr: true,
+		},
+		{
+			name:    "kubeconfig empty",
+			client:  fake.NewFakeClientWithScheme(scheme.Scheme, newSecret(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "kubeconfig not parsable",
+			client:  fake.NewFakeClientWithScheme(scheme.Scheme, newSecret([]byte("not a kubeconfig"))),
+			wantErr: true,
+		},
+		{
+			name:    "kubeconfig without context",
+			client:  fake.NewFakeClientWithScheme(scheme.Scheme, newSecret(noContextKubeconfig)),
+			wantErr: true,
+		},
+		{
+			name:   "valid kubeconfig",
+			client: fake.NewFakeClientWithScheme(scheme.Scheme, newSecret(validKubeconfig)),
+			want:   validKubeconfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getBootstrapKubeconfigData(tt.client, nil, managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getBootstrapKubeconfigData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getBootstrapKubeconfigData() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_createKubeconfigData_names(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(ocinfrav1.SchemeGroupVersion, &ocinfrav1.Infrastructure{}, &ocinfrav1.APIServer{})
+
+	infraConfig := &ocinfrav1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Status: ocinfrav1.InfrastructureStatus{
+			APIServerURL: "https://my-dns-name.com:6443",
+		},
+	}
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sa-token",
+			Namespace: "test-namespace",
+		},
+		Data: map[string][]byte{
+			"token":  []byte("fake-token"),
+			"ca.crt": []byte("default-cert-data"),
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	tests := []struct {
+		name        string
+		clusterName string
+		contextName string
+		wantCluster string
+		wantContext string
+	}{
+		{
+			name:        "default names",
+			wantCluster: "default-cluster",
+			wantContext: "default-context",
+		},
+		{
+			name:        "configured names",
+			clusterName: "hub",
+			contextName: "hub-bootstrap",
+			wantCluster: "hub",
+			wantContext: "hub-bootstrap",
+		},
+		{
+			name:        "blank names",
+			clusterName: " ",
+			contextName: " ",
+			wantCluster: "default-cluster",
+			wantContext: "default-context",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(bootstrapKubeconfigClusterNameEnvVarName, tt.clusterName)
+			defer os.Unsetenv(bootstrapKubeconfigClusterNameEnvVarName)
+			os.Setenv(bootstrapKubeconfigContextNameEnvVarName, tt.contextName)
+			defer os.Unsetenv(bootstrapKubeconfigContextNameEnvVarName)
+
+			c := fake.NewFakeClientWithScheme(s, infraConfig)
+			data, err := createKubeconfigData(c, tokenSecret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := validateBootstrapKubeconfig(data); err != nil {
+				t.Fatalf("invalid bootstrap kubeconfig: %v", err)
+			}
+			config, err := clientcmd.Load(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.CurrentContext != tt.wantContext {
+				t.Errorf("current context = %q, want %q", config.CurrentContext, tt.wantContext)
+			}
+			context, ok := config.Contexts[tt.wantContext]
+			if !ok {
+				t.Fatalf("context %q not found in %v", tt.wantContext, config.Contexts)
+			}
+			if context.Cluster != tt.wantCluster {
+				t.Errorf("context cluster = %q, want %q", context.Cluster, tt.wantCluster)
+			}
+			cluster, ok := config.Clusters[tt.wantCluster]
+			if !ok {
+				t.Fatalf("cluster %q not found in %v", tt.wantCluster, config.Clusters)
+			}
+			if cluster.Server != "https://my-dns-name.com:6443" {
+				t.Errorf("server = %q, want https://my-dns-name.com:6443", cluster.Server)
+			}
+			if len(config.Clusters) != 1 || len(config.Contexts) != 1 {
+				t.Errorf("expected a single cluster and context, got %v and %v", config.Clusters, config.Contexts)
+			}
+		})
+	}
+}
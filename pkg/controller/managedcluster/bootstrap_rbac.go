@@ -0,0 +1,71 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+const bootstrapClusterRoleBindingNamePrefix = "system:open-cluster-management:managedcluster:bootstrap:"
+
+const (
+	ConditionBootstrapRBACDriftCorrected string = "BootstrapRBACDriftCorrected"
+	reasonBootstrapRBACRepaired          string = "BootstrapRBACRepaired"
+)
+
+func bootstrapClusterRoleBindingName(managedCluster *clusterv1.ManagedCluster) string {
+	return bootstrapClusterRoleBindingNamePrefix + managedCluster.Name
+}
+
+//checkBootstrapRBACDrift returns a description of the drift if the bootstrap ClusterRoleBinding
+//doesn't bind the bootstrap ClusterRole to the bootstrap service account anymore.
+//A missing ClusterRoleBinding is not a drift as the applier will create it.
+func checkBootstrapRBACDrift(c client.Client, managedCluster *clusterv1.ManagedCluster) (string, error) {
+	saNsN, err := bootstrapServiceAccountNsN(managedCluster)
+	if err != nil {
+		return "", err
+	}
+	name := bootstrapClusterRoleBindingName(managedCluster)
+	crb := &rbacv1.ClusterRoleBinding{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name}, crb); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if crb.RoleRef.Kind != "ClusterRole" || crb.RoleRef.Name != name {
+		return fmt.Sprintf("ClusterRoleBinding %s references %s %s instead of ClusterRole %s",
+			name, crb.RoleRef.Kind, crb.RoleRef.Name, name), nil
+	}
+	for _, s := range crb.Subjects {
+		if s.Kind == rbacv1.ServiceAccountKind && s.Name == saNsN.Name && s.Namespace == saNsN.Namespace {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("ClusterRoleBinding %s doesn't reference the service account %s/%s",
+		name, saNsN.Namespace, saNsN.Name), nil
+}
+
+//repairBootstrapRBAC deletes a tampered bootstrap ClusterRoleBinding so the applier recreates it.
+//As the roleRef is immutable, deleting is the only way to fix a binding pointing to another role.
+func repairBootstrapRBAC(c client.Client, managedCluster *clusterv1.ManagedCluster) (string, error) {
+	drift, err := checkBootstrapRBACDrift(c, managedCluster)
+	if err != nil || drift == "" {
+		return "", err
+	}
+	log.Info("Bootstrap RBAC drift detected", "managedcluster", managedCluster.Name, "drift", drift)
+	crb := &rbacv1.ClusterRoleBinding{}
+	crb.SetName(bootstrapClusterRoleBindingName(managedCluster))
+	if err := c.Delete(context.TODO(), crb); err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	return drift, nil
+}
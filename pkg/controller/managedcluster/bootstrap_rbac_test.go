@@ -0,0 +1,96 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_repairBootstrapRBAC(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	name := bootstrapClusterRoleBindingName(managedCluster)
+	newCRB := func(roleName string, subjects ...rbacv1.Subject) *rbacv1.ClusterRoleBinding {
+		return &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     roleName,
+			},
+			Subjects: subjects,
+		}
+	}
+	saSubject := rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      "mycluster" + bootstrapServiceAccountNamePostfix,
+		Namespace: "mycluster",
+	}
+	otherSubject := rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      "intruder",
+		Namespace: "mycluster",
+	}
+
+	tests := []struct {
+		name        string
+		client      client.Client
+		wantDrift   bool
+		wantDeleted bool
+	}{
+		{
+			name:      "binding missing",
+			client:    fake.NewFakeClientWithScheme(scheme.Scheme),
+			wantDrift: false,
+		},
+		{
+			name:      "binding correct",
+			client:    fake.NewFakeClientWithScheme(scheme.Scheme, newCRB(name, otherSubject, saSubject)),
+			wantDrift: false,
+		},
+		{
+			name:        "binding without the bootstrap sa",
+			client:      fake.NewFakeClientWithScheme(scheme.Scheme, newCRB(name, otherSubject)),
+			wantDrift:   true,
+			wantDeleted: true,
+		},
+		{
+			name:        "binding to another role",
+			client:      fake.NewFakeClientWithScheme(scheme.Scheme, newCRB("cluster-admin", saSubject)),
+			wantDrift:   true,
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift, err := repairBootstrapRBAC(tt.client, managedCluster)
+			if err != nil {
+				t.Errorf("repairBootstrapRBAC() error = %v", err)
+				return
+			}
+			if (drift != "") != tt.wantDrift {
+				t.Errorf("repairBootstrapRBAC() drift = %q, wantDrift %v", drift, tt.wantDrift)
+			}
+			crb := &rbacv1.ClusterRoleBinding{}
+			err = tt.client.Get(context.TODO(), types.NamespacedName{Name: name}, crb)
+			if tt.wantDeleted && !errors.IsNotFound(err) {
+				t.Errorf("expected the ClusterRoleBinding to be deleted, got %v", err)
+			}
+		})
+	}
+}
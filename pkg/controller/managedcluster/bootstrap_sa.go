@@ -6,17 +6,40 @@ package managedcluster
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const bootstrapServiceAccountNamePostfix = "-bootstrap-sa"
 
+//bootstrapServiceAccountAnnotation names an existing service account of the cluster namespace to use
+//for the bootstrap instead of creating {cluster_name}-bootstrap-sa
+const bootstrapServiceAccountAnnotation = "import.open-cluster-management.io/bootstrap-service-account"
+
+const reasonInvalidBootstrapServiceAccount = "InvalidBootstrapServiceAccount"
+
+var errInvalidBootstrapServiceAccount = errors.New("invalid bootstrap service account")
+
+//isBootstrapServiceAccountSpecified returns true if the bootstrap service account is provisioned by the user
+func isBootstrapServiceAccountSpecified(managedCluster *clusterv1.ManagedCluster) bool {
+	return managedCluster.GetAnnotations()[bootstrapServiceAccountAnnotation] != ""
+}
+
+//bootstrapServiceAccountName returns the name of the service account used for the bootstrap
+func bootstrapServiceAccountName(managedCluster *clusterv1.ManagedCluster) string {
+	if name := managedCluster.GetAnnotations()[bootstrapServiceAccountAnnotation]; name != "" {
+		return name
+	}
+	return managedCluster.Name + bootstrapServiceAccountNamePostfix
+}
+
 func bootstrapServiceAccountNsN(managedCluster *clusterv1.ManagedCluster) (types.NamespacedName, error) {
 	if managedCluster == nil {
 		return types.NamespacedName{}, fmt.Errorf("managedCluster is nil")
@@ -24,11 +47,40 @@ func bootstrapServiceAccountNsN(managedCluster *clusterv1.ManagedCluster) (types
 		return types.NamespacedName{}, fmt.Errorf("managedCluster.Name is blank")
 	}
 	return types.NamespacedName{
-		Name:      managedCluster.Name + bootstrapServiceAccountNamePostfix,
+		Name:      bootstrapServiceAccountName(managedCluster),
 		Namespace: managedCluster.Name,
 	}, nil
 }
 
+//validateBootstrapServiceAccount checks the service account named by the bootstrapServiceAccountAnnotation
+//exists and, unless the token is requested for audiences, has a token secret
+func validateBootstrapServiceAccount(c client.Client, managedCluster *clusterv1.ManagedCluster) error {
+	saNsN, err := bootstrapServiceAccountNsN(managedCluster)
+	if err != nil {
+		return err
+	}
+	if err := c.Get(context.TODO(), saNsN, &corev1.ServiceAccount{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: the service account %s/%s doesn't exist",
+				errInvalidBootstrapServiceAccount, saNsN.Namespace, saNsN.Name)
+		}
+		return err
+	}
+	if len(getBootstrapTokenAudiences(managedCluster)) != 0 {
+		return nil
+	}
+	secret, err := getBootstrapSecret(c, managedCluster)
+	if err != nil || len(secret.Data["token"]) == 0 {
+		return fmt.Errorf("%w: the service account %s/%s has no token",
+			errInvalidBootstrapServiceAccount, saNsN.Namespace, saNsN.Name)
+	}
+	return nil
+}
+
+func isInvalidBootstrapServiceAccount(err error) bool {
+	return errors.Is(err, errInvalidBootstrapServiceAccount)
+}
+
 func getBootstrapSecret(
 	client client.Client,
 	managedCluster *clusterv1.ManagedCluster) (*corev1.Secret, error) {
@@ -63,7 +115,7 @@ func getBootstrapSecret(
 	}
 	if secret == nil {
 		return nil, fmt.Errorf("secret with prefix %s amd type %s not found in service account %s/%s",
-			managedCluster.Name+bootstrapServiceAccountNamePostfix,
+			saNsN.Name,
 			corev1.SecretTypeServiceAccountToken,
 			saNsN.Name,
 			managedCluster.Name)
@@ -9,8 +9,12 @@ import (
 	"testing"
 
 	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_bootstrapServiceAccountNsN(t *testing.T) {
@@ -62,3 +66,64 @@ func Test_bootstrapServiceAccountNsN(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateBootstrapServiceAccount(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mycluster",
+			Annotations: map[string]string{bootstrapServiceAccountAnnotation: "governed-sa"},
+		},
+	}
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "governed-sa", Namespace: "mycluster"},
+		Secrets:    []corev1.ObjectReference{{Name: "governed-sa-token-abcde"}},
+	}
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "governed-sa-token-abcde", Namespace: "mycluster"},
+		Type:       corev1.SecretTypeServiceAccountToken,
+		Data:       map[string][]byte{"token": []byte("fake-token")},
+	}
+	emptyTokenSecret := tokenSecret.DeepCopy()
+	emptyTokenSecret.Data = nil
+
+	tests := []struct {
+		name        string
+		objs        []runtime.Object
+		wantErr     bool
+		wantInvalid bool
+	}{
+		{
+			name:        "service account missing",
+			objs:        []runtime.Object{},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:        "service account without token",
+			objs:        []runtime.Object{sa, emptyTokenSecret},
+			wantErr:     true,
+			wantInvalid: true,
+		},
+		{
+			name:    "service account with token",
+			objs:    []runtime.Object{sa, tokenSecret},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(scheme.Scheme, tt.objs...)
+			err := validateBootstrapServiceAccount(c, managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBootstrapServiceAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if isInvalidBootstrapServiceAccount(err) != tt.wantInvalid {
+				t.Errorf("isInvalidBootstrapServiceAccount() = %v, want %v", isInvalidBootstrapServiceAccount(err), tt.wantInvalid)
+			}
+		})
+	}
+
+	if got := newHubManifestsValues(managedCluster).BootstrapServiceAccountName; got != "governed-sa" {
+		t.Errorf("newHubManifestsValues() BootstrapServiceAccountName = %s, want governed-sa", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//bootstrapTokenAudienceAnnotation is a comma separated list of audiences the bootstrap token
+//of the ManagedCluster is requested for, it overrides BOOTSTRAP_TOKEN_AUDIENCE
+const bootstrapTokenAudienceAnnotation = "import.open-cluster-management.io/bootstrap-token-audience"
+
+//bootstrapTokenExpirationAnnotation records when the requested bootstrap token expires
+const bootstrapTokenExpirationAnnotation = "import.open-cluster-management.io/bootstrap-token-expiration"
+
+//bootstrapTokenIssuedAnnotation records when the bootstrap token was requested, the apiserver may issue
+//a token with a shorter lifetime than the requested one
+const bootstrapTokenIssuedAnnotation = "import.open-cluster-management.io/bootstrap-token-issued"
+
+/* #nosec */
+const bootstrapTokenSecretNamePostfix = "-bootstrap-audience-token"
+
+const defaultBootstrapTokenExpiration = 365 * 24 * time.Hour
+
+//getBootstrapTokenAudiences returns the audiences set on the ManagedCluster or globally,
+//no audience means the service account token secret is used with the default audience
+func getBootstrapTokenAudiences(managedCluster *clusterv1.ManagedCluster) []string {
+	v, ok := managedCluster.GetAnnotations()[bootstrapTokenAudienceAnnotation]
+	if !ok {
+		v = os.Getenv(bootstrapTokenAudienceEnvVarName)
+	}
+	audiences := []string{}
+	for _, a := range strings.Split(v, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			audiences = append(audiences, a)
+		}
+	}
+	return audiences
+}
+
+//withAudienceBoundToken returns a copy of the bootstrap secret where the token is replaced by
+//a token requested for the audiences. The requested token is kept in a secret of the cluster
+//namespace and reused until 80% of its issued lifetime is elapsed, so the import secret doesn't change
+//on each reconcile.
+func withAudienceBoundToken(
+	c client.Client,
+	kubeClient kubernetes.Interface,
+	managedCluster *clusterv1.ManagedCluster,
+	bootStrapSecret *corev1.Secret,
+	audiences []string,
+) (*corev1.Secret, error) {
+	token, err := getBootstrapToken(c, kubeClient, managedCluster, audiences, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	secret := bootStrapSecret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data["token"] = token
+	return secret, nil
+}
+
+func getBootstrapToken(
+	c client.Client,
+	kubeClient kubernetes.Interface,
+	managedCluster *clusterv1.ManagedCluster,
+	audiences []string,
+	now time.Time,
+) ([]byte, error) {
+	saNsN, err := bootstrapServiceAccountNsN(managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	expiration := getEnvDuration(bootstrapTokenExpirationEnvVarName, defaultBootstrapTokenExpiration)
+	tokenSecret := &corev1.Secret{}
+	err = c.Get(context.TODO(), types.NamespacedName{
+		Name:      managedCluster.Name + bootstrapTokenSecretNamePostfix,
+		Namespace: managedCluster.Name,
+	}, tokenSecret)
+	switch {
+	case errors.IsNotFound(err):
+		tokenSecret = nil
+	case err != nil:
+		return nil, err
+	case isBootstrapTokenValid(tokenSecret, audiences, now):
+		return tokenSecret.Data["token"], nil
+	}
+
+	if kubeClient == nil {
+		return nil, fmt.Errorf("unable to request a bootstrap token for %s, no kube client", managedCluster.Name)
+	}
+	expirationSeconds := int64(expiration.Seconds())
+	tr, err := kubeClient.CoreV1().ServiceAccounts(saNsN.Namespace).CreateToken(context.TODO(), saNsN.Name,
+		&authv1.TokenRequest{
+			Spec: authv1.TokenRequestSpec{
+				Audiences:         audiences,
+				ExpirationSeconds: &expirationSeconds,
+			},
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Requested a bootstrap token", "managedcluster", managedCluster.Name,
+		"audiences", audiences, "expiration", tr.Status.ExpirationTimestamp)
+
+	annotations := map[string]string{
+		bootstrapTokenAudienceAnnotation:   strings.Join(audiences, ","),
+		bootstrapTokenExpirationAnnotation: tr.Status.ExpirationTimestamp.UTC().Format(time.RFC3339),
+		bootstrapTokenIssuedAnnotation:     now.UTC().Format(time.RFC3339),
+	}
+	data := map[string][]byte{"token": []byte(tr.Status.Token)}
+	if tokenSecret == nil {
+		tokenSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        managedCluster.Name + bootstrapTokenSecretNamePostfix,
+				Namespace:   managedCluster.Name,
+				Annotations: annotations,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+		if err := c.Create(context.TODO(), tokenSecret); err != nil {
+			return nil, err
+		}
+		return tokenSecret.Data["token"], nil
+	}
+	tokenSecret.SetAnnotations(annotations)
+	tokenSecret.Data = data
+	if err := c.Update(context.TODO(), tokenSecret); err != nil {
+		return nil, err
+	}
+	return tokenSecret.Data["token"], nil
+}
+
+//isBootstrapTokenValid returns true if the stored token is for the same audiences and less than 80%
+//of its lifetime is elapsed, the lifetime is the one issued by the apiserver, not the requested one
+func isBootstrapTokenValid(tokenSecret *corev1.Secret, audiences []string, now time.Time) bool {
+	if len(tokenSecret.Data["token"]) == 0 {
+		return false
+	}
+	annotations := tokenSecret.GetAnnotations()
+	if annotations[bootstrapTokenAudienceAnnotation] != strings.Join(audiences, ",") {
+		return false
+	}
+	expirationTimestamp, err := time.Parse(time.RFC3339, annotations[bootstrapTokenExpirationAnnotation])
+	if err != nil {
+		return false
+	}
+	issuedTimestamp, err := time.Parse(time.RFC3339, annotations[bootstrapTokenIssuedAnnotation])
+	if err != nil {
+		return false
+	}
+	lifetime := expirationTimestamp.Sub(issuedTimestamp)
+	return now.Add(lifetime / 5).Before(expirationTimestamp)
+}
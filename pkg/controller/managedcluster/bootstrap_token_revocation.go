@@ -0,0 +1,113 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+//bootstrapTokenSecretInfix follows the service account name in the names of its token secrets
+/* #nosec */
+const bootstrapTokenSecretInfix = "-token-"
+
+const (
+	ConditionBootstrapTokenReissued string = "BootstrapTokenReissued"
+	reasonBootstrapTokenRevoked     string = "BootstrapTokenRevoked"
+	reasonBootstrapTokenApplied     string = "BootstrapTokenApplied"
+)
+
+//reissueBootstrapToken removes the references of the bootstrap service account to its deleted token
+//secrets when it has no token secret left, so the token controller issues a new one. It returns true
+//if the token was revoked, the new token is pushed in the import secret and the manifestworks by the
+//next reconcile as the rendered configuration changes. The service accounts provisioned by the user
+//are never modified.
+func (r *ReconcileManagedCluster) reissueBootstrapToken(managedCluster *clusterv1.ManagedCluster) (bool, error) {
+	if isBootstrapServiceAccountSpecified(managedCluster) {
+		return false, nil
+	}
+	saNsN, err := bootstrapServiceAccountNsN(managedCluster)
+	if err != nil {
+		return false, err
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := r.client.Get(context.TODO(), saNsN, sa); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	//The other secrets of the service account, such as the OpenShift dockercfg, are regenerated by their
+	//own controllers
+	revoked := make(map[string]bool)
+	for _, objectRef := range sa.Secrets {
+		if !strings.HasPrefix(objectRef.Name, saNsN.Name+bootstrapTokenSecretInfix) {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: objectRef.Name, Namespace: saNsN.Namespace}, secret)
+		switch {
+		case errors.IsNotFound(err):
+			revoked[objectRef.Name] = true
+		case err != nil:
+			return false, err
+		case secret.Type == corev1.SecretTypeServiceAccountToken:
+			//The service account still has a token
+			return false, nil
+		}
+	}
+	if len(revoked) == 0 {
+		return false, nil
+	}
+
+	names := []string{}
+	secrets := []corev1.ObjectReference{}
+	for _, objectRef := range sa.Secrets {
+		if revoked[objectRef.Name] {
+			names = append(names, objectRef.Name)
+			continue
+		}
+		secrets = append(secrets, objectRef)
+	}
+	sa.Secrets = secrets
+	if err := r.client.Update(context.TODO(), sa); err != nil {
+		return false, err
+	}
+
+	message := fmt.Sprintf("The bootstrap token secrets %s of the managed cluster %s were deleted, a new token is issued",
+		strings.Join(names, ","), managedCluster.Name)
+	log.Info(message, "managedcluster", managedCluster.Name)
+	r.recordEvent(managedCluster, corev1.EventTypeWarning, reasonBootstrapTokenRevoked, message)
+	if err := r.setCondition(managedCluster, metav1.Condition{
+		Type:    ConditionBootstrapTokenReissued,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonBootstrapTokenRevoked,
+		Message: message,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//clearConditionBootstrapTokenReissued sets the condition to false once the import secret holds the
+//re-issued token, the condition is only patched when it was raised
+func (r *ReconcileManagedCluster) clearConditionBootstrapTokenReissued(managedCluster *clusterv1.ManagedCluster) error {
+	if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionBootstrapTokenReissued) {
+		return nil
+	}
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:    ConditionBootstrapTokenReissued,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonBootstrapTokenApplied,
+		Message: "The re-issued bootstrap token is applied",
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileManagedCluster_reissueBootstrapToken(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	saName := "mycluster" + bootstrapServiceAccountNamePostfix
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: saName + "-token-abcde", Namespace: "mycluster"},
+		Type:       corev1.SecretTypeServiceAccountToken,
+	}
+	dockercfgSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: saName + "-dockercfg-abcde", Namespace: "mycluster"},
+		Type:       corev1.SecretTypeDockercfg,
+	}
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		secrets      []string
+		objs         []runtime.Object
+		wantReissued bool
+		wantSecrets  []string
+	}{
+		{
+			name:         "no token secret yet",
+			objs:         []runtime.Object{},
+			wantReissued: false,
+			wantSecrets:  nil,
+		},
+		{
+			name:         "token secret present",
+			secrets:      []string{saName + "-token-abcde", saName + "-dockercfg-abcde"},
+			objs:         []runtime.Object{tokenSecret, dockercfgSecret},
+			wantReissued: false,
+			wantSecrets:  []string{saName + "-token-abcde", saName + "-dockercfg-abcde"},
+		},
+		{
+			name:         "another token secret present",
+			secrets:      []string{saName + "-token-fghij", saName + "-token-abcde"},
+			objs:         []runtime.Object{tokenSecret},
+			wantReissued: false,
+			wantSecrets:  []string{saName + "-token-fghij", saName + "-token-abcde"},
+		},
+		{
+			name:         "token secret deleted",
+			secrets:      []string{saName + "-token-abcde", saName + "-dockercfg-abcde"},
+			objs:         []runtime.Object{dockercfgSecret},
+			wantReissued: true,
+			wantSecrets:  []string{saName + "-dockercfg-abcde"},
+		},
+		{
+			name:         "dockercfg secret deleted",
+			secrets:      []string{saName + "-token-abcde", saName + "-dockercfg-abcde"},
+			objs:         []runtime.Object{tokenSecret},
+			wantReissued: false,
+			wantSecrets:  []string{saName + "-token-abcde", saName + "-dockercfg-abcde"},
+		},
+		{
+			name:         "service account provisioned by the user",
+			annotations:  map[string]string{bootstrapServiceAccountAnnotation: saName},
+			secrets:      []string{saName + "-token-abcde"},
+			objs:         []runtime.Object{},
+			wantReissued: false,
+			wantSecrets:  []string{saName + "-token-abcde"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mycluster",
+					Annotations: tt.annotations,
+				},
+			}
+			sa := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: saName, Namespace: "mycluster"},
+			}
+			for _, name := range tt.secrets {
+				sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: name})
+			}
+			objs := append([]runtime.Object{managedCluster, sa}, tt.objs...)
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, objs...),
+				scheme: testscheme,
+			}
+			reissued, err := r.reissueBootstrapToken(managedCluster)
+			if err != nil {
+				t.Fatalf("reissueBootstrapToken() error = %v", err)
+			}
+			if reissued != tt.wantReissued {
+				t.Errorf("reissueBootstrapToken() = %v, want %v", reissued, tt.wantReissued)
+			}
+
+			gotSA := &corev1.ServiceAccount{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: saName, Namespace: "mycluster"}, gotSA); err != nil {
+				t.Fatal(err)
+			}
+			var gotSecrets []string
+			for _, objectRef := range gotSA.Secrets {
+				gotSecrets = append(gotSecrets, objectRef.Name)
+			}
+			if !reflect.DeepEqual(gotSecrets, tt.wantSecrets) {
+				t.Errorf("service account secrets = %v, want %v", gotSecrets, tt.wantSecrets)
+			}
+
+			mc := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+				t.Fatal(err)
+			}
+			if got := meta.IsStatusConditionTrue(mc.Status.Conditions, ConditionBootstrapTokenReissued); got != tt.wantReissued {
+				t.Errorf("condition %s = %v, want %v", ConditionBootstrapTokenReissued, got, tt.wantReissued)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_clearConditionBootstrapTokenReissued(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   ConditionBootstrapTokenReissued,
+					Status: metav1.ConditionTrue,
+					Reason: reasonBootstrapTokenRevoked,
+				},
+			},
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	if err := r.clearConditionBootstrapTokenReissued(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	mc := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(mc.Status.Conditions, ConditionBootstrapTokenReissued)
+	if c == nil || c.Status != metav1.ConditionFalse || c.Reason != reasonBootstrapTokenApplied {
+		t.Errorf("expected the condition %s to be cleared, got %v", ConditionBootstrapTokenReissued, c)
+	}
+
+	//The condition is not added to the clusters whose token was never re-issued
+	other := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "other",
+		},
+	}
+	r.client = fake.NewFakeClientWithScheme(testscheme, other)
+	if err := r.clearConditionBootstrapTokenReissued(other); err != nil {
+		t.Fatal(err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "other"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if c := meta.FindStatusCondition(got.Status.Conditions, ConditionBootstrapTokenReissued); c != nil {
+		t.Errorf("expected no condition %s, got %v", ConditionBootstrapTokenReissued, c)
+	}
+}
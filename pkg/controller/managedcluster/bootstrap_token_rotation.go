@@ -0,0 +1,136 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//rotateBootstrapTokenAnnotation triggers a rotation of the bootstrap token each time its value changes,
+//for example with a timestamp
+const rotateBootstrapTokenAnnotation = "import.open-cluster-management.io/rotate-bootstrap-token"
+
+//bootstrapTokenRotatedAnnotation records the value of rotateBootstrapTokenAnnotation last handled,
+//so a re-applied trigger (GitOps) doesn't rotate the token again
+const bootstrapTokenRotatedAnnotation = "import.open-cluster-management.io/bootstrap-token-rotated"
+
+//bootstrapTokenRotationRequeueAfter leaves time to the token controller to regenerate the token secret
+const bootstrapTokenRotationRequeueAfter = 10 * time.Second
+
+const (
+	ConditionBootstrapTokenRotated string = "BootstrapTokenRotated"
+	reasonBootstrapTokenRotated    string = "BootstrapTokenRotated"
+)
+
+//isBootstrapTokenRotationRequested returns true if the rotation trigger changed since the last rotation
+func isBootstrapTokenRotationRequested(managedCluster *clusterv1.ManagedCluster) bool {
+	annotations := managedCluster.GetAnnotations()
+	trigger, ok := annotations[rotateBootstrapTokenAnnotation]
+	if !ok || trigger == "" {
+		return false
+	}
+	return annotations[bootstrapTokenRotatedAnnotation] != trigger
+}
+
+//deleteBootstrapTokenSecrets deletes the token secrets of the bootstrap service account and the
+//audience bound token, they are regenerated by the token controller and on the next request.
+//It returns the names of the deleted secrets.
+func deleteBootstrapTokenSecrets(c client.Client, managedCluster *clusterv1.ManagedCluster) ([]string, error) {
+	saNsN, err := bootstrapServiceAccountNsN(managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := c.Get(context.TODO(), saNsN, sa); err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	names := []string{}
+	for _, objectRef := range sa.Secrets {
+		if strings.HasPrefix(objectRef.Name, saNsN.Name) {
+			names = append(names, objectRef.Name)
+		}
+	}
+	names = append(names, managedCluster.Name+bootstrapTokenSecretNamePostfix)
+
+	deleted := []string{}
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: saNsN.Namespace}, secret); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return deleted, err
+		}
+		//only delete tokens, the service account may reference other secrets such as the image pull secret
+		if secret.Type != corev1.SecretTypeServiceAccountToken &&
+			name != managedCluster.Name+bootstrapTokenSecretNamePostfix {
+			continue
+		}
+		if err := c.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+			return deleted, err
+		}
+		deleted = append(deleted, name)
+	}
+	return deleted, nil
+}
+
+//rotateBootstrapToken deletes the bootstrap token secrets if a rotation is requested, it returns
+//true if the token was rotated. The new token is pushed in the import secret and the manifestworks
+//by the next reconcile as the rendered configuration changes.
+func (r *ReconcileManagedCluster) rotateBootstrapToken(managedCluster *clusterv1.ManagedCluster) (bool, error) {
+	if !isBootstrapTokenRotationRequested(managedCluster) {
+		return false, nil
+	}
+	deleted, err := deleteBootstrapTokenSecrets(r.client, managedCluster)
+	if err != nil {
+		return false, err
+	}
+
+	trigger := managedCluster.GetAnnotations()[rotateBootstrapTokenAnnotation]
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	annotations := managedCluster.GetAnnotations()
+	annotations[bootstrapTokenRotatedAnnotation] = trigger
+	managedCluster.SetAnnotations(annotations)
+	if err := r.client.Patch(context.TODO(), managedCluster, patch); err != nil {
+		return false, err
+	}
+
+	message := fmt.Sprintf("The bootstrap token of the managed cluster %s was rotated (%s), deleted secrets: %s",
+		managedCluster.Name, trigger, strings.Join(deleted, ","))
+	log.Info(message, "managedcluster", managedCluster.Name)
+	r.recordEvent(managedCluster, corev1.EventTypeNormal, reasonBootstrapTokenRotated, message)
+	if err := r.setCondition(managedCluster, metav1.Condition{
+		Type:    ConditionBootstrapTokenRotated,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonBootstrapTokenRotated,
+		Message: message,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//clearConditionBootstrapTokenRotated sets the condition to false once the import secret holds the
+//rotated token, the condition is only patched when it was raised
+func (r *ReconcileManagedCluster) clearConditionBootstrapTokenRotated(managedCluster *clusterv1.ManagedCluster) error {
+	if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionBootstrapTokenRotated) {
+		return nil
+	}
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:    ConditionBootstrapTokenRotated,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonBootstrapTokenApplied,
+		Message: "The rotated bootstrap token is applied",
+	})
+}
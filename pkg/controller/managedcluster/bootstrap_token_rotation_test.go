@@ -0,0 +1,182 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileManagedCluster_rotateBootstrapToken(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	saName := "mycluster" + bootstrapServiceAccountNamePostfix
+	newObjs := func() []runtime.Object {
+		return []runtime.Object{
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: saName, Namespace: "mycluster"},
+				Secrets: []corev1.ObjectReference{
+					{Name: saName + "-token-abcde"},
+					{Name: saName + "-dockercfg-abcde"},
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: saName + "-token-abcde", Namespace: "mycluster"},
+				Type:       corev1.SecretTypeServiceAccountToken,
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: saName + "-dockercfg-abcde", Namespace: "mycluster"},
+				Type:       corev1.SecretTypeDockercfg,
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "mycluster" + bootstrapTokenSecretNamePostfix, Namespace: "mycluster"},
+				Type:       corev1.SecretTypeOpaque,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantRotated bool
+	}{
+		{
+			name:        "no trigger",
+			wantRotated: false,
+		},
+		{
+			name:        "new trigger",
+			annotations: map[string]string{rotateBootstrapTokenAnnotation: "2021-01-01T00:00:00Z"},
+			wantRotated: true,
+		},
+		{
+			name: "trigger changed",
+			annotations: map[string]string{
+				rotateBootstrapTokenAnnotation:  "2021-02-01T00:00:00Z",
+				bootstrapTokenRotatedAnnotation: "2021-01-01T00:00:00Z",
+			},
+			wantRotated: true,
+		},
+		{
+			name: "trigger already handled",
+			annotations: map[string]string{
+				rotateBootstrapTokenAnnotation:  "2021-01-01T00:00:00Z",
+				bootstrapTokenRotatedAnnotation: "2021-01-01T00:00:00Z",
+			},
+			wantRotated: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mycluster",
+					Annotations: tt.annotations,
+				},
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, append(newObjs(), managedCluster)...),
+				scheme: testscheme,
+			}
+			rotated, err := r.rotateBootstrapToken(managedCluster)
+			if err != nil {
+				t.Fatalf("rotateBootstrapToken() error = %v", err)
+			}
+			if rotated != tt.wantRotated {
+				t.Errorf("rotateBootstrapToken() = %v, want %v", rotated, tt.wantRotated)
+			}
+
+			for name, wantDeleted := range map[string]bool{
+				saName + "-token-abcde":                       tt.wantRotated,
+				"mycluster" + bootstrapTokenSecretNamePostfix: tt.wantRotated,
+				saName + "-dockercfg-abcde":                   false,
+			} {
+				err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "mycluster"}, &corev1.Secret{})
+				if errors.IsNotFound(err) != wantDeleted {
+					t.Errorf("secret %s deleted = %v, want %v", name, errors.IsNotFound(err), wantDeleted)
+				}
+			}
+
+			mc := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantRotated {
+				if mc.GetAnnotations()[bootstrapTokenRotatedAnnotation] != tt.annotations[rotateBootstrapTokenAnnotation] {
+					t.Errorf("rotated annotation = %s, want %s",
+						mc.GetAnnotations()[bootstrapTokenRotatedAnnotation], tt.annotations[rotateBootstrapTokenAnnotation])
+				}
+				if !meta.IsStatusConditionTrue(mc.Status.Conditions, ConditionBootstrapTokenRotated) {
+					t.Errorf("condition %s not set", ConditionBootstrapTokenRotated)
+				}
+			}
+			if isBootstrapTokenRotationRequested(mc) {
+				t.Errorf("rotation still requested after rotateBootstrapToken()")
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_clearConditionBootstrapTokenRotated(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   ConditionBootstrapTokenRotated,
+					Status: metav1.ConditionTrue,
+					Reason: reasonBootstrapTokenRotated,
+				},
+			},
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	if err := r.clearConditionBootstrapTokenRotated(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	mc := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(mc.Status.Conditions, ConditionBootstrapTokenRotated)
+	if c == nil || c.Status != metav1.ConditionFalse || c.Reason != reasonBootstrapTokenApplied {
+		t.Errorf("expected the condition %s to be cleared, got %v", ConditionBootstrapTokenRotated, c)
+	}
+
+	//The condition is not added to the clusters whose token was never rotated
+	other := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "other",
+		},
+	}
+	r.client = fake.NewFakeClientWithScheme(testscheme, other)
+	if err := r.clearConditionBootstrapTokenRotated(other); err != nil {
+		t.Fatal(err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "other"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if c := meta.FindStatusCondition(got.Status.Conditions, ConditionBootstrapTokenRotated); c != nil {
+		t.Errorf("expected no condition %s, got %v", ConditionBootstrapTokenRotated, c)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getBootstrapTokenAudiences(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		env         string
+		want        []string
+	}{
+		{
+			name: "default audience",
+			want: []string{},
+		},
+		{
+			name: "global audiences",
+			env:  "hub, registration",
+			want: []string{"hub", "registration"},
+		},
+		{
+			name:        "cluster audience overrides the global one",
+			annotations: map[string]string{bootstrapTokenAudienceAnnotation: "cluster1-registration"},
+			env:         "hub",
+			want:        []string{"cluster1-registration"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(bootstrapTokenAudienceEnvVarName, tt.env)
+			defer os.Unsetenv(bootstrapTokenAudienceEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster1",
+					Annotations: tt.annotations,
+				},
+			}
+			if got := getBootstrapTokenAudiences(managedCluster); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getBootstrapTokenAudiences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getBootstrapToken(t *testing.T) {
+	now := time.Now()
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+		},
+	}
+	newTokenSecret := func(audience string, issued, expiration time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1" + bootstrapTokenSecretNamePostfix,
+				Namespace: "cluster1",
+				Annotations: map[string]string{
+					bootstrapTokenAudienceAnnotation:   audience,
+					bootstrapTokenExpirationAnnotation: expiration.UTC().Format(time.RFC3339),
+					bootstrapTokenIssuedAnnotation:     issued.UTC().Format(time.RFC3339),
+				},
+			},
+			Data: map[string][]byte{"token": []byte("stored-token")},
+		}
+	}
+	//newKubeClient issues the tokens with the requested lifetime capped to maxLifetime, if set
+	newKubeClient := func(maxLifetime time.Duration) *fakeclientset.Clientset {
+		kubeClient := fakeclientset.NewSimpleClientset()
+		kubeClient.PrependReactor("create", "serviceaccounts",
+			func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "token" {
+					return false, nil, nil
+				}
+				tr := action.(clienttesting.CreateAction).GetObject().(*authv1.TokenRequest).DeepCopy()
+				lifetime := time.Duration(*tr.Spec.ExpirationSeconds) * time.Second
+				if maxLifetime != 0 && lifetime > maxLifetime {
+					lifetime = maxLifetime
+				}
+				tr.Status = authv1.TokenRequestStatus{
+					Token:               "requested-token",
+					ExpirationTimestamp: metav1.NewTime(now.Add(lifetime)),
+				}
+				return true, tr, nil
+			})
+		return kubeClient
+	}
+
+	tokenSecretWithoutIssueTime := newTokenSecret("hub", now, now.Add(24*time.Hour))
+	delete(tokenSecretWithoutIssueTime.Annotations, bootstrapTokenIssuedAnnotation)
+
+	tests := []struct {
+		name        string
+		client      client.Client
+		audiences   []string
+		maxLifetime time.Duration
+		want        string
+	}{
+		{
+			name:      "no stored token",
+			client:    fake.NewFakeClientWithScheme(scheme.Scheme),
+			audiences: []string{"hub"},
+			want:      "requested-token",
+		},
+		{
+			name:      "stored token valid",
+			client:    fake.NewFakeClientWithScheme(scheme.Scheme, newTokenSecret("hub", now.Add(-time.Hour), now.Add(24*time.Hour))),
+			audiences: []string{"hub"},
+			want:      "stored-token",
+		},
+		{
+			name:      "stored token for another audience",
+			client:    fake.NewFakeClientWithScheme(scheme.Scheme, newTokenSecret("other", now.Add(-time.Hour), now.Add(24*time.Hour))),
+			audiences: []string{"hub"},
+			want:      "requested-token",
+		},
+		{
+			name:      "stored token close to expiration",
+			client:    fake.NewFakeClientWithScheme(scheme.Scheme, newTokenSecret("hub", now.Add(-23*time.Hour), now.Add(time.Hour))),
+			audiences: []string{"hub"},
+			want:      "requested-token",
+		},
+		{
+			name:      "stored token without issue time",
+			client:    fake.NewFakeClientWithScheme(scheme.Scheme, tokenSecretWithoutIssueTime),
+			audiences: []string{"hub"},
+			want:      "requested-token",
+		},
+		{
+			name:        "lifetime capped by the apiserver",
+			client:      fake.NewFakeClientWithScheme(scheme.Scheme),
+			audiences:   []string{"hub"},
+			maxLifetime: time.Hour,
+			want:        "requested-token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(bootstrapTokenExpirationEnvVarName, "24h")
+			defer os.Unsetenv(bootstrapTokenExpirationEnvVarName)
+			got, err := getBootstrapToken(tt.client, newKubeClient(tt.maxLifetime), managedCluster, tt.audiences, now.Add(time.Minute))
+			if err != nil {
+				t.Errorf("getBootstrapToken() error = %v", err)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("getBootstrapToken() = %s, want %s", got, tt.want)
+			}
+			//the requested token is stored and reused
+			again, err := getBootstrapToken(tt.client, nil, managedCluster, tt.audiences, now.Add(time.Minute))
+			if err != nil {
+				t.Errorf("getBootstrapToken() error = %v", err)
+				return
+			}
+			if string(again) != tt.want {
+				t.Errorf("getBootstrapToken() second call = %s, want %s", again, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//defaultBootstrapCARepushInterval is the delay between the reconciles of two clusters after a rotation
+//of the bootstrap CA
+const defaultBootstrapCARepushInterval = time.Second
+
+//bootstrapCAKey returns the key of the CA bundle in the secret or the configmap of the bootstrap CA
+func bootstrapCAKey() string {
+	if key := os.Getenv(bootstrapCAKeyEnvVarName); key != "" {
+		return key
+	}
+	return defaultBootstrapCAKey
+}
+
+//bootstrapCASource returns an empty object of the kind of the configured bootstrap CA source and its
+//namespaced name, nil if neither BOOTSTRAP_CA_SECRET nor BOOTSTRAP_CA_CONFIGMAP is set
+func bootstrapCASource() (runtime.Object, types.NamespacedName, error) {
+	switch {
+	case os.Getenv(bootstrapCASecretEnvVarName) != "":
+		nsn, err := parseNamespacedName(os.Getenv(bootstrapCASecretEnvVarName))
+		return &corev1.Secret{}, nsn, err
+	case os.Getenv(bootstrapCAConfigMapEnvVarName) != "":
+		nsn, err := parseNamespacedName(os.Getenv(bootstrapCAConfigMapEnvVarName))
+		return &corev1.ConfigMap{}, nsn, err
+	}
+	return nil, types.NamespacedName{}, nil
+}
+
+//bootstrapCAData returns the CA bundle of the bootstrap CA secret or configmap
+func bootstrapCAData(obj runtime.Object, key string) []byte {
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		return o.Data[key]
+	case *corev1.ConfigMap:
+		return []byte(o.Data[key])
+	}
+	return nil
+}
+
+//newBootstrapCARotationPredicate filters the events to the updates of the bootstrap CA source changing
+//the CA bundle. The creations are ignored, the informer replays them at startup when all the
+//clusters are reconciled anyway.
+func newBootstrapCARotationPredicate(nsn types.NamespacedName, key string) predicate.Predicate {
+	return predicate.Predicate(predicate.Funcs{
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.MetaNew == nil || e.MetaNew.GetNamespace() != nsn.Namespace || e.MetaNew.GetName() != nsn.Name {
+				return false
+			}
+			return !bytes.Equal(bootstrapCAData(e.ObjectOld, key), bootstrapCAData(e.ObjectNew, key))
+		},
+	})
+}
+
+//bootstrapCARotationHandler enqueues the selected ManagedClusters when the bootstrap CA rotates, so their
+//import secret and manifestworks embed the new CA. The clusters are spread one every interval, to not
+//re-push the whole fleet at once.
+type bootstrapCARotationHandler struct {
+	client   client.Client
+	selector labels.Selector
+	interval time.Duration
+}
+
+func (h *bootstrapCARotationHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {}
+
+func (h *bootstrapCARotationHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueueManagedClusters(q)
+}
+
+func (h *bootstrapCARotationHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {}
+
+func (h *bootstrapCARotationHandler) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {}
+
+func (h *bootstrapCARotationHandler) enqueueManagedClusters(q workqueue.RateLimitingInterface) {
+	mcs := &clusterv1.ManagedClusterList{}
+	if err := h.client.List(context.TODO(), mcs); err != nil {
+		log.Error(err, "Unable to list the ManagedClusters after the bootstrap CA rotation")
+		return
+	}
+	names := make([]string, 0, len(mcs.Items))
+	for i := range mcs.Items {
+		if mcs.Items[i].DeletionTimestamp == nil && isManagedClusterSelected(h.selector, &mcs.Items[i]) {
+			names = append(names, mcs.Items[i].Name)
+		}
+	}
+	sort.Strings(names)
+	log.Info("The bootstrap CA rotated, regenerating the import secrets and manifestworks",
+		"managedclusters", len(names), "interval", h.interval.String())
+	for i, name := range names {
+		q.AddAfter(reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}, time.Duration(i)*h.interval)
+	}
+}
+
+//watchBootstrapCA watches the bootstrap CA source, if one is configured, to regenerate the import secrets
+//and manifestworks of all the clusters when the CA rotates. Only the bootstrap CA source is cached.
+func watchBootstrapCA(c controller.Controller, mgr manager.Manager, selector labels.Selector) error {
+	obj, nsn, err := bootstrapCASource()
+	if err != nil || obj == nil {
+		return err
+	}
+	src, err := newFilteredInformerSource(mgr, obj, nsn.Namespace, nameListOptions(nsn.Name))
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		src,
+		&bootstrapCARotationHandler{
+			client:   mgr.GetClient(),
+			selector: selector,
+			interval: getEnvDuration(bootstrapCARepushIntervalEnvVarName, defaultBootstrapCARepushInterval),
+		},
+		newBootstrapCARotationPredicate(nsn, bootstrapCAKey()),
+	)
+}
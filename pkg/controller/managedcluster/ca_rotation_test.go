@@ -0,0 +1,145 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_bootstrapCASource(t *testing.T) {
+	defer os.Unsetenv(bootstrapCASecretEnvVarName)
+	defer os.Unsetenv(bootstrapCAConfigMapEnvVarName)
+
+	obj, _, err := bootstrapCASource()
+	if err != nil || obj != nil {
+		t.Errorf("expected no source, got %v, %v", obj, err)
+	}
+
+	os.Setenv(bootstrapCAConfigMapEnvVarName, "default/kube-root-ca.crt")
+	obj, nsn, err := bootstrapCASource()
+	if _, ok := obj.(*corev1.ConfigMap); !ok || err != nil || nsn.Name != "kube-root-ca.crt" {
+		t.Errorf("expected the configmap source, got %v, %v, %v", obj, nsn, err)
+	}
+
+	//The secret takes precedence
+	os.Setenv(bootstrapCASecretEnvVarName, "hub/ca")
+	obj, nsn, err = bootstrapCASource()
+	if _, ok := obj.(*corev1.Secret); !ok || err != nil || nsn.Name != "ca" {
+		t.Errorf("expected the secret source, got %v, %v, %v", obj, nsn, err)
+	}
+
+	os.Setenv(bootstrapCASecretEnvVarName, "ca")
+	if _, _, err := bootstrapCASource(); err == nil {
+		t.Errorf("expected an invalid reference error")
+	}
+}
+
+func Test_newBootstrapCARotationPredicate(t *testing.T) {
+	newSecret := func(name, ca string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "hub",
+			},
+			Data: map[string][]byte{
+				"ca.crt":  []byte(ca),
+				"tls.crt": []byte("serving"),
+			},
+		}
+	}
+	p := newBootstrapCARotationPredicate(types.NamespacedName{Namespace: "hub", Name: "ca"}, "ca.crt")
+	update := func(old, new *corev1.Secret) bool {
+		return p.Update(event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: new, ObjectNew: new})
+	}
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{
+			name: "created",
+			got:  p.Create(event.CreateEvent{Meta: newSecret("ca", "ca1"), Object: newSecret("ca", "ca1")}),
+			want: false,
+		},
+		{
+			name: "CA rotated",
+			got:  update(newSecret("ca", "ca1"), newSecret("ca", "ca2")),
+			want: true,
+		},
+		{
+			name: "CA unchanged",
+			got:  update(newSecret("ca", "ca1"), newSecret("ca", "ca1")),
+			want: false,
+		},
+		{
+			name: "other secret",
+			got:  update(newSecret("other", "ca1"), newSecret("other", "ca2")),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("predicate = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_bootstrapCARotationHandler(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{}, &clusterv1.ManagedClusterList{})
+
+	now := metav1.Now()
+	c := fake.NewFakeClientWithScheme(testscheme,
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"shard": "a"}}},
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster2", Labels: map[string]string{"shard": "a"}}},
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster3", Labels: map[string]string{"shard": "b"}}},
+		&clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:              "deleted",
+			Labels:            map[string]string{"shard": "a"},
+			DeletionTimestamp: &now,
+			Finalizers:        []string{managedClusterFinalizer},
+		}},
+	)
+	selector, err := labels.Parse("shard=a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//All at once
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	h := &bootstrapCARotationHandler{client: c, selector: selector, interval: 0}
+	h.Update(event.UpdateEvent{}, q)
+	if q.Len() != 2 {
+		t.Fatalf("expected the 2 selected clusters to be enqueued, got %d", q.Len())
+	}
+	item, _ := q.Get()
+	if item != (reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster1"}}) {
+		t.Errorf("expected cluster1 first, got %v", item)
+	}
+
+	//Spread over time, only the first cluster is enqueued right away
+	q2 := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q2.ShutDown()
+	h.interval = time.Hour
+	h.Update(event.UpdateEvent{}, q2)
+	if q2.Len() != 1 {
+		t.Errorf("expected 1 cluster enqueued right away, got %d", q2.Len())
+	}
+}
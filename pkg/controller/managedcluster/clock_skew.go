@@ -0,0 +1,95 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const reasonClockSkewDetected = "ClockSkewDetected"
+
+var errClockSkewDetected = fmt.Errorf("clock skew detected with the managed cluster")
+
+//defaultClockSkewThreshold is the clock skew reported when CLOCK_SKEW_THRESHOLD is not set
+const defaultClockSkewThreshold = time.Minute
+
+//clockSkewRequestTimeout bounds the request reading the time of the managed cluster
+const clockSkewRequestTimeout = 10 * time.Second
+
+//serverClock is implemented by the managed cluster clients able to read the time of the API server
+type serverClock interface {
+	ServerTime() (time.Time, error)
+}
+
+var _ serverClock = &managedClusterClient{}
+
+//clockSkewThreshold returns the clock skew above which a failed import is reported as ClockSkewDetected,
+//0 disables the check
+func clockSkewThreshold() time.Duration {
+	return getEnvDuration(clockSkewThresholdEnvVarName, defaultClockSkewThreshold)
+}
+
+//ServerTime returns the time of the API server of the managed cluster read from the Date header of
+//a request, the header is set even when the request is not authorized
+func (c *managedClusterClient) ServerTime() (time.Time, error) {
+	transport, err := rest.TransportFor(c.config)
+	if err != nil {
+		return time.Time{}, err
+	}
+	httpClient := &http.Client{Transport: transport, Timeout: clockSkewRequestTimeout}
+	resp, err := httpClient.Get(strings.TrimSuffix(c.config.Host, "/") + "/version")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+	return http.ParseTime(resp.Header.Get("Date"))
+}
+
+//measureClockSkew returns the difference between the clock of the managed cluster and the local clock,
+//positive when the managed cluster is ahead. ok is false if the client can't read the remote time.
+func measureClockSkew(managedClusterClient client.Client, now func() time.Time) (skew time.Duration, ok bool) {
+	clock, isClock := managedClusterClient.(serverClock)
+	if !isClock {
+		return 0, false
+	}
+	before := now()
+	remote, err := clock.ServerTime()
+	if err != nil {
+		return 0, false
+	}
+	//The Date header has a second precision, compare it to the middle of the request
+	local := before.Add(now().Sub(before) / 2)
+	return remote.Sub(local), true
+}
+
+//checkClockSkew wraps the error of a failed import when the clock of the managed cluster differs
+//from the local clock by more than the threshold, token authentications fail with a large skew.
+//The not installed clusters and the transient failures are retried as usual.
+func checkClockSkew(managedCluster *clusterv1.ManagedCluster, managedClusterClient client.Client, errIn error) error {
+	threshold := clockSkewThreshold()
+	if errIn == nil || managedClusterClient == nil || threshold == 0 ||
+		isClusterNotInstalled(errIn) || isTransientApplyError(errIn) {
+		return errIn
+	}
+	skew, ok := measureClockSkew(managedClusterClient, time.Now)
+	if !ok {
+		return errIn
+	}
+	abs := skew
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs <= threshold {
+		return errIn
+	}
+	clusterLogger(managedCluster.Name).Info("Clock skew detected with the managed cluster", "skew", skew.String())
+	return fmt.Errorf("%w: the managed cluster clock differs by %s (threshold %s), synchronize the clocks: %v",
+		errClockSkewDetected, skew.Round(time.Second).String(), threshold.String(), errIn)
+}
@@ -0,0 +1,126 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeClockClient struct {
+	client.Client
+	time time.Time
+	err  error
+}
+
+func (c *fakeClockClient) ServerTime() (time.Time, error) {
+	return c.time, c.err
+}
+
+func Test_managedClusterClient_ServerTime(t *testing.T) {
+	remote := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", remote.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := &managedClusterClient{config: &rest.Config{Host: server.URL}}
+	got, err := c.ServerTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(remote) {
+		t.Errorf("ServerTime() = %v, want %v", got, remote)
+	}
+}
+
+func Test_checkClockSkew(t *testing.T) {
+	defer os.Unsetenv(clockSkewThresholdEnvVarName)
+
+	importErr := fmt.Errorf("Unauthorized")
+	remoteClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+	tests := []struct {
+		name      string
+		threshold string
+		client    client.Client
+		err       error
+		wantSkew  bool
+	}{
+		{
+			name:   "no error",
+			client: &fakeClockClient{Client: remoteClient, time: time.Now().Add(time.Hour)},
+			err:    nil,
+		},
+		{
+			name:   "client without time",
+			client: remoteClient,
+			err:    importErr,
+		},
+		{
+			name:   "time not readable",
+			client: &fakeClockClient{Client: remoteClient, err: fmt.Errorf("unreachable")},
+			err:    importErr,
+		},
+		{
+			name:   "within the threshold",
+			client: &fakeClockClient{Client: remoteClient, time: time.Now().Add(-10 * time.Second)},
+			err:    importErr,
+		},
+		{
+			name:     "managed cluster ahead",
+			client:   &fakeClockClient{Client: remoteClient, time: time.Now().Add(time.Hour)},
+			err:      importErr,
+			wantSkew: true,
+		},
+		{
+			name:     "managed cluster behind",
+			client:   &fakeClockClient{Client: remoteClient, time: time.Now().Add(-10 * time.Minute)},
+			err:      importErr,
+			wantSkew: true,
+		},
+		{
+			name:      "disabled",
+			threshold: "0",
+			client:    &fakeClockClient{Client: remoteClient, time: time.Now().Add(time.Hour)},
+			err:       importErr,
+		},
+		{
+			name:   "transient failure",
+			client: &fakeClockClient{Client: remoteClient, time: time.Now().Add(time.Hour)},
+			err:    newImportError(ErrTransientApply, importErr),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(clockSkewThresholdEnvVarName, tt.threshold)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mycluster",
+				},
+			}
+			err := checkClockSkew(managedCluster, tt.client, tt.err)
+			if got := errors.Is(err, errClockSkewDetected); got != tt.wantSkew {
+				t.Errorf("checkClockSkew() = %v, want a clock skew %v", err, tt.wantSkew)
+			}
+			if !tt.wantSkew && err != tt.err {
+				t.Errorf("checkClockSkew() = %v, want %v", err, tt.err)
+			}
+			if tt.wantSkew && importErrorReason(err) != reasonClockSkewDetected {
+				t.Errorf("importErrorReason() = %s, want %s", importErrorReason(err), reasonClockSkewDetected)
+			}
+		})
+	}
+}
@@ -12,6 +12,8 @@ package managedcluster
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 
 	ocinfrav1 "github.com/openshift/api/config/v1"
@@ -43,6 +45,42 @@ func getKubeAPIServerAddress(client client.Client) (string, error) {
 	return infraConfig.Status.APIServerURL, nil
 }
 
+// validateKubeAPIServerURL checks the kube API server URL used in the bootstrap kubeconfig
+// and returns it without trailing slash. Non-standard ports and path prefixes
+// (e.g. an API server behind an ingress at /k8s) are kept as they are, as well as the
+// brackets of an IPv6 literal host.
+func validateKubeAPIServerURL(serverURL string) (string, error) {
+	u, err := parseServerURL(serverURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid kube API server URL %q: %v", serverURL, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("invalid kube API server URL %q: the scheme must be https or http", serverURL)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("invalid kube API server URL %q: the host is missing", serverURL)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid kube API server URL %q: user info, query and fragment are not supported", serverURL)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// kubeAPIServerDialAddress returns the host:port to dial for the kube API server URL,
+// the port defaults to the one of the scheme
+func kubeAPIServerDialAddress(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
 // getKubeAPIServerSecretName iterate through all namespacedCertificates
 // returns the first one which has a name matches the given dnsName
 func getKubeAPIServerSecretName(client client.Client, dnsName string) (string, error) {
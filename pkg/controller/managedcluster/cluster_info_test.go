@@ -5,6 +5,7 @@
 package managedcluster
 
 import (
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -69,6 +70,134 @@ func Test_getKubeAPIServerAddress(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateKubeAPIServerURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "standard port",
+			url:  "https://api.example.com:6443",
+			want: "https://api.example.com:6443",
+		},
+		{
+			name: "default port",
+			url:  "https://api.example.com",
+			want: "https://api.example.com",
+		},
+		{
+			name: "custom port",
+			url:  "https://api.example.com:8443",
+			want: "https://api.example.com:8443",
+		},
+		{
+			name: "path prefix",
+			url:  "https://ingress.example.com/k8s",
+			want: "https://ingress.example.com/k8s",
+		},
+		{
+			name: "custom port and path prefix with trailing slash",
+			url:  "https://ingress.example.com:8443/clusters/k8s/",
+			want: "https://ingress.example.com:8443/clusters/k8s",
+		},
+		{
+			name: "IPv6",
+			url:  "https://[fd00::1]:6443/",
+			want: "https://[fd00::1]:6443",
+		},
+		{
+			name: "IPv6 default port",
+			url:  "https://[2001:db8::1]",
+			want: "https://[2001:db8::1]",
+		},
+		{
+			name:    "IPv6 without brackets",
+			url:     "https://fd00::1:6443",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			url:     "",
+			wantErr: true,
+		},
+		{
+			name:    "no scheme",
+			url:     "api.example.com:6443",
+			wantErr: true,
+		},
+		{
+			name:    "no host",
+			url:     "https:///k8s",
+			wantErr: true,
+		},
+		{
+			name:    "query",
+			url:     "https://api.example.com:6443/k8s?timeout=32s",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateKubeAPIServerURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKubeAPIServerURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("validateKubeAPIServerURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_kubeAPIServerDialAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "custom port and path prefix",
+			url:  "https://ingress.example.com:8443/k8s",
+			want: "ingress.example.com:8443",
+		},
+		{
+			name: "https default port",
+			url:  "https://ingress.example.com/k8s",
+			want: "ingress.example.com:443",
+		},
+		{
+			name: "IPv6",
+			url:  "https://[fd00::1]:6443",
+			want: "[fd00::1]:6443",
+		},
+		{
+			name: "IPv6 default port",
+			url:  "https://[fd00::1]/k8s",
+			want: "[fd00::1]:443",
+		},
+		{
+			name: "http default port",
+			url:  "http://127.0.0.1",
+			want: "127.0.0.1:80",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := kubeAPIServerDialAddress(u); got != tt.want {
+				t.Errorf("kubeAPIServerDialAddress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_getKubeAPIServerSecretName(t *testing.T) {
 	s := scheme.Scheme
 	s.AddKnownTypes(ocinfrav1.SchemeGroupVersion, &ocinfrav1.APIServer{})
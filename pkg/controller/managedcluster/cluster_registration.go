@@ -0,0 +1,46 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const reasonWaitingForClusterRegistration = "WaitingForClusterRegistration"
+
+//registrationConditionTypes are the conditions set on the ManagedCluster by the registration controller
+var registrationConditionTypes = []string{
+	clusterv1.ManagedClusterConditionHubAccepted,
+	clusterv1.ManagedClusterConditionHubDenied,
+	clusterv1.ManagedClusterConditionJoined,
+	clusterv1.ManagedClusterConditionAvailable,
+}
+
+//isPendingRegistration returns true if the registration controller didn't process the ManagedCluster
+//yet, it has none of its conditions. Such a brand-new cluster is not offline, it is not registered yet.
+//The conditions set by this controller are ignored. A cluster not accepted by the hub is not pending,
+//the registration doesn't proceed before its klusterlet joins so waiting for it would block the import.
+func isPendingRegistration(managedCluster *clusterv1.ManagedCluster) bool {
+	if !managedCluster.Spec.HubAcceptsClient {
+		return false
+	}
+	for _, conditionType := range registrationConditionTypes {
+		if meta.FindStatusCondition(managedCluster.Status.Conditions, conditionType) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+//setConditionWaitingForClusterRegistration sets the import condition while the auto-import waits for
+//the registration of the cluster, the update of the conditions by the registration triggers a reconcile
+func (r *ReconcileManagedCluster) setConditionWaitingForClusterRegistration(managedCluster *clusterv1.ManagedCluster) error {
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:    importConditionType(),
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonWaitingForClusterRegistration,
+		Message: "Waiting for the registration of the cluster on the hub before the auto-import",
+	})
+}
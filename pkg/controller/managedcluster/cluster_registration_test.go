@@ -0,0 +1,119 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_isPendingRegistration(t *testing.T) {
+	tests := []struct {
+		name             string
+		hubAcceptsClient bool
+		conditions       []metav1.Condition
+		want             bool
+	}{
+		{
+			name:             "nil conditions",
+			hubAcceptsClient: true,
+			conditions:       nil,
+			want:             true,
+		},
+		{
+			name:             "empty conditions",
+			hubAcceptsClient: true,
+			conditions:       []metav1.Condition{},
+			want:             true,
+		},
+		{
+			name:             "only the import condition",
+			hubAcceptsClient: true,
+			conditions: []metav1.Condition{
+				{
+					Type:   importConditionType(),
+					Status: metav1.ConditionFalse,
+				},
+			},
+			want: true,
+		},
+		{
+			name:             "not accepted by the hub",
+			hubAcceptsClient: false,
+			conditions:       nil,
+			want:             false,
+		},
+		{
+			name:             "accepted by the hub",
+			hubAcceptsClient: true,
+			conditions: []metav1.Condition{
+				{
+					Type:   clusterv1.ManagedClusterConditionHubAccepted,
+					Status: metav1.ConditionTrue,
+				},
+			},
+			want: false,
+		},
+		{
+			name:             "available unknown",
+			hubAcceptsClient: true,
+			conditions: []metav1.Condition{
+				{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: metav1.ConditionUnknown,
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mycluster",
+				},
+				Spec: clusterv1.ManagedClusterSpec{
+					HubAcceptsClient: tt.hubAcceptsClient,
+				},
+				Status: clusterv1.ManagedClusterStatus{
+					Conditions: tt.conditions,
+				},
+			}
+			if got := isPendingRegistration(managedCluster); got != tt.want {
+				t.Errorf("isPendingRegistration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_setConditionWaitingForClusterRegistration(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	if err := r.setConditionWaitingForClusterRegistration(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, got); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(got.Status.Conditions, importConditionType())
+	if c == nil || c.Status != metav1.ConditionFalse || c.Reason != reasonWaitingForClusterRegistration {
+		t.Errorf("expected the condition %s, got %v", reasonWaitingForClusterRegistration, got.Status.Conditions)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"os"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	libgometav1 "github.com/open-cluster-management/library-go/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+//getManagedClusterSelector returns the selector of the ManagedClusters reconciled by this controller,
+//all the ManagedClusters if MANAGED_CLUSTER_LABEL_SELECTOR is not set
+func getManagedClusterSelector() (labels.Selector, error) {
+	v := os.Getenv(managedClusterLabelSelectorEnvVarName)
+	if v == "" {
+		return labels.Everything(), nil
+	}
+	selector, err := labels.Parse(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", managedClusterLabelSelectorEnvVarName, v, err)
+	}
+	return selector, nil
+}
+
+//isManagedClusterSelected returns true if the ManagedCluster is reconciled by this controller:
+//it matches the selector, or it is deleted and still has the finalizer of a previous import
+//by this controller (the label changed since) so the cleanup is done
+func isManagedClusterSelected(selector labels.Selector, managedCluster *clusterv1.ManagedCluster) bool {
+	if selector == nil || selector.Matches(labels.Set(managedCluster.GetLabels())) {
+		return true
+	}
+	return managedCluster.DeletionTimestamp != nil &&
+		libgometav1.HasFinalizer(managedCluster, managedClusterFinalizer)
+}
+
+//newManagedClusterSelectorPredicate filters the ManagedCluster events to the selected ManagedClusters
+func newManagedClusterSelectorPredicate(selector labels.Selector) predicate.Predicate {
+	selected := func(obj interface{}) bool {
+		managedCluster, ok := obj.(*clusterv1.ManagedCluster)
+		return ok && isManagedClusterSelected(selector, managedCluster)
+	}
+	return predicate.Predicate(predicate.Funcs{
+		GenericFunc: func(e event.GenericEvent) bool { return selected(e.Object) },
+		CreateFunc:  func(e event.CreateEvent) bool { return selected(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return selected(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return selected(e.ObjectNew) },
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func Test_isManagedClusterSelected(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name              string
+		env               string
+		labels            map[string]string
+		deletionTimestamp *metav1.Time
+		finalizers        []string
+		want              bool
+	}{
+		{
+			name: "no selector",
+			want: true,
+		},
+		{
+			name:   "matching selector",
+			env:    "shard=a",
+			labels: map[string]string{"shard": "a"},
+			want:   true,
+		},
+		{
+			name:   "not matching selector",
+			env:    "shard=a",
+			labels: map[string]string{"shard": "b"},
+			want:   false,
+		},
+		{
+			name:              "not matching selector, deleted after an import by this controller",
+			env:               "shard=a",
+			labels:            map[string]string{"shard": "b"},
+			deletionTimestamp: &now,
+			finalizers:        []string{managedClusterFinalizer},
+			want:              true,
+		},
+		{
+			name:              "not matching selector, deleted without import by this controller",
+			env:               "shard=a",
+			labels:            map[string]string{"shard": "b"},
+			deletionTimestamp: &now,
+			want:              false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(managedClusterLabelSelectorEnvVarName, tt.env)
+			defer os.Unsetenv(managedClusterLabelSelectorEnvVarName)
+			selector, err := getManagedClusterSelector()
+			if err != nil {
+				t.Fatal(err)
+			}
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "mycluster",
+					Labels:            tt.labels,
+					DeletionTimestamp: tt.deletionTimestamp,
+					Finalizers:        tt.finalizers,
+				},
+			}
+			if got := isManagedClusterSelected(selector, managedCluster); got != tt.want {
+				t.Errorf("isManagedClusterSelected() = %v, want %v", got, tt.want)
+			}
+			p := newManagedClusterSelectorPredicate(selector)
+			if got := p.Create(event.CreateEvent{Meta: managedCluster, Object: managedCluster}); got != tt.want {
+				t.Errorf("predicate Create() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getManagedClusterSelector_invalid(t *testing.T) {
+	os.Setenv(managedClusterLabelSelectorEnvVarName, "shard in (a")
+	defer os.Unsetenv(managedClusterLabelSelectorEnvVarName)
+	if _, err := getManagedClusterSelector(); err == nil {
+		t.Errorf("expected an error for an invalid selector")
+	}
+}
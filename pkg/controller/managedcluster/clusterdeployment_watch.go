@@ -0,0 +1,67 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//clusterNamespaceToManagedCluster maps an object of a cluster namespace to the ManagedCluster named as
+//its namespace
+func clusterNamespaceToManagedCluster(obj handler.MapObject) []reconcile.Request {
+	if obj.Meta == nil || obj.Meta.GetNamespace() == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name: obj.Meta.GetNamespace(),
+			},
+		},
+	}
+}
+
+//isClusterDeploymentCRDMissing returns true if the error is due to the ClusterDeployment kind being unknown,
+//on a hub without Hive. The clusters of such a hub are not Hive clusters.
+func isClusterDeploymentCRDMissing(err error) bool {
+	return meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err)
+}
+
+//hasClusterDeploymentCRD returns true if the ClusterDeployment kind is served by the hub
+func hasClusterDeploymentCRD(mapper meta.RESTMapper) (bool, error) {
+	gvk := hivev1.SchemeGroupVersion.WithKind("ClusterDeployment")
+	if _, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func isClusterDeploymentInstalled(obj interface{}) bool {
+	clusterDeployment, ok := obj.(*hivev1.ClusterDeployment)
+	return ok && clusterDeployment.Spec.Installed
+}
+
+//newClusterDeploymentInstalledPredicate filters the events to the ClusterDeployments becoming installed,
+//so the Hive-provisioned clusters are imported as soon as the provisioning finishes
+func newClusterDeploymentInstalledPredicate() predicate.Predicate {
+	return predicate.Predicate(predicate.Funcs{
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isClusterDeploymentInstalled(e.Object)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !isClusterDeploymentInstalled(e.ObjectOld) && isClusterDeploymentInstalled(e.ObjectNew)
+		},
+	})
+}
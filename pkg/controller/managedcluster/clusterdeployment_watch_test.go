@@ -0,0 +1,200 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func newTestClusterDeployment(installed bool) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mycluster",
+			Namespace: "mycluster",
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			Installed: installed,
+		},
+	}
+}
+
+func Test_clusterNamespaceToManagedCluster(t *testing.T) {
+	clusterDeployment := newTestClusterDeployment(true)
+	got := clusterNamespaceToManagedCluster(handler.MapObject{Meta: clusterDeployment, Object: clusterDeployment})
+	if len(got) != 1 || got[0].Name != "mycluster" || got[0].Namespace != "" {
+		t.Errorf("clusterNamespaceToManagedCluster() = %v, want the ManagedCluster mycluster", got)
+	}
+}
+
+func Test_newClusterDeploymentInstalledPredicate(t *testing.T) {
+	notInstalled := newTestClusterDeployment(false)
+	installed := newTestClusterDeployment(true)
+	p := newClusterDeploymentInstalledPredicate()
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{
+			name: "created installed",
+			got:  p.Create(event.CreateEvent{Meta: installed, Object: installed}),
+			want: true,
+		},
+		{
+			name: "created not installed",
+			got:  p.Create(event.CreateEvent{Meta: notInstalled, Object: notInstalled}),
+			want: false,
+		},
+		{
+			name: "becomes installed",
+			got: p.Update(event.UpdateEvent{
+				MetaOld: notInstalled, ObjectOld: notInstalled,
+				MetaNew: installed, ObjectNew: installed}),
+			want: true,
+		},
+		{
+			name: "status update of an installed cluster",
+			got: p.Update(event.UpdateEvent{
+				MetaOld: installed, ObjectOld: installed,
+				MetaNew: installed, ObjectNew: installed}),
+			want: false,
+		},
+		{
+			name: "status update of a provisioning cluster",
+			got: p.Update(event.UpdateEvent{
+				MetaOld: notInstalled, ObjectOld: notInstalled,
+				MetaNew: notInstalled, ObjectNew: notInstalled}),
+			want: false,
+		},
+		{
+			name: "deleted",
+			got:  p.Delete(event.DeleteEvent{Meta: installed, Object: installed}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("predicate = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isClusterDeploymentCRDMissing(t *testing.T) {
+	gvk := hivev1.SchemeGroupVersion.WithKind("ClusterDeployment")
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "no kind match",
+			err:  &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}},
+			want: true,
+		},
+		{
+			name: "not registered",
+			err:  runtime.NewNotRegisteredErrForKind("test", gvk),
+			want: true,
+		},
+		{
+			name: "not found",
+			err:  errors.NewNotFound(schema.GroupResource{Group: "hive.openshift.io", Resource: "clusterdeployments"}, "test"),
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("connection refused"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isClusterDeploymentCRDMissing(tt.err); got != tt.want {
+				t.Errorf("isClusterDeploymentCRDMissing() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hasClusterDeploymentCRD(t *testing.T) {
+	noHive := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	noHive.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	hive := meta.NewDefaultRESTMapper([]schema.GroupVersion{hivev1.SchemeGroupVersion})
+	hive.Add(hivev1.SchemeGroupVersion.WithKind("ClusterDeployment"), meta.RESTScopeNamespace)
+
+	if got, err := hasClusterDeploymentCRD(noHive); err != nil || got {
+		t.Errorf("expected no ClusterDeployment CRD, got %t, %v", got, err)
+	}
+	if got, err := hasClusterDeploymentCRD(hive); err != nil || !got {
+		t.Errorf("expected the ClusterDeployment CRD, got %t, %v", got, err)
+	}
+}
+
+//newNoHiveScheme returns a scheme without the Hive types, as the client of a hub without Hive
+func newNoHiveScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	s.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	return s
+}
+
+func TestReconcileManagedCluster_toBeImported_noHive(t *testing.T) {
+	s := newNoHiveScheme(t)
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(s, managedCluster),
+		scheme: s,
+	}
+	_, clusterDeployment, toImport, err := r.toBeImported(managedCluster)
+	if err != nil {
+		t.Fatalf("toBeImported() unexpected error %v", err)
+	}
+	if clusterDeployment != nil || toImport {
+		t.Errorf("expected a cluster not to import, got %v, %t", clusterDeployment, toImport)
+	}
+}
+
+func TestReconcileManagedCluster_deleteNamespace_noHive(t *testing.T) {
+	s := newNoHiveScheme(t)
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "mycluster",
+			Labels: map[string]string{clusterLabel: "mycluster"},
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(s, ns),
+		scheme: s,
+	}
+	if err := r.deleteNamespace("mycluster"); err != nil {
+		t.Fatalf("deleteNamespace() unexpected error %v", err)
+	}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, &corev1.Namespace{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the namespace to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//reasonControllerRBACInsufficient is set when the service account of the controller is not allowed
+//to apply the hub manifests, it's a misconfiguration of the controller rather than of the cluster
+const reasonControllerRBACInsufficient = "ControllerRBACInsufficient"
+
+const (
+	controllerRBACBackoffBase = 30 * time.Second
+	controllerRBACBackoffCap  = 10 * time.Minute
+)
+
+//controllerRBACRetries spreads the retries of the clusters while the controller RBAC is insufficient
+var controllerRBACRetries = &namespaceDeletionBackoff{
+	failures: map[string]int{},
+	base:     controllerRBACBackoffBase,
+	cap:      controllerRBACBackoffCap,
+}
+
+//forbiddenMessage extracts the verb and the resource of the API server forbidden message
+var forbiddenMessage = regexp.MustCompile(`cannot (\S+) resource "([^"]+)"(?: in API group "([^"]*)")?`)
+
+//getForbiddenOperations returns the denied operations, as "<verb> <resource>", of a forbidden error
+//returned while applying the hub manifests, nil if the error is not a forbidden error. The forbidden
+//error of a terminating namespace is not an RBAC issue.
+func getForbiddenOperations(err error) []string {
+	if err == nil || isNamespaceTerminating(err) {
+		return nil
+	}
+	var applyErr *hubManifestsApplyError
+	if errors.As(err, &applyErr) {
+		operations := []string{}
+		for _, e := range applyErr.failed {
+			operations = append(operations, getForbiddenOperations(e)...)
+		}
+		sort.Strings(operations)
+		if len(operations) == 0 {
+			return nil
+		}
+		return operations
+	}
+	//The applier may not wrap the API error, the message is checked as well
+	if !apierrors.IsForbidden(err) && !strings.Contains(err.Error(), "is forbidden") {
+		return nil
+	}
+	m := forbiddenMessage.FindStringSubmatch(err.Error())
+	switch {
+	case m == nil:
+		return []string{"apply"}
+	case m[3] != "":
+		return []string{fmt.Sprintf("%s %s.%s", m[1], m[2], m[3])}
+	default:
+		return []string{fmt.Sprintf("%s %s", m[1], m[2])}
+	}
+}
+
+//reportControllerRBACInsufficient sets the import condition naming the denied operations and requeues
+//the ManagedCluster with an exponential backoff, the RBAC of the controller has to be fixed first
+func (r *ReconcileManagedCluster) reportControllerRBACInsufficient(
+	managedCluster *clusterv1.ManagedCluster,
+	operations []string,
+	errIn error,
+) (reconcile.Result, error) {
+	retryAfter := controllerRBACRetries.next(managedCluster.Name)
+	message := fmt.Sprintf("The controller service account is not allowed to %s, fix its RBAC: %s",
+		strings.Join(operations, ", "), errIn.Error())
+	clusterLogger(managedCluster.Name).Info(message, "retryAfter", retryAfter.String())
+	if err := r.setCondition(managedCluster, metav1.Condition{
+		Type:    importConditionType(),
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonControllerRBACInsufficient,
+		Message: message,
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: retryAfter}, nil
+}
@@ -0,0 +1,145 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getForbiddenOperations(t *testing.T) {
+	clusterRoles := schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}
+	forbiddenClusterRole := apierrors.NewForbidden(clusterRoles, "cluster-bootstrap", fmt.Errorf(
+		`User "system:serviceaccount:open-cluster-management:managedcluster-import-controller" `+
+			`cannot create resource "clusterroles" in API group "rbac.authorization.k8s.io" at the cluster scope`))
+	forbiddenSecret := apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "cluster-import",
+		fmt.Errorf(`User "system:serviceaccount:open-cluster-management:managedcluster-import-controller" `+
+			`cannot update resource "secrets" in API group "" in the namespace "cluster"`))
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{
+			name: "no error",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "not forbidden",
+			err:  fmt.Errorf("connection refused"),
+			want: nil,
+		},
+		{
+			name: "forbidden",
+			err:  forbiddenClusterRole,
+			want: []string{"create clusterroles.rbac.authorization.k8s.io"},
+		},
+		{
+			name: "forbidden in the core group",
+			err:  forbiddenSecret,
+			want: []string{"update secrets"},
+		},
+		{
+			name: "forbidden without the operation",
+			err:  apierrors.NewForbidden(clusterRoles, "cluster-bootstrap", fmt.Errorf("denied")),
+			want: []string{"apply"},
+		},
+		{
+			name: "forbidden message of an unwrapped error",
+			err:  fmt.Errorf("failed to apply: %v", forbiddenClusterRole),
+			want: []string{"create clusterroles.rbac.authorization.k8s.io"},
+		},
+		{
+			name: "terminating namespace",
+			err: apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "cluster-import",
+				fmt.Errorf("unable to create new content in namespace cluster because it is being terminated")),
+			want: nil,
+		},
+		{
+			name: "hub manifests",
+			err: &hubManifestsApplyError{failed: map[string]error{
+				"hub/managedcluster/manifests/a.yaml": forbiddenSecret,
+				"hub/managedcluster/manifests/b.yaml": forbiddenClusterRole,
+				"hub/managedcluster/manifests/c.yaml": fmt.Errorf("connection refused"),
+			}},
+			want: []string{"create clusterroles.rbac.authorization.k8s.io", "update secrets"},
+		},
+		{
+			name: "hub manifests not forbidden",
+			err: &hubManifestsApplyError{failed: map[string]error{
+				"hub/managedcluster/manifests/a.yaml": fmt.Errorf("connection refused"),
+			}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getForbiddenOperations(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getForbiddenOperations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_reportControllerRBACInsufficient(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	defer controllerRBACRetries.reset("cluster")
+
+	err := apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "cluster-import", fmt.Errorf("denied"))
+	var previous time.Duration
+	for i := 0; i < 3; i++ {
+		result, errReport := r.reportControllerRBACInsufficient(managedCluster, []string{"update secrets"}, err)
+		if errReport != nil {
+			t.Fatal(errReport)
+		}
+		if result.RequeueAfter < controllerRBACBackoffBase/2 || result.RequeueAfter > controllerRBACBackoffCap {
+			t.Errorf("unexpected requeue after %s", result.RequeueAfter)
+		}
+		//The lower bound of a jittered delay is the upper bound of the previous one
+		if result.RequeueAfter < previous {
+			t.Errorf("expected the requeue to back off, got %s after %s", result.RequeueAfter, previous)
+		}
+		previous = result.RequeueAfter
+	}
+
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster"}, got); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(got.Status.Conditions, importConditionType())
+	if c == nil || c.Status != metav1.ConditionFalse || c.Reason != reasonControllerRBACInsufficient {
+		t.Fatalf("expected the %s condition, got %v", reasonControllerRBACInsufficient, c)
+	}
+
+	//The condition is removed once the hub manifests are applied
+	if err := r.clearConditionImportWaiting(got, reasonControllerRBACInsufficient); err != nil {
+		t.Fatal(err)
+	}
+	if c := meta.FindStatusCondition(got.Status.Conditions, importConditionType()); c != nil {
+		t.Errorf("expected the condition to be removed, got %v", c)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+//correlationIDs holds the correlation ID of the reconcile in progress of each cluster, the reconciles
+//of a cluster are never run concurrently so the cluster name identifies the reconcile. The reconciles
+//get no context with the controller-runtime version in use, so there is no trace ID to reuse.
+var correlationIDs sync.Map
+
+//newCorrelationID returns a random ID identifying a reconcile in the logs
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+//beginCorrelation assigns a new correlation ID to the reconcile of the cluster
+func beginCorrelation(clusterName string) string {
+	id := newCorrelationID()
+	correlationIDs.Store(clusterName, id)
+	return id
+}
+
+//endCorrelation forgets the correlation ID of the reconcile of the cluster
+func endCorrelation(clusterName string) {
+	correlationIDs.Delete(clusterName)
+}
+
+//correlationID returns the correlation ID of the reconcile in progress of the cluster, empty if none
+func correlationID(clusterName string) string {
+	if id, ok := correlationIDs.Load(clusterName); ok {
+		return id.(string)
+	}
+	return ""
+}
+
+//clusterLogger returns a logger whose lines carry the correlation ID of the reconcile of the cluster
+func clusterLogger(clusterName string) logr.Logger {
+	return log.WithValues("correlationID", correlationID(clusterName), "managedcluster", clusterName)
+}
+
+//correlated prefixes a klog message with the correlation ID of the reconcile of the cluster
+func correlated(clusterName, format string) string {
+	return "[correlationID=" + correlationID(clusterName) + "] " + format
+}
@@ -0,0 +1,30 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_correlationID(t *testing.T) {
+	if id := correlationID("cluster1"); id != "" {
+		t.Errorf("expected no correlation ID before the reconcile, got %s", id)
+	}
+	id1 := beginCorrelation("cluster1")
+	id2 := beginCorrelation("cluster2")
+	if id1 == "" || id1 == id2 {
+		t.Errorf("expected distinct correlation IDs, got %s and %s", id1, id2)
+	}
+	if got := correlationID("cluster1"); got != id1 {
+		t.Errorf("expected the correlation ID %s, got %s", id1, got)
+	}
+	if got := correlated("cluster2", "Importing cluster: %s"); !strings.Contains(got, id2) {
+		t.Errorf("expected the message to carry the correlation ID %s, got %s", id2, got)
+	}
+	endCorrelation("cluster1")
+	endCorrelation("cluster2")
+	if id := correlationID("cluster1"); id != "" {
+		t.Errorf("expected no correlation ID after the reconcile, got %s", id)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/templateprocessor"
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const reasonInvalidCreateOnlyManifests = "InvalidCreateOnlyManifests"
+
+// createOnlyManifests returns the klusterlet manifest paths of KLUSTERLET_CREATE_ONLY_MANIFESTS
+func createOnlyManifests() []string {
+	paths := make([]string, 0)
+	for _, p := range strings.Split(os.Getenv(klusterletCreateOnlyManifestsEnvVarName), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// getExistingCreateOnlyManifests returns the create-only manifests already on the managed cluster, they
+// are excluded from the apply like the existing service account so the changes made on the managed
+// cluster are not reverted. The missing ones are created.
+func getExistingCreateOnlyManifests(
+	managedClusterClient client.Client,
+	managedCluster *clusterv1.ManagedCluster,
+	paths []string,
+) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	agentNamespace, err := getKlusterletNamespace(managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	klusterletCRName, err := getKlusterletName(managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	klusterletClusterRoleName, err := getKlusterletClusterRoleName(managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	klusterletAdminAggregateClusterRoleName, err := getKlusterletAdminAggregateClusterRoleName(managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	//Only the fields naming the resources are needed to find them on the managed cluster
+	values := map[string]interface{}{
+		"KlusterletNamespace":     agentNamespace,
+		"ManagedClusterNamespace": managedCluster.Name,
+		"ImagePullSecretName":     managedClusterImagePullSecretName,
+		"KlusterletName":          klusterletCRName,
+
+		"KlusterletClusterRoleName":               klusterletClusterRoleName,
+		"KlusterletAdminAggregateClusterRoleName": klusterletAdminAggregateClusterRoleName,
+	}
+	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make([]string, 0)
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "klusterlet/") || strings.HasPrefix(path, "klusterlet/crds/") {
+			return nil, newConfigError(reasonInvalidCreateOnlyManifests,
+				"invalid klusterlet create-only manifests: %s is not a klusterlet manifest", path)
+		}
+		if _, err := bindata.Asset(path); err != nil {
+			return nil, newConfigError(reasonInvalidCreateOnlyManifests,
+				"invalid klusterlet create-only manifests: %s doesn't exist", path)
+		}
+		b, err := tp.TemplateResource(path, values)
+		if err != nil {
+			return nil, err
+		}
+		u, err := parseManifest(string(b))
+		if err != nil {
+			return nil, newConfigError(reasonInvalidCreateOnlyManifests,
+				"invalid klusterlet create-only manifests: %s: %v", path, err)
+		}
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(u.GroupVersionKind())
+		err = managedClusterClient.Get(context.TODO(),
+			types.NamespacedName{Name: u.GetName(), Namespace: u.GetNamespace()}, found)
+		switch {
+		case apierrors.IsNotFound(err) || meta.IsNoMatchError(err):
+			logSubsystemImport.V(2).Infof(correlated(managedCluster.Name, "Creating the create-only manifest %s"), path)
+		case err != nil:
+			return nil, err
+		default:
+			existing = append(existing, path)
+		}
+	}
+	return existing, nil
+}
@@ -0,0 +1,109 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_createOnlyManifests(t *testing.T) {
+	os.Setenv(klusterletCreateOnlyManifestsEnvVarName, " klusterlet/cluster_role.yaml,,klusterlet/operator.yaml ")
+	defer os.Unsetenv(klusterletCreateOnlyManifestsEnvVarName)
+
+	want := []string{"klusterlet/cluster_role.yaml", "klusterlet/operator.yaml"}
+	if got := createOnlyManifests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("createOnlyManifests() = %v, want %v", got, want)
+	}
+}
+
+func Test_getExistingCreateOnlyManifests(t *testing.T) {
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "klusterlet",
+		},
+	}
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "klusterlet",
+			Namespace: klusterletNamespace,
+		},
+	}
+	tests := []struct {
+		name        string
+		paths       []string
+		objs        []runtime.Object
+		want        []string
+		wantInvalid bool
+	}{
+		{
+			name: "none configured",
+			objs: []runtime.Object{clusterRole},
+			want: nil,
+		},
+		{
+			name:  "missing manifests are created",
+			paths: []string{"klusterlet/cluster_role.yaml", "klusterlet/service_account.yaml"},
+			want:  []string{},
+		},
+		{
+			name:  "existing manifests are not updated",
+			paths: []string{"klusterlet/cluster_role.yaml", "klusterlet/operator.yaml"},
+			objs:  []runtime.Object{clusterRole},
+			want:  []string{"klusterlet/cluster_role.yaml"},
+		},
+		{
+			name:  "found in the klusterlet namespace",
+			paths: []string{"klusterlet/service_account.yaml"},
+			objs:  []runtime.Object{serviceAccount},
+			want:  []string{"klusterlet/service_account.yaml"},
+		},
+		{
+			name:        "not a klusterlet manifest",
+			paths:       []string{"hub/managedcluster/manifests/managedcluster-service-account.yaml"},
+			wantInvalid: true,
+		},
+		{
+			name:        "crds are not create-only",
+			paths:       []string{"klusterlet/crds/0000_00_operator.open-cluster-management.io_klusterlets.crd.yaml"},
+			wantInvalid: true,
+		},
+		{
+			name:        "unknown manifest",
+			paths:       []string{"klusterlet/unknown.yaml"},
+			wantInvalid: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			}
+			c := fake.NewFakeClientWithScheme(scheme.Scheme, tt.objs...)
+			got, err := getExistingCreateOnlyManifests(c, managedCluster, tt.paths)
+			if tt.wantInvalid {
+				if configErrorReason(err) != reasonInvalidCreateOnlyManifests {
+					t.Errorf("expected an invalid create-only manifests error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getExistingCreateOnlyManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
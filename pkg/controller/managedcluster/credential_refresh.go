@@ -0,0 +1,69 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const defaultMaxCredentialRefreshes = 3
+
+const reasonCredentialRefreshFailed = "CredentialRefreshFailed"
+
+var errCredentialRefreshFailed = errors.New("the managed cluster credentials are still rejected after refresh")
+
+//retryImportWithRefreshedCredentials imports the cluster again when the managed cluster rejected
+//the credentials of the auto-import-secret in the middle of the import. Short-lived tokens
+//(exec plugins, OIDC...) can expire during a long import: the exec plugin is called again
+//when the client is rebuilt and a token rotated in the secret is picked up.
+func (r *ReconcileManagedCluster) retryImportWithRefreshedCredentials(
+	managedCluster *clusterv1.ManagedCluster,
+	autoImportSecret *corev1.Secret,
+	res reconcile.Result,
+	err error,
+) (reconcile.Result, error) {
+	maxRefreshes := getEnvInt(maxCredentialRefreshesEnvVarName, defaultMaxCredentialRefreshes)
+	return retryOnUnauthorized(maxRefreshes, res, err, func() (reconcile.Result, error) {
+		klog.Infof("Credentials rejected while importing %s, refreshing them", managedCluster.Name)
+		latest := &corev1.Secret{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{
+			Name:      autoImportSecret.Name,
+			Namespace: autoImportSecret.Namespace,
+		}, latest); err != nil {
+			return reconcile.Result{}, err
+		}
+		//Keep the caller secret up to date as the retry counter is decremented on it
+		latest.DeepCopyInto(autoImportSecret)
+		managedClusterClient, err := r.getManagedClusterClientFromAutoImportSecret(managedCluster, autoImportSecret)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return r.importClusterWithClient(managedCluster, autoImportSecret, managedClusterClient)
+	})
+}
+
+//retryOnUnauthorized calls importFn again while the import fails with Unauthorized,
+//at most maxRefreshes times
+func retryOnUnauthorized(
+	maxRefreshes int,
+	res reconcile.Result,
+	err error,
+	importFn func() (reconcile.Result, error),
+) (reconcile.Result, error) {
+	for i := 0; i < maxRefreshes && apierrors.IsUnauthorized(err); i++ {
+		res, err = importFn()
+	}
+	if apierrors.IsUnauthorized(err) {
+		return res, fmt.Errorf("%w (%d refreshes): %v", errCredentialRefreshFailed, maxRefreshes, err)
+	}
+	return res, err
+}
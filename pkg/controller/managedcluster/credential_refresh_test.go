@@ -0,0 +1,72 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_retryOnUnauthorized(t *testing.T) {
+	unauthorized := apierrors.NewUnauthorized("token expired")
+	tests := []struct {
+		name        string
+		err         error
+		results     []error
+		wantCalls   int
+		wantErr     bool
+		wantRefresh bool
+	}{
+		{
+			name:      "no error",
+			err:       nil,
+			wantCalls: 0,
+		},
+		{
+			name:      "other error is not retried",
+			err:       fmt.Errorf("boom"),
+			wantCalls: 0,
+			wantErr:   true,
+		},
+		{
+			name:      "refresh succeeds",
+			err:       unauthorized,
+			results:   []error{unauthorized, nil},
+			wantCalls: 2,
+		},
+		{
+			name:        "refresh keeps failing",
+			err:         unauthorized,
+			results:     []error{unauthorized, unauthorized, unauthorized},
+			wantCalls:   3,
+			wantErr:     true,
+			wantRefresh: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			_, err := retryOnUnauthorized(3, reconcile.Result{}, tt.err, func() (reconcile.Result, error) {
+				err := tt.results[calls]
+				calls++
+				return reconcile.Result{}, err
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("retryOnUnauthorized() calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("retryOnUnauthorized() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, errCredentialRefreshFailed) != tt.wantRefresh {
+				t.Errorf("retryOnUnauthorized() error = %v, want refresh failure %v", err, tt.wantRefresh)
+			}
+			if tt.wantRefresh && importErrorReason(err) != reasonCredentialRefreshFailed {
+				t.Errorf("importErrorReason() = %s, want %s", importErrorReason(err), reasonCredentialRefreshFailed)
+			}
+		})
+	}
+}
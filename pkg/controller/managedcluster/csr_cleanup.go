@@ -0,0 +1,39 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	certificatesv1beta1 "k8s.io/api/certificates/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//csrClusterNameLabel is the label set by the registration agent on its CSRs with the cluster name
+const csrClusterNameLabel = "open-cluster-management.io/cluster-name"
+
+//deleteClusterCSRs deletes the pending and approved CSRs of the deleted ManagedCluster, only the CSRs
+//labeled with its name are deleted
+func deleteClusterCSRs(c client.Client, managedCluster *clusterv1.ManagedCluster) error {
+	if managedCluster.Name == "" {
+		return nil
+	}
+	csrs := &certificatesv1beta1.CertificateSigningRequestList{}
+	if err := c.List(context.TODO(), csrs, client.MatchingLabels{csrClusterNameLabel: managedCluster.Name}); err != nil {
+		return err
+	}
+	for i := range csrs.Items {
+		csr := &csrs.Items[i]
+		//the list selector is not trusted to never delete the CSRs of another cluster
+		if csr.GetLabels()[csrClusterNameLabel] != managedCluster.Name {
+			continue
+		}
+		log.Info("Deleting the CSR of the deleted cluster", "csr", csr.Name, "managedcluster", managedCluster.Name)
+		if err := c.Delete(context.TODO(), csr); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
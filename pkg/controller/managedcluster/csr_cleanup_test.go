@@ -0,0 +1,55 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	certificatesv1beta1 "k8s.io/api/certificates/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_deleteClusterCSRs(t *testing.T) {
+	newCSR := func(name, clusterName string) *certificatesv1beta1.CertificateSigningRequest {
+		csr := &certificatesv1beta1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		if clusterName != "" {
+			csr.Labels = map[string]string{csrClusterNameLabel: clusterName}
+		}
+		return csr
+	}
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+		},
+	}
+	c := fake.NewFakeClientWithScheme(scheme.Scheme,
+		newCSR("cluster1-pending", "cluster1"),
+		newCSR("cluster1-approved", "cluster1"),
+		newCSR("cluster10", "cluster10"),
+		newCSR("other", "cluster2"),
+		newCSR("unlabeled", ""),
+	)
+
+	if err := deleteClusterCSRs(c, managedCluster); err != nil {
+		t.Fatalf("deleteClusterCSRs() error = %v", err)
+	}
+	csrs := &certificatesv1beta1.CertificateSigningRequestList{}
+	if err := c.List(context.TODO(), csrs); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, csr := range csrs.Items {
+		got[csr.Name] = true
+	}
+	if len(got) != 3 || !got["cluster10"] || !got["other"] || !got["unlabeled"] {
+		t.Errorf("expected only the CSRs of cluster1 to be deleted, remaining %v", got)
+	}
+}
@@ -7,9 +7,16 @@ package managedcluster
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +28,18 @@ import (
 const syncsetNamePostfix = "-klusterlet"
 const syncsetCRDSPostfix = "-crds"
 
+const (
+	ConditionKlusterletSyncSetsDeleted string = "KlusterletSyncSetsDeleted"
+	reasonKlusterletSyncSetsDeleted    string = "KlusterletSyncSetsDeleted"
+	reasonKlusterletSyncSetsDeleting   string = "KlusterletSyncSetsDeleting"
+	reasonKlusterletSyncSetsKept       string = "KlusterletSyncSetsKept"
+	reasonNoKlusterletSyncSets         string = "NoKlusterletSyncSets"
+)
+
+//keepKlusterletSyncSetsAnnotation set to "true" on a ManagedCluster prevents the deletion of its
+//klusterlet syncsets, for clusters which are not yet fully migrated to manifestworks
+const keepKlusterletSyncSetsAnnotation = "import.open-cluster-management.io/keep-klusterlet-syncsets"
+
 func syncSetNsN(managedCluster *clusterv1.ManagedCluster) (types.NamespacedName, error) {
 	if managedCluster == nil {
 		return types.NamespacedName{}, fmt.Errorf("managedCluster is nil")
@@ -33,29 +52,116 @@ func syncSetNsN(managedCluster *clusterv1.ManagedCluster) (types.NamespacedName,
 	}, nil
 }
 
+//syncSetsCleanup is the outcome of the deletion of the klusterlet syncsets of a cluster
+type syncSetsCleanup struct {
+	//deleted are the names of the syncsets deleted
+	deleted []string
+	//pending is true if a syncset waits for hive to process its upsert mode before being deleted
+	pending bool
+	//kept is true if the cluster opted out of the deletion
+	kept bool
+	//syncSetAPIMissing is true if the SyncSet kind is not served by the hub, Hive is not installed
+	syncSetAPIMissing bool
+}
+
 func deleteKlusterletSyncSets(
 	client client.Client,
 	managedCluster *clusterv1.ManagedCluster,
-) (res reconcile.Result, err error) {
+) (res reconcile.Result, cleanup syncSetsCleanup, err error) {
 	ssNsN, err := syncSetNsN(managedCluster)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, cleanup, err
 	}
 
-	//Delete the CRD syncset
-	result, err := deleteKlusterletSyncSet(client, ssNsN.Name+syncsetCRDSPostfix, ssNsN.Namespace)
-	if err != nil {
-		return result, err
+	if keepKlusterletSyncSets(managedCluster) {
+		klog.V(4).Infof("Keeping the klusterlet syncsets of %s as requested by the %s annotation",
+			managedCluster.Name, keepKlusterletSyncSetsAnnotation)
+		cleanup.kept = true
+		return reconcile.Result{}, cleanup, nil
 	}
 
-	//Delete the YAML syncset
-	return deleteKlusterletSyncSet(client, ssNsN.Name, ssNsN.Namespace)
+	//Delete the CRD syncset then the YAML syncset
+	for _, name := range []string{ssNsN.Name + syncsetCRDSPostfix, ssNsN.Name} {
+		var result reconcile.Result
+		result, err = deleteKlusterletSyncSet(client, name, ssNsN.Namespace, &cleanup)
+		if err != nil || cleanup.syncSetAPIMissing {
+			break
+		}
+		if result.Requeue {
+			res = result
+		}
+	}
+	if len(cleanup.deleted) != 0 {
+		klog.Infof("Deleted %d klusterlet syncsets of %s: %s", len(cleanup.deleted), managedCluster.Name,
+			strings.Join(cleanup.deleted, ", "))
+	}
+	return res, cleanup, err
+}
+
+//reportKlusterletSyncSetsCleanup sets the KlusterletSyncSetsDeleted condition to track the migration of
+//the cluster from the syncsets to the manifestworks, the condition is only patched when it changes.
+//Once True, it is kept as is when there is no syncset left to delete.
+func (r *ReconcileManagedCluster) reportKlusterletSyncSetsCleanup(
+	managedCluster *clusterv1.ManagedCluster,
+	cleanup syncSetsCleanup,
+) error {
+	if len(cleanup.deleted) != 0 {
+		r.recordEvent(managedCluster, corev1.EventTypeNormal, reasonKlusterletSyncSetsDeleted,
+			fmt.Sprintf("Deleted the klusterlet syncsets %s", strings.Join(cleanup.deleted, ", ")))
+	}
+	condition := metav1.Condition{
+		Type:   ConditionKlusterletSyncSetsDeleted,
+		Status: metav1.ConditionTrue,
+	}
+	switch {
+	case cleanup.kept:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonKlusterletSyncSetsKept
+		condition.Message = fmt.Sprintf("The klusterlet syncsets are kept as requested by the %s annotation",
+			keepKlusterletSyncSetsAnnotation)
+	case cleanup.pending:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonKlusterletSyncSetsDeleting
+		condition.Message = "Waiting for Hive to process the upsert mode of the klusterlet syncsets before deleting them"
+	case len(cleanup.deleted) != 0:
+		condition.Reason = reasonKlusterletSyncSetsDeleted
+		condition.Message = fmt.Sprintf("Deleted the klusterlet syncsets %s", strings.Join(cleanup.deleted, ", "))
+	case meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionKlusterletSyncSetsDeleted):
+		return nil
+	case cleanup.syncSetAPIMissing:
+		condition.Reason = reasonNoKlusterletSyncSets
+		condition.Message = "The SyncSet API is not installed on the hub, there is no klusterlet syncset to delete"
+	default:
+		condition.Reason = reasonNoKlusterletSyncSets
+		condition.Message = "There is no klusterlet syncset to delete"
+	}
+	if c := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionKlusterletSyncSetsDeleted); c != nil &&
+		c.Status == condition.Status && c.Reason == condition.Reason && c.Message == condition.Message {
+		return nil
+	}
+	return r.setCondition(managedCluster, condition)
+}
+
+//keepKlusterletSyncSets returns true if the ManagedCluster opted out of the syncsets deletion
+func keepKlusterletSyncSets(managedCluster *clusterv1.ManagedCluster) bool {
+	v, ok := managedCluster.GetAnnotations()[keepKlusterletSyncSetsAnnotation]
+	if !ok {
+		return false
+	}
+	keep, err := strconv.ParseBool(v)
+	if err != nil {
+		klog.Infof("Invalid value %q for the annotation %s on %s, the syncsets will be deleted",
+			v, keepKlusterletSyncSetsAnnotation, managedCluster.Name)
+		return false
+	}
+	return keep
 }
 
 func deleteKlusterletSyncSet(
 	client client.Client,
 	name string,
 	namespace string,
+	cleanup *syncSetsCleanup,
 ) (res reconcile.Result, err error) {
 	oldSyncSet := &hivev1.SyncSet{}
 	err = client.Get(context.TODO(),
@@ -64,26 +170,42 @@ func deleteKlusterletSyncSet(
 			Namespace: namespace,
 		},
 		oldSyncSet)
-	if err == nil {
-		klog.Infof("SyncSet %s found, will delete it with upsert mode", oldSyncSet.GetName())
-		//Update the syncset to set upsert mode.
-		if oldSyncSet.Spec.ResourceApplyMode != hivev1.UpsertResourceApplyMode {
-			klog.Infof("SyncSet %s set with upsert mode", oldSyncSet.GetName())
-			oldSyncSet.Spec.ResourceApplyMode = hivev1.UpsertResourceApplyMode
-			err := client.Update(context.TODO(), oldSyncSet)
-			if err != nil {
-				return reconcile.Result{}, err
-			}
-			klog.Infof("SyncSet %s set with upsert mode, requeue to wait hive to process", oldSyncSet.GetName())
-			return reconcile.Result{Requeue: true, RequeueAfter: 1 * time.Minute}, nil
-		}
-		//Now delete syncset.
-		klog.Infof("SyncSet %s will be deleted", oldSyncSet.GetName())
-		err = client.Delete(context.TODO(), oldSyncSet)
+	switch {
+	case errors.IsNotFound(err):
+		return reconcile.Result{}, nil
+	case isSyncSetCRDMissing(err):
+		klog.V(4).Infof("The SyncSet kind is not served by the hub, no syncset %s to delete", name)
+		cleanup.syncSetAPIMissing = true
+		return reconcile.Result{}, nil
+	case err != nil:
+		return reconcile.Result{}, err
+	}
+	klog.Infof("SyncSet %s found, will delete it with upsert mode", oldSyncSet.GetName())
+	//Update the syncset to set upsert mode.
+	if oldSyncSet.Spec.ResourceApplyMode != hivev1.UpsertResourceApplyMode {
+		klog.Infof("SyncSet %s set with upsert mode", oldSyncSet.GetName())
+		oldSyncSet.Spec.ResourceApplyMode = hivev1.UpsertResourceApplyMode
+		err := client.Update(context.TODO(), oldSyncSet)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
-		klog.Infof("SyncSet %s deleted", oldSyncSet.GetName())
+		klog.Infof("SyncSet %s set with upsert mode, requeue to wait hive to process", oldSyncSet.GetName())
+		cleanup.pending = true
+		return reconcile.Result{Requeue: true, RequeueAfter: 1 * time.Minute}, nil
 	}
+	//Now delete syncset.
+	klog.Infof("SyncSet %s will be deleted", oldSyncSet.GetName())
+	err = client.Delete(context.TODO(), oldSyncSet)
+	if err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	klog.Infof("SyncSet %s deleted", oldSyncSet.GetName())
+	cleanup.deleted = append(cleanup.deleted, oldSyncSet.GetName())
 	return reconcile.Result{}, nil
 }
+
+//isSyncSetCRDMissing returns true if the error is due to the SyncSet kind being unknown, on a hub
+//without Hive
+func isSyncSetCRDMissing(err error) bool {
+	return meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err)
+}
@@ -11,6 +11,7 @@ import (
 
 	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -106,9 +107,10 @@ func Test_deleteSyncSets(t *testing.T) {
 		managedCluster *clusterv1.ManagedCluster
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name     string
+		args     args
+		wantErr  bool
+		wantKept bool
 	}{
 		{
 			name: "nil cluster",
@@ -130,15 +132,33 @@ func Test_deleteSyncSets(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "syncsets kept by annotation",
+			args: args{
+				client: fake.NewFakeClientWithScheme(testScheme, []runtime.Object{
+					crds, yamls,
+				}...),
+				managedCluster: &clusterv1.ManagedCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "deletesyncset",
+						Annotations: map[string]string{
+							keepKlusterletSyncSetsAnnotation: "true",
+						},
+					},
+				},
+			},
+			wantErr:  false,
+			wantKept: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			//Set upsert
-			if _, err := deleteKlusterletSyncSets(tt.args.client, tt.args.managedCluster); (err != nil) != tt.wantErr {
+			if _, _, err := deleteKlusterletSyncSets(tt.args.client, tt.args.managedCluster); (err != nil) != tt.wantErr {
 				t.Errorf("deleteSyncSets() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			//Delete syncset as upsert is set
-			if _, err := deleteKlusterletSyncSets(tt.args.client, tt.args.managedCluster); (err != nil) != tt.wantErr {
+			if _, _, err := deleteKlusterletSyncSets(tt.args.client, tt.args.managedCluster); (err != nil) != tt.wantErr {
 				t.Errorf("deleteSyncSets() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if !tt.wantErr {
@@ -148,8 +168,8 @@ func Test_deleteSyncSets(t *testing.T) {
 						Name:      "deletesyncset" + syncsetNamePostfix + syncsetCRDSPostfix,
 						Namespace: "deletesyncset",
 					}, crds)
-				if err == nil {
-					t.Error("deletesyncset crds manifest not deleted")
+				if (err == nil) != tt.wantKept {
+					t.Errorf("deletesyncset crds manifest kept = %v, wantKept %v", err == nil, tt.wantKept)
 				}
 				yamls := &hivev1.SyncSet{}
 				err = tt.args.client.Get(context.TODO(),
@@ -157,10 +177,152 @@ func Test_deleteSyncSets(t *testing.T) {
 						Name:      "deletesyncset" + syncsetNamePostfix,
 						Namespace: "deletesyncset",
 					}, yamls)
-				if err == nil {
-					t.Error("deletesyncset yamls manifest not deleted")
+				if (err == nil) != tt.wantKept {
+					t.Errorf("deletesyncset yamls manifest kept = %v, wantKept %v", err == nil, tt.wantKept)
+				}
+			}
+		})
+	}
+}
+
+func Test_deleteSyncSets_report(t *testing.T) {
+	testScheme := scheme.Scheme
+	testScheme.AddKnownTypes(hivev1.SchemeGroupVersion, &hivev1.SyncSet{})
+	testScheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	//A hub without Hive doesn't serve the SyncSet kind
+	noHiveScheme := runtime.NewScheme()
+	if err := clusterv1.Install(noHiveScheme); err != nil {
+		t.Fatal(err)
+	}
+
+	newSyncSet := func(name string, mode hivev1.SyncSetResourceApplyMode) *hivev1.SyncSet {
+		return &hivev1.SyncSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "deletesyncset",
+			},
+			Spec: hivev1.SyncSetSpec{
+				SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+					ResourceApplyMode: mode,
+				},
+			},
+		}
+	}
+	crdsName := "deletesyncset" + syncsetNamePostfix + syncsetCRDSPostfix
+	yamlsName := "deletesyncset" + syncsetNamePostfix
+
+	tests := []struct {
+		name        string
+		scheme      *runtime.Scheme
+		objs        []runtime.Object
+		annotations map[string]string
+		wantDeleted []string
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+	}{
+		{
+			name:       "no syncset",
+			scheme:     testScheme,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reasonNoKlusterletSyncSets,
+		},
+		{
+			name:       "hive not installed",
+			scheme:     noHiveScheme,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reasonNoKlusterletSyncSets,
+		},
+		{
+			name:   "syncsets deleted",
+			scheme: testScheme,
+			objs: []runtime.Object{
+				newSyncSet(crdsName, hivev1.UpsertResourceApplyMode),
+				newSyncSet(yamlsName, hivev1.UpsertResourceApplyMode),
+			},
+			wantDeleted: []string{crdsName, yamlsName},
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  reasonKlusterletSyncSetsDeleted,
+		},
+		{
+			name:   "waiting for the upsert mode",
+			scheme: testScheme,
+			objs: []runtime.Object{
+				newSyncSet(crdsName, hivev1.UpsertResourceApplyMode),
+				newSyncSet(yamlsName, hivev1.SyncResourceApplyMode),
+			},
+			wantDeleted: []string{crdsName},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  reasonKlusterletSyncSetsDeleting,
+		},
+		{
+			name:        "syncsets kept",
+			scheme:      testScheme,
+			objs:        []runtime.Object{newSyncSet(crdsName, hivev1.UpsertResourceApplyMode)},
+			annotations: map[string]string{keepKlusterletSyncSetsAnnotation: "true"},
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  reasonKlusterletSyncSetsKept,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "deletesyncset",
+					Annotations: tt.annotations,
+				},
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(tt.scheme, append(tt.objs, managedCluster)...),
+				scheme: tt.scheme,
+			}
+			_, cleanup, err := deleteKlusterletSyncSets(r.client, managedCluster)
+			if err != nil {
+				t.Fatalf("deleteKlusterletSyncSets() unexpected error %v", err)
+			}
+			if len(cleanup.deleted) != 0 || len(tt.wantDeleted) != 0 {
+				if !reflect.DeepEqual(cleanup.deleted, tt.wantDeleted) {
+					t.Errorf("deleted = %v, want %v", cleanup.deleted, tt.wantDeleted)
 				}
 			}
+			if err := r.reportKlusterletSyncSetsCleanup(managedCluster, cleanup); err != nil {
+				t.Fatal(err)
+			}
+			got := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "deletesyncset"}, got); err != nil {
+				t.Fatal(err)
+			}
+			c := meta.FindStatusCondition(got.Status.Conditions, ConditionKlusterletSyncSetsDeleted)
+			if c == nil || c.Status != tt.wantStatus || c.Reason != tt.wantReason {
+				t.Errorf("condition = %v, want %s %s", c, tt.wantStatus, tt.wantReason)
+			}
 		})
 	}
 }
+
+func TestReconcileManagedCluster_reportKlusterletSyncSetsCleanup_keepsDeleted(t *testing.T) {
+	testScheme := scheme.Scheme
+	testScheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "deletesyncset",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testScheme, managedCluster),
+		scheme: testScheme,
+	}
+	if err := r.reportKlusterletSyncSetsCleanup(managedCluster,
+		syncSetsCleanup{deleted: []string{"deletesyncset-klusterlet"}}); err != nil {
+		t.Fatal(err)
+	}
+	//The next reconciles find no syncset, the record of the deletion is kept
+	if err := r.reportKlusterletSyncSetsCleanup(managedCluster, syncSetsCleanup{}); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionKlusterletSyncSetsDeleted)
+	if c == nil || c.Reason != reasonKlusterletSyncSetsDeleted {
+		t.Errorf("expected the deletion to be kept in the condition, got %v", c)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+//defaultMaxConcurrentDeletions is the number of clusters cleaned up at the same time when
+//MAX_CONCURRENT_DELETIONS is not set
+const defaultMaxConcurrentDeletions = 10
+
+//deletionThrottledRequeueAfter is the delay before a throttled deletion tries again, jittered so the
+//waiting clusters don't retry together
+const deletionThrottledRequeueAfter = 5 * time.Second
+
+//deletionWaiterExpiry forgets a waiting cluster which didn't try again, its ManagedCluster is gone
+const deletionWaiterExpiry = 10 * deletionThrottledRequeueAfter
+
+//deletionSlotLease frees the slot of a cleanup which didn't try again, longer than the requeues of a
+//cleanup waiting on the managed cluster
+const deletionSlotLease = 5 * time.Minute
+
+//deletionWaiter is a cluster waiting for a deletion slot
+type deletionWaiter struct {
+	first time.Time
+	last  time.Time
+}
+
+//deletionLimiter bounds the number of clusters cleaned up at the same time, so the deletion of a whole
+//fleet doesn't overwhelm the apiserver. The slots are granted in the order of the first attempt, so
+//every deletion makes progress. The slots are leased, each attempt of the cleanup renews its lease.
+type deletionLimiter struct {
+	mu       sync.Mutex
+	inFlight map[string]time.Time
+	waiting  map[string]*deletionWaiter
+}
+
+//deletions limits the cleanups of the controller, shared by the concurrent reconciles
+var deletions = &deletionLimiter{
+	inFlight: map[string]time.Time{},
+	waiting:  map[string]*deletionWaiter{},
+}
+
+func maxConcurrentDeletions() int {
+	return getEnvInt(maxConcurrentDeletionsEnvVarName, defaultMaxConcurrentDeletions)
+}
+
+//acquire returns true if the cleanup of the cluster can start or is in progress, it must then be released
+//once the finalizer is removed, the cluster is gone or the cleanup fails, otherwise the slot is freed
+//deletionSlotLease after the last attempt. If no slot is free the cluster waits for its turn and tries
+//again after deletionThrottledRequeueAfter.
+func (l *deletionLimiter) acquire(clusterName string, max int, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.inFlight[clusterName]; ok {
+		l.inFlight[clusterName] = now
+		return true
+	}
+	for name, renewed := range l.inFlight {
+		if now.Sub(renewed) > deletionSlotLease {
+			delete(l.inFlight, name)
+		}
+	}
+	for name, w := range l.waiting {
+		if now.Sub(w.last) > deletionWaiterExpiry {
+			delete(l.waiting, name)
+		}
+	}
+	w, ok := l.waiting[clusterName]
+	if !ok {
+		w = &deletionWaiter{first: now}
+		l.waiting[clusterName] = w
+	}
+	w.last = now
+
+	free := max - len(l.inFlight)
+	if free <= 0 {
+		return false
+	}
+	ahead := 0
+	for name, other := range l.waiting {
+		if other.first.Before(w.first) || (other.first.Equal(w.first) && name < clusterName) {
+			ahead++
+		}
+	}
+	if ahead >= free {
+		return false
+	}
+	delete(l.waiting, clusterName)
+	l.inFlight[clusterName] = now
+	return true
+}
+
+//release frees the slot of the cluster, it's a no-op if the cluster doesn't hold one
+func (l *deletionLimiter) release(clusterName string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.inFlight, clusterName)
+}
+
+//throttledRequeueAfter returns the jittered delay before a throttled deletion tries again
+func throttledRequeueAfter() time.Duration {
+	return wait.Jitter(deletionThrottledRequeueAfter, 1.0)
+}
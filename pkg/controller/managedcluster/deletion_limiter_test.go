@@ -0,0 +1,434 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestDeletionLimiter() *deletionLimiter {
+	return &deletionLimiter{
+		inFlight: map[string]time.Time{},
+		waiting:  map[string]*deletionWaiter{},
+	}
+}
+
+func Test_deletionLimiter_order(t *testing.T) {
+	l := newTestDeletionLimiter()
+	now := time.Now()
+
+	if !l.acquire("cluster1", 1, now) {
+		t.Fatalf("expected cluster1 to get the slot")
+	}
+	if l.acquire("cluster2", 1, now.Add(time.Second)) {
+		t.Errorf("expected cluster2 to wait")
+	}
+	if l.acquire("cluster3", 1, now.Add(2*time.Second)) {
+		t.Errorf("expected cluster3 to wait")
+	}
+	l.release("cluster1")
+
+	//cluster2 waits longer than cluster3, it gets the slot first
+	if l.acquire("cluster3", 1, now.Add(3*time.Second)) {
+		t.Errorf("expected cluster3 to wait for cluster2")
+	}
+	if !l.acquire("cluster2", 1, now.Add(3*time.Second)) {
+		t.Errorf("expected cluster2 to get the slot")
+	}
+	l.release("cluster2")
+	if !l.acquire("cluster3", 1, now.Add(4*time.Second)) {
+		t.Errorf("expected cluster3 to get the slot")
+	}
+	l.release("cluster3")
+
+	//A waiting cluster which doesn't try again is forgotten
+	if !l.acquire("cluster4", 1, now) {
+		t.Fatalf("expected cluster4 to get the slot")
+	}
+	if l.acquire("gone", 1, now) {
+		t.Errorf("expected gone to wait")
+	}
+	l.release("cluster4")
+	if !l.acquire("cluster5", 1, now.Add(deletionWaiterExpiry+time.Second)) {
+		t.Errorf("expected cluster5 to get the slot of the expired waiter")
+	}
+}
+
+func Test_deletionLimiter_lease(t *testing.T) {
+	l := newTestDeletionLimiter()
+	now := time.Now()
+
+	if !l.acquire("cluster1", 1, now) {
+		t.Fatalf("expected cluster1 to get the slot")
+	}
+	//cluster1 renews its lease while it waits on the managed cluster
+	for i := 1; i <= 10; i++ {
+		at := now.Add(time.Duration(i) * time.Minute)
+		if !l.acquire("cluster1", 1, at) {
+			t.Fatalf("expected cluster1 to keep its slot")
+		}
+		if l.acquire("cluster2", 1, at) {
+			t.Fatalf("expected cluster2 to wait")
+		}
+	}
+	//cluster1 doesn't try again, its slot is given to cluster2 once the lease expired
+	last := now.Add(10 * time.Minute)
+	if l.acquire("cluster2", 1, last.Add(deletionSlotLease-time.Second)) {
+		t.Errorf("expected cluster2 to wait for the lease of cluster1")
+	}
+	if !l.acquire("cluster2", 1, last.Add(deletionSlotLease+time.Second)) {
+		t.Errorf("expected cluster2 to get the slot of the expired lease")
+	}
+}
+
+//Test_deletionLimiter_massDeletion simulates the deletion of a whole fleet, each round every cluster not
+//cleaned up yet reconciles once and the granted cleanups complete
+func Test_deletionLimiter_massDeletion(t *testing.T) {
+	const clusters = 100
+	const max = 10
+	l := newTestDeletionLimiter()
+	now := time.Now()
+
+	done := map[string]bool{}
+	rounds := 0
+	for len(done) < clusters {
+		rounds++
+		if rounds > clusters {
+			t.Fatalf("the deletions are not making progress, %d clusters deleted", len(done))
+		}
+		granted := []string{}
+		for i := 0; i < clusters; i++ {
+			name := fmt.Sprintf("cluster%03d", i)
+			if done[name] {
+				continue
+			}
+			if l.acquire(name, max, now) {
+				granted = append(granted, name)
+			}
+		}
+		if len(granted) > max {
+			t.Fatalf("round %d: %d cleanups in flight, want at most %d", rounds, len(granted), max)
+		}
+		for _, name := range granted {
+			l.release(name)
+			done[name] = true
+		}
+		now = now.Add(deletionThrottledRequeueAfter)
+	}
+	if rounds != clusters/max {
+		t.Errorf("expected the fleet to be deleted in %d rounds, got %d", clusters/max, rounds)
+	}
+}
+
+func BenchmarkDeletionLimiter_massDeletion(b *testing.B) {
+	const max = 10
+	l := newTestDeletionLimiter()
+	var inFlight, peak int64
+	var next int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			name := fmt.Sprintf("cluster%d", atomic.AddInt64(&next, 1)%1000)
+			if !l.acquire(name, max, time.Now()) {
+				continue
+			}
+			n := atomic.AddInt64(&inFlight, 1)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+					break
+				}
+			}
+			atomic.AddInt64(&inFlight, -1)
+			l.release(name)
+		}
+	})
+	if peak > max {
+		b.Errorf("%d cleanups in flight, want at most %d", peak, max)
+	}
+}
+
+func Test_deletionLimiter_concurrent(t *testing.T) {
+	const max = 3
+	l := newTestDeletionLimiter()
+	var inFlight, peak int64
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			for !l.acquire(name, max, time.Now()) {
+				time.Sleep(time.Millisecond)
+			}
+			n := atomic.AddInt64(&inFlight, 1)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt64(&inFlight, -1)
+			l.release(name)
+		}(fmt.Sprintf("cluster%d", i))
+	}
+	wg.Wait()
+	if peak > max {
+		t.Errorf("%d cleanups in flight, want at most %d", peak, max)
+	}
+}
+
+//failingListClient fails the lists, it stands for an apiserver error during a cleanup
+type failingListClient struct {
+	client.Client
+}
+
+func (c *failingListClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	return fmt.Errorf("apiserver unavailable")
+}
+
+func newDeletedManagedCluster(name string, available metav1.ConditionStatus) *clusterv1.ManagedCluster {
+	now := metav1.Now()
+	return &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Finalizers:        []string{managedClusterFinalizer},
+			DeletionTimestamp: &now,
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: available,
+				},
+			},
+		},
+	}
+}
+
+//Test_managedClusterDeletion_massDeletion deletes a fleet of online clusters at once, each round every
+//cluster not cleaned up yet reconciles once and the klusterlets whose manifestwork is deleted are removed
+func Test_managedClusterDeletion_massDeletion(t *testing.T) {
+	const clusters = 20
+	const max = 5
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	os.Setenv(maxConcurrentDeletionsEnvVarName, fmt.Sprintf("%d", max))
+	defer os.Unsetenv(maxConcurrentDeletionsEnvVarName)
+	defaultDeletions := deletions
+	deletions = newTestDeletionLimiter()
+	defer func() { deletions = defaultDeletions }()
+
+	names := []string{}
+	objs := []runtime.Object{}
+	for i := 0; i < clusters; i++ {
+		name := fmt.Sprintf("cluster%02d", i)
+		names = append(names, name)
+		objs = append(objs,
+			newDeletedManagedCluster(name, metav1.ConditionTrue),
+			&workv1.ManifestWork{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name + manifestWorkNamePostfix,
+					Namespace: name,
+				},
+			})
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, objs...),
+		scheme: testscheme,
+	}
+	getManagedCluster := func(name string) *clusterv1.ManagedCluster {
+		mc := &clusterv1.ManagedCluster{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name}, mc); err != nil {
+			t.Fatal(err)
+		}
+		return mc
+	}
+
+	done := map[string]bool{}
+	for rounds := 1; len(done) < clusters; rounds++ {
+		if rounds > 2*clusters {
+			t.Fatalf("the deletions are not making progress, %d clusters deleted", len(done))
+		}
+		for _, name := range names {
+			if done[name] {
+				continue
+			}
+			if _, err := r.managedClusterDeletion(getManagedCluster(name)); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if n := len(deletions.inFlight); n > max {
+				t.Fatalf("round %d: %d cleanups in flight, want at most %d", rounds, n, max)
+			}
+			if len(getManagedCluster(name).Finalizers) == 0 {
+				done[name] = true
+			}
+		}
+		//The klusterlets whose manifestwork is deleted are removed, their cluster goes offline
+		for _, name := range names {
+			err := r.client.Get(context.TODO(),
+				types.NamespacedName{Name: name + manifestWorkNamePostfix, Namespace: name}, &workv1.ManifestWork{})
+			if done[name] || !errors.IsNotFound(err) {
+				continue
+			}
+			mc := getManagedCluster(name)
+			meta.SetStatusCondition(&mc.Status.Conditions, metav1.Condition{
+				Type:   clusterv1.ManagedClusterConditionAvailable,
+				Status: metav1.ConditionUnknown,
+				Reason: "ManagedClusterLeaseUpdateStopped",
+			})
+			if err := r.client.Status().Update(context.TODO(), mc); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if n := len(deletions.inFlight); n != 0 {
+		t.Errorf("expected all the slots to be released, %d in flight", n)
+	}
+}
+
+//Test_managedClusterDeletion_failedCleanup a failed cleanup gives its slot back to the waiting clusters
+func Test_managedClusterDeletion_failedCleanup(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	os.Setenv(maxConcurrentDeletionsEnvVarName, "1")
+	defer os.Unsetenv(maxConcurrentDeletionsEnvVarName)
+	defaultDeletions := deletions
+	deletions = newTestDeletionLimiter()
+	defer func() { deletions = defaultDeletions }()
+
+	r := &ReconcileManagedCluster{
+		client: &failingListClient{Client: fake.NewFakeClientWithScheme(testscheme,
+			newDeletedManagedCluster("cluster1", metav1.ConditionTrue))},
+		scheme: testscheme,
+	}
+	if _, err := r.managedClusterDeletion(newDeletedManagedCluster("cluster1", metav1.ConditionTrue)); err == nil {
+		t.Fatal("expected the cleanup of cluster1 to fail")
+	}
+	if !deletions.acquire("cluster2", 1, time.Now()) {
+		t.Errorf("expected cluster2 to get the slot of the failed cleanup")
+	}
+}
+
+//Test_managedClusterDeletion_deletionSlots deletes more online clusters than the limit, a cleanup waiting
+//for the klusterlet removal keeps its slot until the finalizer is removed
+func Test_managedClusterDeletion_deletionSlots(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	os.Setenv(maxConcurrentDeletionsEnvVarName, "2")
+	defer os.Unsetenv(maxConcurrentDeletionsEnvVarName)
+	defaultDeletions := deletions
+	deletions = newTestDeletionLimiter()
+	defer func() { deletions = defaultDeletions }()
+
+	now := metav1.Now()
+	objs := []runtime.Object{}
+	for _, name := range []string{"cluster1", "cluster2", "cluster3"} {
+		objs = append(objs,
+			&clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Finalizers:        []string{managedClusterFinalizer},
+					DeletionTimestamp: &now,
+				},
+				Status: clusterv1.ManagedClusterStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:   clusterv1.ManagedClusterConditionAvailable,
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+			},
+			&workv1.ManifestWork{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name + manifestWorkNamePostfix,
+					Namespace: name,
+				},
+			})
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, objs...),
+		scheme: testscheme,
+	}
+	getManagedCluster := func(name string) *clusterv1.ManagedCluster {
+		mc := &clusterv1.ManagedCluster{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name}, mc); err != nil {
+			t.Fatal(err)
+		}
+		return mc
+	}
+	klusterletManifestWorkExists := func(name string) bool {
+		return r.client.Get(context.TODO(), types.NamespacedName{Name: name + manifestWorkNamePostfix, Namespace: name},
+			&workv1.ManifestWork{}) == nil
+	}
+
+	//cluster1 and cluster2 start their cleanup and wait for the removal of their klusterlet
+	for _, name := range []string{"cluster1", "cluster2"} {
+		res, err := r.managedClusterDeletion(getManagedCluster(name))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !res.Requeue {
+			t.Errorf("expected the cleanup of %s to wait for the klusterlet removal, got %+v", name, res)
+		}
+		if klusterletManifestWorkExists(name) {
+			t.Errorf("expected the klusterlet manifestwork of %s to be deleted", name)
+		}
+	}
+
+	//The slots are still held, cluster3 waits
+	for i := 0; i < 2; i++ {
+		res, err := r.managedClusterDeletion(getManagedCluster("cluster3"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if res.Requeue || res.RequeueAfter == 0 {
+			t.Errorf("expected the cleanup of cluster3 to be throttled, got %+v", res)
+		}
+		if !klusterletManifestWorkExists("cluster3") {
+			t.Errorf("expected the cleanup of cluster3 not to start")
+		}
+	}
+
+	//The finalizer of cluster1 is removed, its slot is given to cluster3
+	mc := getManagedCluster("cluster1")
+	mc.Annotations = map[string]string{klusterletProvisionedExternallyAnnotation: "true"}
+	if err := r.client.Update(context.TODO(), mc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.managedClusterDeletion(getManagedCluster("cluster1")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if finalizers := getManagedCluster("cluster1").Finalizers; len(finalizers) != 0 {
+		t.Fatalf("expected the finalizer of cluster1 to be removed, got %v", finalizers)
+	}
+	if _, err := r.managedClusterDeletion(getManagedCluster("cluster3")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if klusterletManifestWorkExists("cluster3") {
+		t.Errorf("expected the cleanup of cluster3 to start once cluster1 released its slot")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//clusterIDAnnotation records the UID of the kube-system namespace of the cluster the ManagedCluster
+//was imported to, it identifies the physical cluster whatever the API server URL used
+const clusterIDAnnotation = "import.open-cluster-management.io/cluster-id"
+
+const (
+	ConditionDuplicateClusterImport string = "DuplicateClusterImport"
+	reasonDuplicateClusterID        string = "DuplicateClusterID"
+	reasonUniqueClusterID           string = "UniqueClusterID"
+)
+
+//getRemoteClusterID returns the UID of the kube-system namespace of the managed cluster
+func getRemoteClusterID(managedClusterClient client.Client) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := managedClusterClient.Get(context.TODO(), types.NamespacedName{Name: "kube-system"}, ns); err != nil {
+		return "", err
+	}
+	return string(ns.UID), nil
+}
+
+//findDuplicateManagedClusters returns the names of the other ManagedClusters imported to the same cluster
+func findDuplicateManagedClusters(c client.Client, managedCluster *clusterv1.ManagedCluster, clusterID string) ([]string, error) {
+	managedClusters := &clusterv1.ManagedClusterList{}
+	if err := c.List(context.TODO(), managedClusters); err != nil {
+		return nil, err
+	}
+	duplicates := []string{}
+	for _, mc := range managedClusters.Items {
+		if mc.Name != managedCluster.Name && mc.GetAnnotations()[clusterIDAnnotation] == clusterID {
+			duplicates = append(duplicates, mc.Name)
+		}
+	}
+	sort.Strings(duplicates)
+	return duplicates, nil
+}
+
+//checkDuplicateClusterImport records the ID of the cluster being imported on the ManagedCluster and flags
+//the ManagedClusters pointing to the same cluster, their klusterlets would fight. The check is best-effort,
+//the import goes on if the ID can't be read.
+func (r *ReconcileManagedCluster) checkDuplicateClusterImport(
+	managedCluster *clusterv1.ManagedCluster,
+	managedClusterClient client.Client,
+) error {
+	clusterID, err := getRemoteClusterID(managedClusterClient)
+	if err != nil || clusterID == "" {
+		log.Info("Unable to read the cluster ID, skipping the duplicate import check",
+			"managedcluster", managedCluster.Name, "error", fmt.Sprint(err))
+		return nil
+	}
+
+	if managedCluster.GetAnnotations()[clusterIDAnnotation] != clusterID {
+		patch := client.MergeFrom(managedCluster.DeepCopy())
+		annotations := managedCluster.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[clusterIDAnnotation] = clusterID
+		managedCluster.SetAnnotations(annotations)
+		if err := r.client.Patch(context.TODO(), managedCluster, patch); err != nil {
+			return err
+		}
+	}
+
+	duplicates, err := findDuplicateManagedClusters(r.client, managedCluster, clusterID)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) == 0 {
+		if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionDuplicateClusterImport) {
+			return nil
+		}
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionDuplicateClusterImport,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonUniqueClusterID,
+			Message: fmt.Sprintf("No other managed cluster is imported to the cluster %s", clusterID),
+		})
+	}
+	message := fmt.Sprintf("The managed clusters %s are imported to the same cluster %s, their klusterlets conflict",
+		strings.Join(append([]string{managedCluster.Name}, duplicates...), ","), clusterID)
+	log.Info(message, "managedcluster", managedCluster.Name)
+	r.recordEvent(managedCluster, corev1.EventTypeWarning, reasonDuplicateClusterID, message)
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:    ConditionDuplicateClusterImport,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonDuplicateClusterID,
+		Message: message,
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileManagedCluster_checkDuplicateClusterImport(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{}, &clusterv1.ManagedClusterList{})
+
+	kubeSystem := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kube-system",
+			UID:  types.UID("cluster-uid"),
+		},
+	}
+	newManagedCluster := func(name, clusterID string) *clusterv1.ManagedCluster {
+		mc := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		if clusterID != "" {
+			mc.Annotations = map[string]string{clusterIDAnnotation: clusterID}
+		}
+		return mc
+	}
+
+	tests := []struct {
+		name          string
+		hubObjs       []runtime.Object
+		remoteObjs    []runtime.Object
+		wantClusterID string
+		wantDuplicate bool
+	}{
+		{
+			name:          "cluster ID not readable",
+			hubObjs:       []runtime.Object{newManagedCluster("other", "cluster-uid")},
+			remoteObjs:    []runtime.Object{},
+			wantClusterID: "",
+			wantDuplicate: false,
+		},
+		{
+			name:          "unique cluster",
+			hubObjs:       []runtime.Object{newManagedCluster("other", "other-uid")},
+			remoteObjs:    []runtime.Object{kubeSystem},
+			wantClusterID: "cluster-uid",
+			wantDuplicate: false,
+		},
+		{
+			name:          "duplicate cluster",
+			hubObjs:       []runtime.Object{newManagedCluster("other", "cluster-uid")},
+			remoteObjs:    []runtime.Object{kubeSystem},
+			wantClusterID: "cluster-uid",
+			wantDuplicate: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := newManagedCluster("mycluster", "")
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, append(tt.hubObjs, managedCluster)...),
+				scheme: testscheme,
+			}
+			remoteClient := fake.NewFakeClientWithScheme(scheme.Scheme, tt.remoteObjs...)
+			if err := r.checkDuplicateClusterImport(managedCluster, remoteClient); err != nil {
+				t.Fatal(err)
+			}
+			mc := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+				t.Fatal(err)
+			}
+			if got := mc.GetAnnotations()[clusterIDAnnotation]; got != tt.wantClusterID {
+				t.Errorf("cluster ID = %s, want %s", got, tt.wantClusterID)
+			}
+			if got := meta.IsStatusConditionTrue(mc.Status.Conditions, ConditionDuplicateClusterImport); got != tt.wantDuplicate {
+				t.Errorf("condition %s = %v, want %v", ConditionDuplicateClusterImport, got, tt.wantDuplicate)
+			}
+		})
+	}
+}
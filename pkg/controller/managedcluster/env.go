@@ -0,0 +1,194 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	//offlineClusterRequeueIntervalEnvVarName is the interval at which an offline cluster with a pending
+	//auto-import is reconciled again after an import attempt which didn't fail, a failed attempt is retried
+	//with the backoff of the controller, "0" disables the periodic requeue
+	offlineClusterRequeueIntervalEnvVarName = "OFFLINE_CLUSTER_REQUEUE_INTERVAL"
+	//manifestWorkApplyStrategyEnvVarName selects how the klusterlet manifestworks are applied,
+	//"Update" (default) or "ServerSideApply"
+	manifestWorkApplyStrategyEnvVarName = "MANIFESTWORK_APPLY_STRATEGY"
+	//bootstrapLeastPrivilegeEnvVarName restricts the bootstrap service account to the managed cluster
+	//it registers, "false" (default) keeps the broader grant
+	bootstrapLeastPrivilegeEnvVarName = "BOOTSTRAP_SA_LEAST_PRIVILEGE"
+	//finalizerGraceTimeoutEnvVarName is the maximum time a deleted ManagedCluster waits on the cleanup
+	//before the controller finalizer is force-removed, "0" (default) waits forever
+	finalizerGraceTimeoutEnvVarName = "FINALIZER_GRACE_TIMEOUT"
+	//maxConcurrentReconcilesEnvVarName is the number of ManagedClusters reconciled in parallel, default 1
+	maxConcurrentReconcilesEnvVarName = "MAX_CONCURRENT_RECONCILES"
+	//manifestWorkConflictThresholdEnvVarName is the number of consecutive manifestwork apply conflicts
+	//after which the ManifestWorkApplyConflict condition is raised, default 5
+	manifestWorkConflictThresholdEnvVarName = "MANIFESTWORK_CONFLICT_THRESHOLD"
+	//bootstrapTokenAudienceEnvVarName is a comma separated list of audiences the bootstrap tokens
+	//are requested for, by default the service account token with the default audience is used
+	bootstrapTokenAudienceEnvVarName = "BOOTSTRAP_TOKEN_AUDIENCE"
+	//bootstrapTokenExpirationEnvVarName is the lifetime of the bootstrap tokens requested
+	//for an audience, default 8760h
+	bootstrapTokenExpirationEnvVarName = "BOOTSTRAP_TOKEN_EXPIRATION"
+	//maxCredentialRefreshesEnvVarName is the number of times the auto-import credentials are refreshed
+	//when they are rejected during an import, default 3
+	maxCredentialRefreshesEnvVarName = "AUTO_IMPORT_MAX_CREDENTIAL_REFRESHES"
+	//propagatedLabelsEnvVarName is a JSON object of labels added to the resources generated for all clusters
+	propagatedLabelsEnvVarName = "PROPAGATED_LABELS"
+	//propagatedAnnotationsEnvVarName is a JSON object of annotations added to the resources generated
+	//for all clusters
+	propagatedAnnotationsEnvVarName = "PROPAGATED_ANNOTATIONS"
+	//manifestWorkMaxSizeEnvVarName is the maximum serialized size in bytes of a klusterlet manifestwork,
+	//a larger manifestwork is split in parts, default 1048576
+	manifestWorkMaxSizeEnvVarName = "MANIFESTWORK_MAX_SIZE"
+	//allowInsecureSkipTLSVerifyEnvVarName allows the auto-import-secrets to skip the verification of the
+	//managed cluster certificate, "false" (default) rejects them
+	allowInsecureSkipTLSVerifyEnvVarName = "AUTO_IMPORT_ALLOW_INSECURE_SKIP_TLS_VERIFY"
+	//logVerbosityEnvVarName is a comma separated list of <subsystem>=<level> raising the log verbosity
+	//of a subsystem (import, manifestwork or namespace) only, for example "import=4"
+	logVerbosityEnvVarName = "LOG_VERBOSITY"
+	//reconcileFastPathEnvVarName skips applying the hub manifests, the import secret and the klusterlet
+	//manifestworks when the rendered configuration didn't change, "true" (default)
+	reconcileFastPathEnvVarName = "RECONCILE_FAST_PATH"
+	//importSecretNamespaceEnvVarName is the namespace holding the import secrets of all clusters,
+	//by default each import secret is in its cluster namespace
+	importSecretNamespaceEnvVarName = "IMPORT_SECRET_NAMESPACE"
+	//klusterletReadyTimeoutEnvVarName is the maximum time an auto-import waits for the klusterlet operator
+	//to be available on the managed cluster, default 1m, "0" skips the verification
+	klusterletReadyTimeoutEnvVarName = "KLUSTERLET_READY_TIMEOUT"
+	//importDrainTimeoutEnvVarName is the maximum time the imports in progress are given to complete
+	//on shutdown (SIGTERM), default 20s, to keep below the termination grace period of the pod
+	importDrainTimeoutEnvVarName = "IMPORT_DRAIN_TIMEOUT"
+	//managedClusterLabelSelectorEnvVarName is a label selector restricting the ManagedClusters reconciled
+	//by this controller, to shard the imports across several controllers, default all
+	managedClusterLabelSelectorEnvVarName = "MANAGED_CLUSTER_LABEL_SELECTOR"
+	//importConditionTypePrefixEnvVarName is a DNS subdomain namespacing the import condition type as
+	//<prefix>/ManagedClusterImportSucceeded, when several import controllers manage the same clusters
+	importConditionTypePrefixEnvVarName = "IMPORT_CONDITION_TYPE_PREFIX"
+	//klusterletUpgradeIntervalEnvVarName is the minimum delay between the upgrades of two klusterlets when
+	//the controller templates changed, default 10s, "0" upgrades all the klusterlets at once
+	klusterletUpgradeIntervalEnvVarName = "KLUSTERLET_UPGRADE_INTERVAL"
+	//allowAvailabilityOverrideEnvVarName honors the availability-override annotation forcing a cluster
+	//offline or online, for the tests only, "false" (default) ignores it
+	allowAvailabilityOverrideEnvVarName = "ALLOW_AVAILABILITY_OVERRIDE"
+	//protectedNamespacesEnvVarName is a comma separated list of namespaces the controller never labels
+	//nor deletes, a trailing "*" matches a prefix, default the system namespaces
+	protectedNamespacesEnvVarName = "PROTECTED_NAMESPACES"
+	//bootstrapCASecretEnvVarName is the <namespace>/<name> of a secret holding the CA bundle embedded in the
+	//bootstrap kubeconfigs, by default the API server certificate or the service account CA is embedded
+	bootstrapCASecretEnvVarName = "BOOTSTRAP_CA_SECRET"
+	//bootstrapCAConfigMapEnvVarName is the <namespace>/<name> of a configmap holding the CA bundle embedded
+	//in the bootstrap kubeconfigs, used if BOOTSTRAP_CA_SECRET is not set
+	bootstrapCAConfigMapEnvVarName = "BOOTSTRAP_CA_CONFIGMAP"
+	//bootstrapCAKeyEnvVarName is the key of the CA bundle in the secret or configmap, default ca.crt
+	bootstrapCAKeyEnvVarName = "BOOTSTRAP_CA_KEY"
+	//klusterletExtraManifestsEnvVarName is the <namespace>/<name> of a configmap holding extra manifests
+	//appended to the klusterlet manifestwork, a priorityClass for example
+	klusterletExtraManifestsEnvVarName = "KLUSTERLET_EXTRA_MANIFESTS"
+	//readOnlyEnvVarName runs the controller in shadow mode, "true" logs the writes of the reconcile of
+	//the ManagedClusters instead of doing them, default "false"
+	readOnlyEnvVarName = "READ_ONLY"
+	//autoImportRetryEnvVarName is the number of retries of the auto-import when the auto-import-secret
+	//has neither the autoImportRetry key nor the auto-import-retry annotation, default 5
+	autoImportRetryEnvVarName = "AUTO_IMPORT_RETRY"
+	//offlineGracePeriodEnvVarName is how long a cluster which was available is still considered online
+	//after its Available condition turned False or Unknown, default 0
+	offlineGracePeriodEnvVarName = "OFFLINE_GRACE_PERIOD"
+	//importVerificationDelayEnvVarName is the delay after a successful import before the controller checks
+	//once the klusterlet is still on the managed cluster and imports it again if it vanished, default 0
+	//(disabled)
+	importVerificationDelayEnvVarName = "IMPORT_VERIFICATION_DELAY"
+	//autoImportInitialDelayEnvVarName is the delay before the first auto-import attempt after the
+	//auto-import-secret is created or the cluster went offline, default 0
+	autoImportInitialDelayEnvVarName = "AUTO_IMPORT_INITIAL_DELAY"
+	//klusterletRegionRegistriesEnvVarName is the <namespace>/<name> of a configmap mapping the regions of
+	//the region annotation to the registry the klusterlet images are pulled from
+	klusterletRegionRegistriesEnvVarName = "KLUSTERLET_REGION_REGISTRIES"
+	//clockSkewThresholdEnvVarName is the clock skew between the hub and the managed cluster above which a
+	//failed import is reported with the reason ClockSkewDetected, default 1m, 0 disables the check
+	clockSkewThresholdEnvVarName = "CLOCK_SKEW_THRESHOLD"
+	//fieldManagerEnvVarName is the field manager of the writes of the controller on the hub and the managed
+	//clusters, default managedcluster-import-controller
+	fieldManagerEnvVarName = "FIELD_MANAGER"
+	//bootstrapCARepushIntervalEnvVarName is the delay between the reconciles of two clusters when the bootstrap
+	//CA rotates, default 1s, 0 reconciles all the clusters at once
+	bootstrapCARepushIntervalEnvVarName = "BOOTSTRAP_CA_REPUSH_INTERVAL"
+	//maxConcurrentDeletionsEnvVarName is the number of deleted clusters cleaned up at the same time, the
+	//others wait for their turn, default 10
+	maxConcurrentDeletionsEnvVarName = "MAX_CONCURRENT_DELETIONS"
+	//namespaceLabelsEnvVarName is a JSON object of labels ensured on the cluster namespaces, for example
+	//the PodSecurity admission or the network policy labels
+	namespaceLabelsEnvVarName = "NAMESPACE_LABELS"
+	//importSecretExternalKeysEnvVarName is a comma separated list of keys of the import secrets owned by
+	//an external controller, the import controller never overwrites them
+	importSecretExternalKeysEnvVarName = "IMPORT_SECRET_EXTERNAL_KEYS"
+	//klusterletCreateOnlyManifestsEnvVarName is a comma separated list of klusterlet manifest paths, for
+	//example klusterlet/cluster_role.yaml, created on the managed cluster if missing but never updated
+	klusterletCreateOnlyManifestsEnvVarName = "KLUSTERLET_CREATE_ONLY_MANIFESTS"
+	//finalizerOrderEnvVarName is the order in which the import and the registration finalizers are removed
+	//from a deleted ManagedCluster, remove-all (default), import-first or registration-first
+	finalizerOrderEnvVarName = "FINALIZER_ORDER"
+	//autoImportSecretNameEnvVarName is the name of the auto-import-secret looked for in the cluster
+	//namespaces, default auto-import-secret
+	autoImportSecretNameEnvVarName = "AUTO_IMPORT_SECRET_NAME"
+	//bootstrapKubeconfigClusterNameEnvVarName is the name of the cluster of the generated bootstrap
+	//kubeconfigs, default default-cluster
+	bootstrapKubeconfigClusterNameEnvVarName = "BOOTSTRAP_KUBECONFIG_CLUSTER_NAME"
+	//bootstrapKubeconfigContextNameEnvVarName is the name of the context of the generated bootstrap
+	//kubeconfigs, default default-context
+	bootstrapKubeconfigContextNameEnvVarName = "BOOTSTRAP_KUBECONFIG_CONTEXT_NAME"
+)
+
+const defaultOfflineClusterRequeueInterval = 5 * time.Minute
+
+//getEnvDuration returns the duration defined in the environment variable or the defaultValue
+//if the variable is not set or not parsable
+func getEnvDuration(name string, defaultValue time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Info("Invalid duration, using default", "env", name, "value", v, "default", defaultValue.String())
+		return defaultValue
+	}
+	return d
+}
+
+//getEnvBool returns the boolean defined in the environment variable or the defaultValue
+//if the variable is not set or not parsable
+func getEnvBool(name string, defaultValue bool) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Info("Invalid boolean, using default", "env", name, "value", v, "default", defaultValue)
+		return defaultValue
+	}
+	return b
+}
+
+//getEnvInt returns the positive integer defined in the environment variable or the defaultValue
+//if the variable is not set or not parsable
+func getEnvInt(name string, defaultValue int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 1 {
+		log.Info("Invalid integer, using default", "env", name, "value", v, "default", defaultValue)
+		return defaultValue
+	}
+	return i
+}
+
+func offlineClusterRequeueInterval() time.Duration {
+	return getEnvDuration(offlineClusterRequeueIntervalEnvVarName, defaultOfflineClusterRequeueInterval)
+}
@@ -0,0 +1,79 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_getEnvDuration(t *testing.T) {
+	const envName = "TEST_GET_ENV_DURATION"
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "not set", value: "", want: time.Minute},
+		{name: "valid", value: "30s", want: 30 * time.Second},
+		{name: "disabled", value: "0", want: 0},
+		{name: "invalid", value: "abc", want: time.Minute},
+		{name: "negative", value: "-1m", want: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(envName, tt.value)
+			defer os.Unsetenv(envName)
+			if got := getEnvDuration(envName, time.Minute); got != tt.want {
+				t.Errorf("getEnvDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getEnvBool(t *testing.T) {
+	const envName = "TEST_GET_ENV_BOOL"
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "not set", value: "", want: true},
+		{name: "false", value: "false", want: false},
+		{name: "true", value: "true", want: true},
+		{name: "invalid", value: "abc", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(envName, tt.value)
+			defer os.Unsetenv(envName)
+			if got := getEnvBool(envName, true); got != tt.want {
+				t.Errorf("getEnvBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getEnvInt(t *testing.T) {
+	const envName = "TEST_GET_ENV_INT"
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{name: "not set", value: "", want: 1},
+		{name: "valid", value: "10", want: 10},
+		{name: "zero", value: "0", want: 1},
+		{name: "invalid", value: "abc", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(envName, tt.value)
+			defer os.Unsetenv(envName)
+			if got := getEnvInt(envName, 1); got != tt.want {
+				t.Errorf("getEnvInt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"io"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExportImportYAML writes the klusterlet crds followed by the klusterlet manifests of the
+// managed cluster as a single YAML stream which can be applied with kubectl on the managed cluster.
+// It is the content of the import secret, neither the ManagedCluster nor the import secret are updated.
+// The kubeClient is only required when the bootstrap token is requested for an audience, a missing or
+// expiring token is then requested and stored in the bootstrap audience token secret of the hub, as
+// the reconcile does.
+func ExportImportYAML(c client.Client, kubeClient kubernetes.Interface, clusterName string, w io.Writer) error {
+	managedCluster := &clusterv1.ManagedCluster{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: clusterName}, managedCluster); err != nil {
+		return err
+	}
+
+	crds, yamls, err := generateImportYAMLs(c, kubeClient, managedCluster, []string{})
+	if err != nil {
+		return err
+	}
+
+	crdsYAML, err := toYAMLDocuments(crds)
+	if err != nil {
+		return err
+	}
+	importYAML, err := toYAMLDocuments(yamls)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(crdsYAML); err != nil {
+		return err
+	}
+	_, err = w.Write(importYAML)
+	return err
+}
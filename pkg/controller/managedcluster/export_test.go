@@ -0,0 +1,81 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	ocinfrav1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExportImportYAML(t *testing.T) {
+	os.Setenv("DEFAULT_IMAGE_PULL_SECRET", imagePullSecretNameSecret)
+	os.Setenv("POD_NAMESPACE", managedClusterNameSecret)
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster-export",
+		},
+	}
+	infraConfig := &ocinfrav1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Status: ocinfrav1.InfrastructureStatus{
+			APIServerURL: "https://127.0.0.1:6443",
+		},
+	}
+	serviceAccount, err := newBootstrapServiceAccount(managedCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenSecret, err := serviceAccountTokenSecret(serviceAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceAccount.Secrets = append(serviceAccount.Secrets, corev1.ObjectReference{
+		Name: tokenSecret.Name,
+	})
+
+	s := scheme.Scheme
+	s.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	s.AddKnownTypes(ocinfrav1.SchemeGroupVersion, &ocinfrav1.Infrastructure{}, &ocinfrav1.APIServer{})
+	c := fake.NewFakeClientWithScheme(s,
+		managedCluster,
+		serviceAccount,
+		tokenSecret,
+		infraConfig,
+		newFakeImagePullSecret(),
+	)
+
+	t.Run("cluster not found", func(t *testing.T) {
+		if err := ExportImportYAML(c, nil, "unknown", new(bytes.Buffer)); err == nil {
+			t.Error("ExportImportYAML() expected an error")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		if err := ExportImportYAML(c, nil, managedCluster.Name, out); err != nil {
+			t.Errorf("ExportImportYAML() error = %v", err)
+			return
+		}
+		crdsIndex := strings.Index(out.String(), "kind: CustomResourceDefinition")
+		klusterletIndex := strings.Index(out.String(), "kind: Klusterlet\n")
+		if crdsIndex < 0 || klusterletIndex < 0 {
+			t.Errorf("ExportImportYAML() the crds or the klusterlet are missing:\n%s", out.String())
+			return
+		}
+		if crdsIndex > klusterletIndex {
+			t.Error("ExportImportYAML() the crds must be written before the klusterlet")
+		}
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//klusterletProvisionedExternallyAnnotation set to "true" on a ManagedCluster whose klusterlet is installed
+//out-of-band (GitOps on the managed cluster...), the import secret and the bootstrap RBAC are still
+//generated but the klusterlet manifestworks are neither created nor deleted
+const klusterletProvisionedExternallyAnnotation = "import.open-cluster-management.io/klusterlet-provisioned-externally"
+
+func isKlusterletProvisionedExternally(managedCluster *clusterv1.ManagedCluster) bool {
+	v, ok := managedCluster.GetAnnotations()[klusterletProvisionedExternallyAnnotation]
+	if !ok {
+		return false
+	}
+	external, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Info("Invalid klusterlet provisioned externally annotation, the klusterlet manifestworks are applied",
+			"managedcluster", managedCluster.Name, "value", v)
+		return false
+	}
+	return external
+}
+
+//ConditionKlusterletManifestWorksStale reports the klusterlet manifestworks created by an import before the
+//klusterlet was provisioned externally, they are kept while the cluster is online
+const ConditionKlusterletManifestWorksStale string = "KlusterletManifestWorksStale"
+
+const (
+	reasonStaleKlusterletManifestWorks   string = "StaleKlusterletManifestWorks"
+	reasonNoStaleKlusterletManifestWorks string = "NoStaleKlusterletManifestWorks"
+)
+
+//listStaleKlusterletManifestWorks returns the klusterlet manifestworks created by a previous import once the
+//klusterlet is provisioned externally, only the manifestworks controlled by the ManagedCluster are returned
+func listStaleKlusterletManifestWorks(c client.Client, managedCluster *clusterv1.ManagedCluster) ([]*workv1.ManifestWork, error) {
+	mwNsN, err := manifestWorkNsN(managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	mws := &workv1.ManifestWorkList{}
+	if err := c.List(context.TODO(), mws, &client.ListOptions{Namespace: mwNsN.Namespace}); err != nil {
+		return nil, err
+	}
+	stale := make([]*workv1.ManifestWork, 0)
+	for i := range mws.Items {
+		mw := &mws.Items[i]
+		if !isKlusterletManifestWork(mw.Name, mwNsN.Name) || !metav1.IsControlledBy(mw, managedCluster) {
+			continue
+		}
+		stale = append(stale, mw)
+	}
+	return stale, nil
+}
+
+//deleteStaleKlusterletManifestWorks deletes the klusterlet manifestworks created by a previous import once
+//the klusterlet is provisioned externally, so the hub doesn't keep a stale klusterlet definition. Only the
+//manifestworks controlled by the ManagedCluster are deleted, it returns their names. The work agent removes
+//the resources of a deleted manifestwork, it must only be called once the cluster is offline.
+func deleteStaleKlusterletManifestWorks(c client.Client, managedCluster *clusterv1.ManagedCluster) ([]string, error) {
+	stale, err := listStaleKlusterletManifestWorks(c, managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	deleted := make([]string, 0)
+	for _, mw := range stale {
+		if err := c.Delete(context.TODO(), mw); err != nil && !errors.IsNotFound(err) {
+			return deleted, err
+		}
+		deleted = append(deleted, mw.Name)
+	}
+	if len(deleted) != 0 {
+		clusterLogger(managedCluster.Name).Info("Deleted the klusterlet manifestworks, the klusterlet is provisioned externally",
+			"manifestworks", deleted)
+	}
+	return deleted, nil
+}
+
+//cleanUpStaleKlusterletManifestWorks deletes the stale klusterlet manifestworks of an offline cluster. The
+//work api can't orphan the resources of a manifestwork, deleting them while the cluster is online would
+//uninstall the running klusterlet, they are kept and reported in the KlusterletManifestWorksStale condition.
+func (r *ReconcileManagedCluster) cleanUpStaleKlusterletManifestWorks(managedCluster *clusterv1.ManagedCluster) error {
+	names := make([]string, 0)
+	if checkOffLine(managedCluster) {
+		if _, err := deleteStaleKlusterletManifestWorks(r.client, managedCluster); err != nil {
+			return err
+		}
+	} else {
+		stale, err := listStaleKlusterletManifestWorks(r.client, managedCluster)
+		if err != nil {
+			return err
+		}
+		for _, mw := range stale {
+			names = append(names, mw.Name)
+		}
+	}
+	if len(names) == 0 {
+		if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionKlusterletManifestWorksStale) {
+			return nil
+		}
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionKlusterletManifestWorksStale,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonNoStaleKlusterletManifestWorks,
+			Message: "The klusterlet manifestworks of the previous import are deleted",
+		})
+	}
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:   ConditionKlusterletManifestWorksStale,
+		Status: metav1.ConditionTrue,
+		Reason: reasonStaleKlusterletManifestWorks,
+		Message: fmt.Sprintf("The klusterlet is provisioned externally, the manifestworks %s of the previous import "+
+			"are deleted once the cluster is offline as deleting them uninstalls the running klusterlet",
+			strings.Join(names, ", ")),
+	})
+}
@@ -0,0 +1,281 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_isKlusterletProvisionedExternally(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name: "no annotation",
+			want: false,
+		},
+		{
+			name:        "external",
+			annotations: map[string]string{klusterletProvisionedExternallyAnnotation: "true"},
+			want:        true,
+		},
+		{
+			name:        "not external",
+			annotations: map[string]string{klusterletProvisionedExternallyAnnotation: "false"},
+			want:        false,
+		},
+		{
+			name:        "invalid",
+			annotations: map[string]string{klusterletProvisionedExternallyAnnotation: "yes please"},
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster1",
+					Annotations: tt.annotations,
+				},
+			}
+			if got := isKlusterletProvisionedExternally(mc); got != tt.want {
+				t.Errorf("isKlusterletProvisionedExternally() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_managedClusterDeletion_klusterletProvisionedExternally(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	now := metav1.Now()
+	mc := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cluster1",
+			Finalizers:        []string{managedClusterFinalizer},
+			DeletionTimestamp: &now,
+			Annotations:       map[string]string{klusterletProvisionedExternallyAnnotation: "true"},
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+	mw := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1" + manifestWorkNamePostfix,
+			Namespace: "cluster1",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, mc, mw),
+		scheme: testscheme,
+	}
+
+	//The cluster is online but the deletion doesn't wait for the klusterlet removal
+	if _, err := r.managedClusterDeletion(mc); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster1"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Finalizers) != 0 {
+		t.Errorf("expected the finalizer to be removed, got %v", got.Finalizers)
+	}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: mw.Name, Namespace: mw.Namespace},
+		&workv1.ManifestWork{}); err != nil {
+		t.Errorf("expected the klusterlet manifestwork to be kept, got %v", err)
+	}
+}
+
+func Test_deleteStaleKlusterletManifestWorks(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	//The cluster was imported online, the klusterlet manifestworks are controlled by the ManagedCluster
+	mc := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+			UID:  types.UID("cluster1-uid"),
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+	controlled := true
+	newManifestWork := func(name string, owned bool) *workv1.ManifestWork {
+		mw := &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "cluster1",
+			},
+		}
+		if owned {
+			mw.OwnerReferences = []metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.SchemeGroupVersion.String(),
+					Kind:       "ManagedCluster",
+					Name:       mc.Name,
+					UID:        mc.UID,
+					Controller: &controlled,
+				},
+			}
+		}
+		return mw
+	}
+	klusterlet := newManifestWork("cluster1"+manifestWorkNamePostfix, true)
+	crds := newManifestWork("cluster1"+manifestWorkNamePostfix+manifestWorkCRDSPostfix, true)
+	part := newManifestWork(manifestWorkPartName("cluster1"+manifestWorkNamePostfix, 1), true)
+	addon := newManifestWork("cluster1-addon", true)
+	notOwned := newManifestWork("cluster1"+manifestWorkNamePostfix+manifestWorkCRDSPostfix+"-1", false)
+
+	c := fake.NewFakeClientWithScheme(testscheme, mc, klusterlet, crds, part, addon, notOwned)
+
+	//Nothing is deleted while the klusterlet is applied by the hub
+	if isKlusterletProvisionedExternally(mc) {
+		t.Fatalf("expected the klusterlet not to be provisioned externally")
+	}
+
+	//The cluster switches to an externally provisioned klusterlet
+	mc.Annotations = map[string]string{klusterletProvisionedExternallyAnnotation: "true"}
+	if !isKlusterletProvisionedExternally(mc) {
+		t.Fatalf("expected the klusterlet to be provisioned externally")
+	}
+	deleted, err := deleteStaleKlusterletManifestWorks(c, mc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 3 {
+		t.Errorf("expected 3 deleted manifestworks, got %v", deleted)
+	}
+	for _, mw := range []*workv1.ManifestWork{klusterlet, crds, part} {
+		err := c.Get(context.TODO(), types.NamespacedName{Name: mw.Name, Namespace: mw.Namespace}, &workv1.ManifestWork{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("expected the manifestwork %s to be deleted, got %v", mw.Name, err)
+		}
+	}
+	for _, mw := range []*workv1.ManifestWork{addon, notOwned} {
+		err := c.Get(context.TODO(), types.NamespacedName{Name: mw.Name, Namespace: mw.Namespace}, &workv1.ManifestWork{})
+		if err != nil {
+			t.Errorf("expected the manifestwork %s to be kept, got %v", mw.Name, err)
+		}
+	}
+
+	//The next reconciles have nothing to delete
+	deleted, err = deleteStaleKlusterletManifestWorks(c, mc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("expected no deleted manifestwork, got %v", deleted)
+	}
+}
+
+func TestReconcileManagedCluster_Reconcile_switchToKlusterletProvisionedExternally(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	//The cluster was imported online, then the annotation is set
+	mc := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        managedClusterNameReconcile,
+			UID:         types.UID("cluster-uid"),
+			Annotations: map[string]string{klusterletProvisionedExternallyAnnotation: "true"},
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+	controlled := true
+	klusterlet := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      managedClusterNameReconcile + manifestWorkNamePostfix,
+			Namespace: managedClusterNameReconcile,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.SchemeGroupVersion.String(),
+					Kind:       "ManagedCluster",
+					Name:       mc.Name,
+					UID:        mc.UID,
+					Controller: &controlled,
+				},
+			},
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, append(newImportObjects(t, mc), klusterlet)...),
+		scheme: testscheme,
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: mc.Name}}
+	mwNsN := types.NamespacedName{Name: klusterlet.Name, Namespace: klusterlet.Namespace}
+
+	//The running klusterlet is not uninstalled, the manifestwork is reported as stale
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.client.Get(context.TODO(), mwNsN, &workv1.ManifestWork{}); err != nil {
+		t.Errorf("expected the klusterlet manifestwork to be kept while the cluster is online, got %v", err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, ConditionKlusterletManifestWorksStale) {
+		t.Errorf("expected the condition %s to be true, got %v", ConditionKlusterletManifestWorksStale, got.Status.Conditions)
+	}
+
+	//Once the cluster is offline the stale manifestwork is deleted
+	meta.SetStatusCondition(&got.Status.Conditions, metav1.Condition{
+		Type:   clusterv1.ManagedClusterConditionAvailable,
+		Status: metav1.ConditionUnknown,
+		Reason: "ManagedClusterLeaseUpdateStopped",
+	})
+	if err := r.client.Status().Update(context.TODO(), got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.client.Get(context.TODO(), mwNsN, &workv1.ManifestWork{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the klusterlet manifestwork to be deleted once the cluster is offline, got %v", err)
+	}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(got.Status.Conditions, ConditionKlusterletManifestWorksStale)
+	if c == nil || c.Status != metav1.ConditionFalse {
+		t.Errorf("expected the condition %s to be false, got %v", ConditionKlusterletManifestWorksStale, c)
+	}
+}
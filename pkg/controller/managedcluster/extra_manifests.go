@@ -0,0 +1,102 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const reasonInvalidExtraManifests = "InvalidExtraManifests"
+
+var yamlDocumentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+//getExtraManifests returns the manifests of the configmap of KLUSTERLET_EXTRA_MANIFESTS shipped with
+//the klusterlet, each key holds one or more YAML documents. They must not collide with the klusterlet
+//manifests crds and yamls.
+func getExtraManifests(
+	c client.Client,
+	crds []*unstructured.Unstructured,
+	yamls []*unstructured.Unstructured,
+) ([]*unstructured.Unstructured, error) {
+	v := os.Getenv(klusterletExtraManifestsEnvVarName)
+	if v == "" {
+		return nil, nil
+	}
+	nsn, err := parseNamespacedName(v)
+	if err != nil {
+		return nil, err
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(context.TODO(), nsn, cm); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	used := make(map[string]bool)
+	for _, u := range append(append([]*unstructured.Unstructured{}, crds...), yamls...) {
+		used[manifestID(u)] = true
+	}
+	extras := make([]*unstructured.Unstructured, 0)
+	for _, key := range keys {
+		for _, doc := range yamlDocumentSeparator.Split(cm.Data[key], -1) {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+			u, err := parseManifest(doc)
+			if err != nil {
+				return nil, newConfigError(reasonInvalidExtraManifests,
+					"invalid klusterlet extra manifests: %s in %s: %v", key, v, err)
+			}
+			id := manifestID(u)
+			if used[id] {
+				return nil, newConfigError(reasonInvalidExtraManifests,
+					"invalid klusterlet extra manifests: %s in %s: %s is already part of the klusterlet manifests",
+					key, v, id)
+			}
+			used[id] = true
+			extras = append(extras, u)
+		}
+	}
+	return extras, nil
+}
+
+//parseManifest returns the resource of a YAML document, it must have an apiVersion, a kind and a name
+func parseManifest(doc string) (*unstructured.Unstructured, error) {
+	obj := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	switch {
+	case u.GetAPIVersion() == "":
+		return nil, fmt.Errorf("apiVersion is missing")
+	case u.GetKind() == "":
+		return nil, fmt.Errorf("kind is missing")
+	case u.GetName() == "":
+		return nil, fmt.Errorf("metadata.name is missing for a %s", u.GetKind())
+	}
+	return u, nil
+}
+
+//manifestID identifies a resource by its kind, namespace and name
+func manifestID(u *unstructured.Unstructured) string {
+	if u.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", u.GetKind(), u.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", u.GetKind(), u.GetNamespace(), u.GetName())
+}
@@ -0,0 +1,142 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getExtraManifests(t *testing.T) {
+	defer os.Unsetenv(klusterletExtraManifestsEnvVarName)
+
+	newConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "extras",
+				Namespace: "hub",
+			},
+			Data: data,
+		}
+	}
+	klusterlet := &unstructured.Unstructured{}
+	klusterlet.SetKind("Deployment")
+	klusterlet.SetNamespace("open-cluster-management-agent")
+	klusterlet.SetName("klusterlet")
+
+	priorityClass := `apiVersion: scheduling.k8s.io/v1
+kind: PriorityClass
+metadata:
+  name: klusterlet-critical
+value: 1000000
+`
+	configMap := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: custom
+  namespace: open-cluster-management-agent
+`
+
+	tests := []struct {
+		name      string
+		env       string
+		objs      []runtime.Object
+		wantNames []string
+		wantErr   bool
+		wantInv   bool
+	}{
+		{
+			name:      "not set",
+			env:       "",
+			wantNames: nil,
+		},
+		{
+			name:    "invalid reference",
+			env:     "extras",
+			wantErr: true,
+		},
+		{
+			name:    "configmap not found",
+			env:     "hub/extras",
+			wantErr: true,
+		},
+		{
+			name: "multiple documents",
+			env:  "hub/extras",
+			objs: []runtime.Object{newConfigMap(map[string]string{
+				"b.yaml": configMap,
+				"a.yaml": "---\n" + priorityClass + "---\n",
+			})},
+			wantNames: []string{"klusterlet-critical", "custom"},
+		},
+		{
+			name: "malformed",
+			env:  "hub/extras",
+			objs: []runtime.Object{newConfigMap(map[string]string{
+				"a.yaml": "kind: [",
+			})},
+			wantErr: true,
+			wantInv: true,
+		},
+		{
+			name: "missing name",
+			env:  "hub/extras",
+			objs: []runtime.Object{newConfigMap(map[string]string{
+				"a.yaml": "apiVersion: v1\nkind: ConfigMap\n",
+			})},
+			wantErr: true,
+			wantInv: true,
+		},
+		{
+			name: "collides with the klusterlet",
+			env:  "hub/extras",
+			objs: []runtime.Object{newConfigMap(map[string]string{
+				"a.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: klusterlet\n" +
+					"  namespace: open-cluster-management-agent\n",
+			})},
+			wantErr: true,
+			wantInv: true,
+		},
+		{
+			name: "duplicated",
+			env:  "hub/extras",
+			objs: []runtime.Object{newConfigMap(map[string]string{
+				"a.yaml": configMap,
+				"b.yaml": configMap,
+			})},
+			wantErr: true,
+			wantInv: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(klusterletExtraManifestsEnvVarName, tt.env)
+			c := fake.NewFakeClientWithScheme(scheme.Scheme, tt.objs...)
+			got, err := getExtraManifests(c, nil, []*unstructured.Unstructured{klusterlet})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getExtraManifests() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (configErrorReason(err) == reasonInvalidExtraManifests) != tt.wantInv {
+				t.Errorf("configErrorReason() = %q, want %v", configErrorReason(err), tt.wantInv)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tt.wantNames) {
+				t.Fatalf("getExtraManifests() = %d manifests, want %d", len(got), len(tt.wantNames))
+			}
+			for i, u := range got {
+				if u.GetName() != tt.wantNames[i] {
+					t.Errorf("manifest %d = %s, want %s", i, u.GetName(), tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
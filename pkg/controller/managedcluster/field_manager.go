@@ -0,0 +1,105 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//defaultFieldManager is the field manager of the writes of the controller when FIELD_MANAGER is not set
+const defaultFieldManager = "managedcluster-import-controller"
+
+//maxFieldManagerLength is the maximum length of a field manager accepted by the apiserver
+const maxFieldManagerLength = 128
+
+//fieldManager returns the field manager attributed to the writes of the controller in the managed fields,
+//several instances of the controller can set their own to tell their changes apart
+func fieldManager() string {
+	v := os.Getenv(fieldManagerEnvVarName)
+	if v == "" {
+		return defaultFieldManager
+	}
+	if !isValidFieldManager(v) {
+		log.Info("Invalid field manager, using default", "env", fieldManagerEnvVarName, "value", v,
+			"default", defaultFieldManager)
+		return defaultFieldManager
+	}
+	return v
+}
+
+//isValidFieldManager returns true if the apiserver accepts the field manager, at most 128 printable characters
+func isValidFieldManager(v string) bool {
+	if len(v) > maxFieldManagerLength {
+		return false
+	}
+	for _, r := range v {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+//fieldManagerClient sets the field manager on every create, update and patch, including the status ones,
+//a field owner given by the caller takes precedence
+type fieldManagerClient struct {
+	client.Client
+	fieldManager string
+}
+
+//newFieldManagerClient returns a client whose writes are attributed to the fieldManager
+func newFieldManagerClient(c client.Client, fieldManager string) client.Client {
+	return &fieldManagerClient{
+		Client:       c,
+		fieldManager: fieldManager,
+	}
+}
+
+func (c *fieldManagerClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	return c.Client.Create(ctx, obj, append([]client.CreateOption{client.FieldOwner(c.fieldManager)}, opts...)...)
+}
+
+func (c *fieldManagerClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	return c.Client.Update(ctx, obj, append([]client.UpdateOption{client.FieldOwner(c.fieldManager)}, opts...)...)
+}
+
+func (c *fieldManagerClient) Patch(
+	ctx context.Context,
+	obj runtime.Object,
+	patch client.Patch,
+	opts ...client.PatchOption,
+) error {
+	return c.Client.Patch(ctx, obj, patch, append([]client.PatchOption{client.FieldOwner(c.fieldManager)}, opts...)...)
+}
+
+func (c *fieldManagerClient) Status() client.StatusWriter {
+	return &fieldManagerStatusWriter{
+		StatusWriter: c.Client.Status(),
+		fieldManager: c.fieldManager,
+	}
+}
+
+//fieldManagerStatusWriter sets the field manager on the status updates and patches
+type fieldManagerStatusWriter struct {
+	client.StatusWriter
+	fieldManager string
+}
+
+func (w *fieldManagerStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	return w.StatusWriter.Update(ctx, obj, append([]client.UpdateOption{client.FieldOwner(w.fieldManager)}, opts...)...)
+}
+
+func (w *fieldManagerStatusWriter) Patch(
+	ctx context.Context,
+	obj runtime.Object,
+	patch client.Patch,
+	opts ...client.PatchOption,
+) error {
+	return w.StatusWriter.Patch(ctx, obj, patch,
+		append([]client.PatchOption{client.FieldOwner(w.fieldManager)}, opts...)...)
+}
@@ -0,0 +1,98 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_fieldManager(t *testing.T) {
+	defer os.Unsetenv(fieldManagerEnvVarName)
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{
+			name: "default",
+			env:  "",
+			want: defaultFieldManager,
+		},
+		{
+			name: "configured",
+			env:  "import-controller-shard-a",
+			want: "import-controller-shard-a",
+		},
+		{
+			name: "too long",
+			env:  strings.Repeat("a", maxFieldManagerLength+1),
+			want: defaultFieldManager,
+		},
+		{
+			name: "not printable",
+			env:  "import\ncontroller",
+			want: defaultFieldManager,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(fieldManagerEnvVarName, tt.env)
+			if got := fieldManager(); got != tt.want {
+				t.Errorf("fieldManager() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+//recordingClient records the field owner of the writes
+type recordingClient struct {
+	client.Client
+	fieldManagers []string
+}
+
+func (c *recordingClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	o := &client.CreateOptions{}
+	o.ApplyOptions(opts)
+	c.fieldManagers = append(c.fieldManagers, o.FieldManager)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *recordingClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	o := &client.UpdateOptions{}
+	o.ApplyOptions(opts)
+	c.fieldManagers = append(c.fieldManagers, o.FieldManager)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func Test_fieldManagerClient(t *testing.T) {
+	recorder := &recordingClient{Client: fake.NewFakeClientWithScheme(scheme.Scheme)}
+	c := newFieldManagerClient(recorder, "import-controller-shard-a")
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mycluster-import",
+			Namespace: "mycluster",
+		},
+	}
+	if err := c.Create(context.TODO(), secret); err != nil {
+		t.Fatal(err)
+	}
+	//the field owner of the caller takes precedence
+	if err := c.Update(context.TODO(), secret, client.FieldOwner("caller")); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"import-controller-shard-a", "caller"}
+	if strings.Join(recorder.fieldManagers, ",") != strings.Join(want, ",") {
+		t.Errorf("field managers = %v, want %v", recorder.fieldManagers, want)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+//newFilteredInformerSource returns a source of the secrets or the configmaps of the namespace, all the
+//namespaces if empty, selected by the list options. Unlike the manager cache, which holds all the objects
+//of a kind once watched, the informer only caches the selected objects. It is started with the manager.
+func newFilteredInformerSource(
+	mgr manager.Manager,
+	obj runtime.Object,
+	namespace string,
+	tweakListOptions func(*metav1.ListOptions),
+) (source.Source, error) {
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	var informer toolscache.SharedIndexInformer
+	switch obj.(type) {
+	case *corev1.Secret:
+		informer = coreinformers.NewFilteredSecretInformer(kubeClient, namespace, 0, toolscache.Indexers{}, tweakListOptions)
+	case *corev1.ConfigMap:
+		informer = coreinformers.NewFilteredConfigMapInformer(kubeClient, namespace, 0, toolscache.Indexers{}, tweakListOptions)
+	default:
+		return nil, fmt.Errorf("no filtered informer for %T", obj)
+	}
+	if err := mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		informer.Run(stop)
+		return nil
+	})); err != nil {
+		return nil, err
+	}
+	return &source.Informer{Informer: informer}, nil
+}
+
+//importSecretListOptions selects the secrets with the cluster label, the import secrets are labeled
+//with the name of their cluster
+func importSecretListOptions(options *metav1.ListOptions) {
+	options.LabelSelector = clusterLabel
+}
+
+//nameListOptions returns the list options selecting a single object by name
+func nameListOptions(name string) func(*metav1.ListOptions) {
+	return func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	}
+}
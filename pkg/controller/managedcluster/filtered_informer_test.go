@@ -0,0 +1,52 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func Test_importSecretListOptions(t *testing.T) {
+	options := &metav1.ListOptions{}
+	importSecretListOptions(options)
+	selector, err := labels.Parse(options.LabelSelector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+		},
+	}
+	secret, err := newImportSecret(managedCluster, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !selector.Matches(labels.Set(secret.Labels)) {
+		t.Errorf("expected the import secret to be selected by %q", options.LabelSelector)
+	}
+	if selector.Matches(labels.Set(map[string]string{"app": "other"})) {
+		t.Errorf("expected the other secrets not to be selected by %q", options.LabelSelector)
+	}
+}
+
+func Test_nameListOptions(t *testing.T) {
+	options := &metav1.ListOptions{}
+	nameListOptions("bootstrap-ca")(options)
+	selector, err := fields.ParseSelector(options.FieldSelector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !selector.Matches(fields.Set{"metadata.name": "bootstrap-ca"}) {
+		t.Errorf("expected the bootstrap CA to be selected by %q", options.FieldSelector)
+	}
+	if selector.Matches(fields.Set{"metadata.name": "other"}) {
+		t.Errorf("expected the other objects not to be selected by %q", options.FieldSelector)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	libgometav1 "github.com/open-cluster-management/library-go/pkg/apis/meta/v1"
+)
+
+//The orders in which the finalizers of a deleted ManagedCluster are removed, depending on the
+//registration controller of the hub
+const (
+	//finalizerOrderRemoveAll cleans up while the registration finalizer is set and removes both
+	//finalizers, for the registration controllers which don't remove theirs
+	finalizerOrderRemoveAll = "remove-all"
+	//finalizerOrderImportFirst cleans up while the registration finalizer is set and only removes the
+	//import finalizer, the registration controller removes its own afterwards
+	finalizerOrderImportFirst = "import-first"
+	//finalizerOrderRegistrationFirst waits for the registration controller to remove its finalizer
+	//before cleaning up and removing the import finalizer
+	finalizerOrderRegistrationFirst = "registration-first"
+)
+
+//finalizerOrder returns the order of FINALIZER_ORDER, remove-all if not set or invalid
+func finalizerOrder() string {
+	switch v := os.Getenv(finalizerOrderEnvVarName); v {
+	case "":
+		return finalizerOrderRemoveAll
+	case finalizerOrderRemoveAll, finalizerOrderImportFirst, finalizerOrderRegistrationFirst:
+		return v
+	default:
+		log.Info("Invalid finalizer order, using default", "env", finalizerOrderEnvVarName, "value", v,
+			"default", finalizerOrderRemoveAll)
+		return finalizerOrderRemoveAll
+	}
+}
+
+//waitedFinalizers returns the finalizers of the ManagedCluster the cleanup waits on, all the finalizers
+//of the other controllers, the registration one included only if it is removed first
+func waitedFinalizers(managedCluster *clusterv1.ManagedCluster, order string) []string {
+	if order == finalizerOrderRegistrationFirst {
+		return filterFinalizers(managedCluster, []string{managedClusterFinalizer})
+	}
+	return filterFinalizers(managedCluster, []string{managedClusterFinalizer, registrationFinalizer})
+}
+
+//remainingFinalizers returns the finalizers left on the ManagedCluster once cleaned up
+func remainingFinalizers(managedCluster *clusterv1.ManagedCluster, order string) []string {
+	if order == finalizerOrderRemoveAll {
+		return nil
+	}
+	return filterFinalizers(managedCluster, []string{managedClusterFinalizer})
+}
+
+//isCleanedUp returns true if the import finalizer was already removed and the ManagedCluster only
+//waits on the finalizers of the other controllers. With remove-all, the cleanup goes on to remove
+//the registration finalizer.
+func isCleanedUp(managedCluster *clusterv1.ManagedCluster, order string) bool {
+	return order != finalizerOrderRemoveAll && !libgometav1.HasFinalizer(managedCluster, managedClusterFinalizer)
+}
@@ -0,0 +1,124 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_finalizerOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "not set", value: "", want: finalizerOrderRemoveAll},
+		{name: "import first", value: "import-first", want: finalizerOrderImportFirst},
+		{name: "registration first", value: "registration-first", want: finalizerOrderRegistrationFirst},
+		{name: "invalid", value: "whatever", want: finalizerOrderRemoveAll},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(finalizerOrderEnvVarName, tt.value)
+			defer os.Unsetenv(finalizerOrderEnvVarName)
+			if got := finalizerOrder(); got != tt.want {
+				t.Errorf("finalizerOrder() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_managedClusterDeletion_finalizerOrder(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	tests := []struct {
+		name  string
+		order string
+		//registrationRemoves simulates the registration controller removing its finalizer once the
+		//import controller reconciled the deletion
+		registrationRemoves bool
+		//wantFinalizers are the finalizers after each reconcile of the deletion
+		wantFinalizers [][]string
+	}{
+		{
+			name:  "remove-all",
+			order: finalizerOrderRemoveAll,
+			wantFinalizers: [][]string{
+				nil,
+			},
+		},
+		{
+			name:                "import-first",
+			order:               finalizerOrderImportFirst,
+			registrationRemoves: true,
+			wantFinalizers: [][]string{
+				{registrationFinalizer},
+				nil,
+			},
+		},
+		{
+			name:                "registration-first",
+			order:               finalizerOrderRegistrationFirst,
+			registrationRemoves: true,
+			wantFinalizers: [][]string{
+				{managedClusterFinalizer, registrationFinalizer},
+				nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(finalizerOrderEnvVarName, tt.order)
+			defer os.Unsetenv(finalizerOrderEnvVarName)
+
+			now := metav1.Now()
+			mc := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "cluster1",
+					Finalizers:        []string{managedClusterFinalizer, registrationFinalizer},
+					DeletionTimestamp: &now,
+					Annotations:       map[string]string{klusterletProvisionedExternallyAnnotation: "true"},
+				},
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, mc),
+				scheme: testscheme,
+			}
+			for i, want := range tt.wantFinalizers {
+				got := &clusterv1.ManagedCluster{}
+				if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster1"}, got); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := r.managedClusterDeletion(got); err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster1"}, got); err != nil {
+					t.Fatal(err)
+				}
+				if len(got.Finalizers) != 0 || len(want) != 0 {
+					if !reflect.DeepEqual(got.Finalizers, want) {
+						t.Errorf("reconcile %d: finalizers = %v, want %v", i, got.Finalizers, want)
+					}
+				}
+				if tt.registrationRemoves {
+					got.SetFinalizers(filterFinalizers(got, []string{registrationFinalizer}))
+					if err := r.client.Update(context.TODO(), got); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
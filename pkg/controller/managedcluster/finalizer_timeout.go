@@ -0,0 +1,46 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const reasonFinalizerGraceTimeoutExceeded string = "FinalizerGraceTimeoutExceeded"
+
+//finalizerGraceTimeout returns the maximum time to wait on the cleanup of a deleted ManagedCluster,
+//0 means waiting forever
+func finalizerGraceTimeout() time.Duration {
+	return getEnvDuration(finalizerGraceTimeoutEnvVarName, 0)
+}
+
+//finalizerGraceTimeoutExceeded returns true if the ManagedCluster is in deletion for more than the timeout
+func finalizerGraceTimeoutExceeded(managedCluster *clusterv1.ManagedCluster, timeout time.Duration, now time.Time) bool {
+	if timeout <= 0 || managedCluster.DeletionTimestamp == nil {
+		return false
+	}
+	return now.After(managedCluster.DeletionTimestamp.Add(timeout))
+}
+
+//forceRemoveFinalizer removes the import controller finalizer without waiting on the cleanup,
+//the other finalizers are left to their owners
+func (r *ReconcileManagedCluster) forceRemoveFinalizer(managedCluster *clusterv1.ManagedCluster) (reconcile.Result, error) {
+	message := fmt.Sprintf(
+		"The cleanup of the managed cluster %s didn't complete within %s, the finalizer %s is force-removed, "+
+			"resources may be left on the hub and the managed cluster",
+		managedCluster.Name, finalizerGraceTimeout().String(), managedClusterFinalizer)
+	log.Error(nil, message, "managedcluster", managedCluster.Name)
+	r.recordEvent(managedCluster, corev1.EventTypeWarning, reasonFinalizerGraceTimeoutExceeded, message)
+
+	managedCluster.SetFinalizers(filterFinalizers(managedCluster, []string{managedClusterFinalizer}))
+	if err := r.client.Update(context.TODO(), managedCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,92 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_finalizerGraceTimeoutExceeded(t *testing.T) {
+	now := time.Now()
+	deletedAt := metav1.NewTime(now.Add(-2 * time.Hour))
+	tests := []struct {
+		name              string
+		deletionTimestamp *metav1.Time
+		timeout           time.Duration
+		want              bool
+	}{
+		{name: "not in deletion", timeout: time.Hour, want: false},
+		{name: "timeout disabled", deletionTimestamp: &deletedAt, timeout: 0, want: false},
+		{name: "within timeout", deletionTimestamp: &deletedAt, timeout: 3 * time.Hour, want: false},
+		{name: "timeout exceeded", deletionTimestamp: &deletedAt, timeout: time.Hour, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "mycluster",
+					DeletionTimestamp: tt.deletionTimestamp,
+				},
+			}
+			if got := finalizerGraceTimeoutExceeded(managedCluster, tt.timeout, now); got != tt.want {
+				t.Errorf("finalizerGraceTimeoutExceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_Reconcile_finalizerGraceTimeout(t *testing.T) {
+	os.Setenv(finalizerGraceTimeoutEnvVarName, "1h")
+	defer os.Unsetenv(finalizerGraceTimeoutEnvVarName)
+
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	deletedAt := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "mycluster",
+			DeletionTimestamp: &deletedAt,
+			Finalizers:        []string{managedClusterFinalizer, "other-finalizer"},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileManagedCluster{
+		client:   fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme:   testscheme,
+		recorder: recorder,
+	}
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "mycluster"}}); err != nil {
+		t.Errorf("ReconcileManagedCluster.Reconcile() error = %v", err)
+		return
+	}
+
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, got); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(got.Finalizers) != 1 || got.Finalizers[0] != "other-finalizer" {
+		t.Errorf("expected only other-finalizer to remain, got %v", got.Finalizers)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, reasonFinalizerGraceTimeoutExceeded) {
+			t.Errorf("expected a %s event, got %s", reasonFinalizerGraceTimeoutExceeded, event)
+		}
+	default:
+		t.Errorf("expected a %s event", reasonFinalizerGraceTimeoutExceeded)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/applier"
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+const hubManifestsPath = "hub/managedcluster/manifests"
+
+const (
+	ConditionHubManifestsApplied  string = "HubManifestsApplied"
+	reasonHubManifestsApplied     string = "HubManifestsApplied"
+	reasonHubManifestsApplyFailed string = "HubManifestsApplyFailed"
+)
+
+//hubManifestsValues are the values used to render the hub manifests
+type hubManifestsValues struct {
+	ManagedClusterName          string
+	ManagedClusterNamespace     string
+	BootstrapServiceAccountName string
+	BootstrapLeastPrivilege     bool
+	//Labels and Annotations are the propagated metadata, rendered quoted
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+func newHubManifestsValues(managedCluster *clusterv1.ManagedCluster) hubManifestsValues {
+	metadata := getPropagatedMetadata(managedCluster)
+	return hubManifestsValues{
+		ManagedClusterName:          managedCluster.Name,
+		ManagedClusterNamespace:     managedCluster.Name,
+		BootstrapServiceAccountName: bootstrapServiceAccountName(managedCluster),
+		BootstrapLeastPrivilege:     getEnvBool(bootstrapLeastPrivilegeEnvVarName, false),
+		Labels:                      metadata.labels,
+		Annotations:                 metadata.annotations,
+	}
+}
+
+//hubManifestsApplyError lists the hub manifests which failed to be applied
+type hubManifestsApplyError struct {
+	failed map[string]error
+}
+
+func (e *hubManifestsApplyError) Error() string {
+	assets := make([]string, 0, len(e.failed))
+	for asset := range e.failed {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	msgs := make([]string, len(assets))
+	for i, asset := range assets {
+		msgs[i] = fmt.Sprintf("%s (%s)", asset, e.failed[asset].Error())
+	}
+	return "Failed to apply: " + strings.Join(msgs, ", ")
+}
+
+//hubManifestsRetries keeps for each cluster the hub manifests which failed to be applied, with the hash
+//of the configuration they were rendered from. The next reconcile of the same configuration only
+//re-attempts them, a new configuration applies all the manifests again.
+type hubManifestsRetries struct {
+	mutex  sync.Mutex
+	failed map[string]failedHubManifests
+}
+
+type failedHubManifests struct {
+	configHash string
+	assets     map[string]bool
+}
+
+var hubManifestsToRetry = &hubManifestsRetries{}
+
+//get returns the manifests to re-attempt for the configuration, nil if all the manifests must be applied
+func (h *hubManifestsRetries) get(clusterName, configHash string) map[string]bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	failed, ok := h.failed[clusterName]
+	if !ok || failed.configHash != configHash {
+		return nil
+	}
+	return failed.assets
+}
+
+//record keeps the manifests which failed to be applied, the cluster is forgotten if none failed or if
+//the error doesn't list them
+func (h *hubManifestsRetries) record(clusterName, configHash string, err error) {
+	applyErr, ok := err.(*hubManifestsApplyError)
+	if !ok {
+		h.forget(clusterName)
+		return
+	}
+	assets := make(map[string]bool, len(applyErr.failed))
+	for asset := range applyErr.failed {
+		assets[asset] = true
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.failed == nil {
+		h.failed = make(map[string]failedHubManifests)
+	}
+	h.failed[clusterName] = failedHubManifests{configHash: configHash, assets: assets}
+}
+
+//forget drops the failed manifests of the cluster
+func (h *hubManifestsRetries) forget(clusterName string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	delete(h.failed, clusterName)
+}
+
+//applyHubManifests creates or updates each hub manifest one by one, a failing manifest doesn't prevent
+//the others to be applied. If only is not nil, only the manifests it contains are applied, the next
+//reconcile uses it to re-attempt the failed manifests while the already applied ones are left unchanged.
+func applyHubManifests(a *applier.Applier, excluded []string, only map[string]bool, values interface{}) error {
+	names, err := bindata.AssetDir(hubManifestsPath)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	failed := make(map[string]error)
+	for _, name := range names {
+		asset := filepath.Join(hubManifestsPath, name)
+		if isExcluded(asset, excluded) || (only != nil && !only[asset]) {
+			continue
+		}
+		if err := a.CreateOrUpdateResource(asset, values); err != nil {
+			log.Error(err, "Failed to apply hub manifest", "asset", asset)
+			failed[asset] = err
+		}
+	}
+	if len(failed) != 0 {
+		return &hubManifestsApplyError{failed: failed}
+	}
+	return nil
+}
+
+func isExcluded(asset string, excluded []string) bool {
+	for _, e := range excluded {
+		if e == asset {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/applier"
+	"github.com/open-cluster-management/applier/pkg/templateprocessor"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+func Test_hubManifestsApplyError(t *testing.T) {
+	err := &hubManifestsApplyError{
+		failed: map[string]error{
+			"hub/b.yaml": fmt.Errorf("error b"),
+			"hub/a.yaml": fmt.Errorf("error a"),
+		},
+	}
+	want := "Failed to apply: hub/a.yaml (error a), hub/b.yaml (error b)"
+	if got := err.Error(); got != want {
+		t.Errorf("hubManifestsApplyError.Error() = %q, want %q", got, want)
+	}
+}
+
+func Test_applyHubManifests(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	c := fake.NewFakeClientWithScheme(testscheme, managedCluster)
+	a, err := applier.NewApplier(
+		bindata.NewBindataReader(),
+		nil,
+		c,
+		managedCluster,
+		testscheme,
+		applier.DefaultKubernetesMerger,
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := newHubManifestsValues(managedCluster)
+
+	//Applying twice must be idempotent
+	for i := 0; i < 2; i++ {
+		if err := applyHubManifests(
+			a,
+			[]string{"hub/managedcluster/manifests/managedcluster-service-account.yaml"},
+			nil,
+			values,
+		); err != nil {
+			t.Errorf("applyHubManifests() error = %v", err)
+		}
+	}
+
+	name := bootstrapClusterRoleBindingName(managedCluster)
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name}, &rbacv1.ClusterRole{}); err != nil {
+		t.Errorf("expected the ClusterRole to be created, got %v", err)
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name}, &rbacv1.ClusterRoleBinding{}); err != nil {
+		t.Errorf("expected the ClusterRoleBinding to be created, got %v", err)
+	}
+}
+
+func Test_applyHubManifests_only(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	c := fake.NewFakeClientWithScheme(testscheme, managedCluster)
+	a, err := applier.NewApplier(
+		bindata.NewBindataReader(),
+		nil,
+		c,
+		managedCluster,
+		testscheme,
+		applier.DefaultKubernetesMerger,
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Only the failed ClusterRoleBinding is re-attempted
+	if err := applyHubManifests(
+		a,
+		[]string{"hub/managedcluster/manifests/managedcluster-service-account.yaml"},
+		map[string]bool{"hub/managedcluster/manifests/managedcluster-clusterrolebinding.yaml": true},
+		newHubManifestsValues(managedCluster),
+	); err != nil {
+		t.Errorf("applyHubManifests() error = %v", err)
+	}
+
+	name := bootstrapClusterRoleBindingName(managedCluster)
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name}, &rbacv1.ClusterRoleBinding{}); err != nil {
+		t.Errorf("expected the ClusterRoleBinding to be created, got %v", err)
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name}, &rbacv1.ClusterRole{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the ClusterRole not to be applied, got %v", err)
+	}
+}
+
+func Test_hubManifestsRetries(t *testing.T) {
+	h := &hubManifestsRetries{}
+	if got := h.get("mycluster", "hash1"); got != nil {
+		t.Errorf("get() = %v, want all the manifests", got)
+	}
+
+	h.record("mycluster", "hash1", &hubManifestsApplyError{
+		failed: map[string]error{"hub/a.yaml": fmt.Errorf("error a")},
+	})
+	want := map[string]bool{"hub/a.yaml": true}
+	if got := h.get("mycluster", "hash1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("get() = %v, want %v", got, want)
+	}
+	if got := h.get("mycluster", "hash2"); got != nil {
+		t.Errorf("get() of a new configuration = %v, want all the manifests", got)
+	}
+	if got := h.get("other", "hash1"); got != nil {
+		t.Errorf("get() of another cluster = %v, want all the manifests", got)
+	}
+
+	//A successful retry forgets the failed manifests
+	h.record("mycluster", "hash1", nil)
+	if got := h.get("mycluster", "hash1"); got != nil {
+		t.Errorf("get() after a success = %v, want all the manifests", got)
+	}
+
+	//An error not listing the manifests applies them all
+	h.record("mycluster", "hash1", &hubManifestsApplyError{
+		failed: map[string]error{"hub/a.yaml": fmt.Errorf("error a")},
+	})
+	h.record("mycluster", "hash1", fmt.Errorf("no assets"))
+	if got := h.get("mycluster", "hash1"); got != nil {
+		t.Errorf("get() after an other error = %v, want all the manifests", got)
+	}
+
+	h.record("mycluster", "hash1", &hubManifestsApplyError{
+		failed: map[string]error{"hub/a.yaml": fmt.Errorf("error a")},
+	})
+	h.forget("mycluster")
+	if got := h.get("mycluster", "hash1"); got != nil {
+		t.Errorf("get() after forget = %v, want all the manifests", got)
+	}
+}
+
+func Test_bootstrapClusterRoleRules(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	tests := []struct {
+		name           string
+		leastPrivilege string
+		want           []rbacv1.PolicyRule
+	}{
+		{
+			name:           "default",
+			leastPrivilege: "",
+			want: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{"certificates.k8s.io"},
+					Resources: []string{"certificatesigningrequests"},
+					Verbs:     []string{"create", "get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{"cluster.open-cluster-management.io"},
+					Resources: []string{"managedclusters"},
+					Verbs:     []string{"get", "create"},
+				},
+			},
+		},
+		{
+			name:           "least privilege",
+			leastPrivilege: "true",
+			want: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{"certificates.k8s.io"},
+					Resources: []string{"certificatesigningrequests"},
+					Verbs:     []string{"create", "get", "list", "watch"},
+				},
+				{
+					APIGroups:     []string{"cluster.open-cluster-management.io"},
+					Resources:     []string{"managedclusters"},
+					ResourceNames: []string{"mycluster"},
+					Verbs:         []string{"get"},
+				},
+				{
+					APIGroups: []string{"cluster.open-cluster-management.io"},
+					Resources: []string{"managedclusters"},
+					Verbs:     []string{"create"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(bootstrapLeastPrivilegeEnvVarName, tt.leastPrivilege)
+			defer os.Unsetenv(bootstrapLeastPrivilegeEnvVarName)
+			tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := tp.TemplateResource(
+				"hub/managedcluster/manifests/managedcluster-clusterrole.yaml",
+				newHubManifestsValues(managedCluster))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cr := &rbacv1.ClusterRole{}
+			if err := yaml.Unmarshal(result, cr); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cr.Rules, tt.want) {
+				t.Errorf("rules = %v, want %v", cr.Rules, tt.want)
+			}
+		})
+	}
+}
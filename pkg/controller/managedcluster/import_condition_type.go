@@ -0,0 +1,24 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+//importConditionType returns the type of the import condition, ManagedClusterImportSucceeded namespaced
+//with the configured prefix so several import controllers don't clobber each other's condition
+func importConditionType() string {
+	prefix := os.Getenv(importConditionTypePrefixEnvVarName)
+	if prefix == "" {
+		return ManagedClusterImportSucceeded
+	}
+	if errs := validation.IsDNS1123Subdomain(prefix); len(errs) != 0 {
+		log.Info("Invalid condition type prefix, using default", "env", importConditionTypePrefixEnvVarName,
+			"value", prefix, "errors", errs)
+		return ManagedClusterImportSucceeded
+	}
+	return prefix + "/" + ManagedClusterImportSucceeded
+}
@@ -0,0 +1,90 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_importConditionType(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{
+			name: "default",
+			want: ManagedClusterImportSucceeded,
+		},
+		{
+			name: "prefixed",
+			env:  "controller-a.example.com",
+			want: "controller-a.example.com/" + ManagedClusterImportSucceeded,
+		},
+		{
+			name: "invalid prefix",
+			env:  "Controller_A",
+			want: ManagedClusterImportSucceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(importConditionTypePrefixEnvVarName, tt.env)
+			defer os.Unsetenv(importConditionTypePrefixEnvVarName)
+			if got := importConditionType(); got != tt.want {
+				t.Errorf("importConditionType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setConditionImport_prefixedType(t *testing.T) {
+	os.Setenv(importConditionTypePrefixEnvVarName, "controller-a.example.com")
+	defer os.Unsetenv(importConditionTypePrefixEnvVarName)
+
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	mc := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   ManagedClusterImportSucceeded,
+					Status: metav1.ConditionFalse,
+					Reason: "OtherController",
+				},
+			},
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, mc),
+		scheme: testscheme,
+	}
+	if err := r.setConditionImport(mc, nil, ""); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "test"}, got); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(got.Status.Conditions, "controller-a.example.com/"+ManagedClusterImportSucceeded)
+	if c == nil || c.Status != metav1.ConditionTrue {
+		t.Errorf("expected the prefixed condition to be true, got %v", got.Status.Conditions)
+	}
+	c = meta.FindStatusCondition(got.Status.Conditions, ManagedClusterImportSucceeded)
+	if c == nil || c.Reason != "OtherController" {
+		t.Errorf("expected the condition of the other controller to be kept, got %v", got.Status.Conditions)
+	}
+}
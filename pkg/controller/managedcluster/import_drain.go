@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultImportDrainTimeout = 20 * time.Second
+
+//importDrainPollInterval is the interval between the checks of the in-flight imports while draining
+const importDrainPollInterval = 100 * time.Millisecond
+
+//importTracker tracks the imports in progress so the controller can let them complete on shutdown
+type importTracker struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight map[string]struct{}
+}
+
+//imports tracks the imports of the controller, shared by the concurrent reconciles
+var imports = &importTracker{inFlight: map[string]struct{}{}}
+
+//begin registers an import of the cluster, it returns false if the controller is shutting down
+//and no import must be started
+func (t *importTracker) begin(clusterName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return false
+	}
+	t.inFlight[clusterName] = struct{}{}
+	return true
+}
+
+//end unregisters a completed (or failed) import of the cluster
+func (t *importTracker) end(clusterName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.inFlight, clusterName)
+}
+
+//pending returns the sorted names of the clusters being imported
+func (t *importTracker) pending() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := make([]string, 0, len(t.inFlight))
+	for name := range t.inFlight {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//drain stops the new imports and waits for the imports in progress for at most timeout,
+//it returns the clusters still being imported when the timeout is exceeded
+func (t *importTracker) drain(timeout time.Duration) []string {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		pending := t.pending()
+		if len(pending) == 0 || !time.Now().Before(deadline) {
+			return pending
+		}
+		time.Sleep(importDrainPollInterval)
+	}
+}
+
+//DrainImports is called on shutdown once the manager stopped dispatching reconciles, it prevents new
+//imports and gives the imports in progress IMPORT_DRAIN_TIMEOUT to complete. It returns false if
+//some imports didn't complete, they are retried by the next controller instance.
+func DrainImports() bool {
+	timeout := getEnvDuration(importDrainTimeoutEnvVarName, defaultImportDrainTimeout)
+	log.Info("Draining the imports in progress", "timeout", timeout.String(), "clusters", imports.pending())
+	if pending := imports.drain(timeout); len(pending) != 0 {
+		log.Info("Imports still in progress after the drain timeout", "timeout", timeout.String(), "clusters", pending)
+		return false
+	}
+	log.Info("No import in progress")
+	return true
+}
@@ -0,0 +1,39 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_importTracker(t *testing.T) {
+	tracker := &importTracker{inFlight: map[string]struct{}{}}
+	if !tracker.begin("cluster1") || !tracker.begin("cluster2") {
+		t.Fatalf("expected the imports to start")
+	}
+	tracker.end("cluster1")
+	if got := tracker.pending(); !reflect.DeepEqual(got, []string{"cluster2"}) {
+		t.Errorf("pending() = %v, want [cluster2]", got)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		tracker.end("cluster2")
+	}()
+	if pending := tracker.drain(time.Second); len(pending) != 0 {
+		t.Errorf("drain() = %v, want no pending import", pending)
+	}
+	if tracker.begin("cluster3") {
+		t.Errorf("expected no import to start while draining")
+	}
+}
+
+func Test_importTracker_drainTimeout(t *testing.T) {
+	tracker := &importTracker{inFlight: map[string]struct{}{}}
+	tracker.begin("cluster1")
+	if pending := tracker.drain(10 * time.Millisecond); !reflect.DeepEqual(pending, []string{"cluster1"}) {
+		t.Errorf("drain() = %v, want [cluster1]", pending)
+	}
+}
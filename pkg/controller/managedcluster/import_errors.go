@@ -0,0 +1,116 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+)
+
+//The kinds of import failures, use errors.Is to check the kind of an error returned by the import
+var (
+	//ErrUnreachable the managed cluster API server can't be reached
+	ErrUnreachable = errors.New("managed cluster unreachable")
+	//ErrInvalidSecret the auto-import-secret or the hive admin kubeconfig secret can't be used
+	ErrInvalidSecret = errors.New("invalid import secret")
+	//ErrClusterNotInstalled the hive ClusterDeployment is not installed yet
+	ErrClusterNotInstalled = errors.New("cluster not installed")
+)
+
+const (
+	reasonManagedClusterUnreachable = "ManagedClusterUnreachable"
+	reasonInvalidImportSecret       = "InvalidImportSecret"
+	reasonClusterNotInstalled       = "ClusterNotInstalled"
+	reasonManagedClusterNotImported = "ManagedClusterNotImported"
+)
+
+//importError is an import failure of a given kind, the cause stays reachable with errors.As
+type importError struct {
+	kind error
+	err  error
+}
+
+func newImportError(kind, err error) error {
+	return &importError{kind: kind, err: err}
+}
+
+func (e *importError) Error() string {
+	return fmt.Sprintf("%v: %v", e.kind, e.err)
+}
+
+func (e *importError) Unwrap() error {
+	return e.err
+}
+
+func (e *importError) Is(target error) bool {
+	return target == e.kind
+}
+
+//configError is an import configuration, an annotation of the ManagedCluster or a setting of the
+//controller, which can't be used. It carries the reason of the import condition, so a new validation
+//only has to return it.
+type configError struct {
+	reason string
+	err    error
+}
+
+//newConfigError returns a configuration error reported with the reason, the message is formatted
+//as with fmt.Errorf
+func newConfigError(reason, format string, a ...interface{}) error {
+	return &configError{reason: reason, err: fmt.Errorf(format, a...)}
+}
+
+func (e *configError) Error() string {
+	return e.err.Error()
+}
+
+func (e *configError) Unwrap() error {
+	return e.err
+}
+
+//configErrorReason returns the condition reason of a configuration error, "" if err is not one
+func configErrorReason(err error) string {
+	var ce *configError
+	if errors.As(err, &ce) {
+		return ce.reason
+	}
+	return ""
+}
+
+func isConfigError(err error) bool {
+	return configErrorReason(err) != ""
+}
+
+//classifyImportError sets the kind of an import error which doesn't have one yet
+func classifyImportError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var ie *importError
+	if errors.As(err, &ie) {
+		return err
+	}
+	if isUnreachableError(err) {
+		return newImportError(ErrUnreachable, err)
+	}
+	return err
+}
+
+//isUnreachableError returns true for network errors, the TLS verification failures
+//are excluded as the cluster was reached
+func isUnreachableError(err error) bool {
+	var hostnameErr x509.HostnameError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var certificateInvalidErr x509.CertificateInvalidError
+	if errors.As(err, &hostnameErr) || errors.As(err, &unknownAuthorityErr) || errors.As(err, &certificateInvalidErr) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func isClusterNotInstalled(err error) bool {
+	return errors.Is(err, ErrClusterNotInstalled)
+}
@@ -0,0 +1,94 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func Test_classifyImportError(t *testing.T) {
+	dialErr := &url.Error{
+		Op:  "Get",
+		URL: "https://10.0.0.1:6443",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")},
+	}
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error
+	}{
+		{
+			name: "nil",
+			err:  nil,
+		},
+		{
+			name: "generic error",
+			err:  fmt.Errorf("generic"),
+		},
+		{
+			name:     "connection refused",
+			err:      dialErr,
+			wantKind: ErrUnreachable,
+		},
+		{
+			name: "tls verification failure",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://10.0.0.1:6443",
+				Err: x509.UnknownAuthorityError{},
+			},
+		},
+		{
+			name:     "kind already set",
+			err:      newImportError(ErrInvalidSecret, dialErr),
+			wantKind: ErrInvalidSecret,
+		},
+		{
+			name:     "invalid exec configuration",
+			err:      fmt.Errorf("%w: command is missing", errInvalidExecConfig),
+			wantKind: ErrInvalidSecret,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyImportError(tt.err)
+			if (got == nil) != (tt.err == nil) {
+				t.Errorf("classifyImportError() = %v, want an error %v", got, tt.err != nil)
+				return
+			}
+			for _, kind := range []error{ErrUnreachable, ErrInvalidSecret, ErrClusterNotInstalled} {
+				if errors.Is(got, kind) != (kind == tt.wantKind) {
+					t.Errorf("classifyImportError() = %v, errors.Is(%v) = %v", got, kind, errors.Is(got, kind))
+				}
+			}
+			if tt.err != nil && !errors.Is(got, tt.err) {
+				t.Errorf("classifyImportError() = %v, the cause %v is lost", got, tt.err)
+			}
+		})
+	}
+}
+
+func Test_configError(t *testing.T) {
+	err := newConfigError(reasonInvalidKlusterletReplicas, "invalid klusterlet replicas: %q is not an integer", "three")
+	if got, want := err.Error(), `invalid klusterlet replicas: "three" is not an integer`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	wrapped := fmt.Errorf("unable to render the klusterlet: %w", err)
+	if !isConfigError(wrapped) {
+		t.Errorf("isConfigError() = false for %v", wrapped)
+	}
+	if got := importErrorReason(wrapped); got != reasonInvalidKlusterletReplicas {
+		t.Errorf("importErrorReason() = %s, want %s", got, reasonInvalidKlusterletReplicas)
+	}
+	if isConfigError(fmt.Errorf("generic")) {
+		t.Error("isConfigError() = true for a generic error")
+	}
+	if got := importErrorReason(fmt.Errorf("generic")); got != reasonManagedClusterNotImported {
+		t.Errorf("importErrorReason() = %s, want %s", got, reasonManagedClusterNotImported)
+	}
+}
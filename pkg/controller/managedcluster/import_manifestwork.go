@@ -7,7 +7,9 @@ package managedcluster
 import (
 	"context"
 	"fmt"
+	"os"
 	"reflect"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,6 +18,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
 	workv1 "github.com/open-cluster-management/api/work/v1"
@@ -24,6 +28,27 @@ import (
 const manifestWorkNamePostfix = "-klusterlet"
 const manifestWorkCRDSPostfix = "-crds"
 
+type manifestWorkApplyStrategy string
+
+const (
+	applyStrategyUpdate          manifestWorkApplyStrategy = "Update"
+	applyStrategyServerSideApply manifestWorkApplyStrategy = "ServerSideApply"
+)
+
+//getManifestWorkApplyStrategy returns the strategy set in MANIFESTWORK_APPLY_STRATEGY,
+//an unknown value falls back to Update
+func getManifestWorkApplyStrategy() manifestWorkApplyStrategy {
+	s := manifestWorkApplyStrategy(os.Getenv(manifestWorkApplyStrategyEnvVarName))
+	switch s {
+	case applyStrategyServerSideApply:
+		return s
+	case "", applyStrategyUpdate:
+		return applyStrategyUpdate
+	}
+	log.Info("Unknown manifestwork apply strategy, using Update", "strategy", s)
+	return applyStrategyUpdate
+}
+
 func manifestWorkNsN(managedCluster *clusterv1.ManagedCluster) (types.NamespacedName, error) {
 	if managedCluster == nil {
 		return types.NamespacedName{}, fmt.Errorf("managedCluster is nil")
@@ -98,25 +123,28 @@ func convertToManifests(us []*unstructured.Unstructured) (manifests []workv1.Man
 	return manifests, nil
 }
 
-// CreateManifestWorks create the manifestWork use for installing klusterlet
+// CreateManifestWorks create the manifestWork use for installing klusterlet,
+// the extra manifests are appended to the klusterlet manifestWork
 func createOrUpdateManifestWorks(
 	client client.Client,
 	scheme *runtime.Scheme,
 	managedCluster *clusterv1.ManagedCluster,
 	ucrds []*unstructured.Unstructured,
 	uyamls []*unstructured.Unstructured,
+	uextras []*unstructured.Unstructured,
 ) (*workv1.ManifestWork, *workv1.ManifestWork, error) {
-	crds, yamls, err := newManifestWorks(managedCluster, ucrds, uyamls)
+	crds, yamls, err := newManifestWorks(managedCluster, ucrds,
+		append(append([]*unstructured.Unstructured{}, uyamls...), uextras...))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	mwcrds, err := createOrUpdateManifestWork(client, scheme, managedCluster, crds)
+	mwcrds, err := createOrUpdateSplitManifestWork(client, scheme, managedCluster, crds)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	mwyamls, err := createOrUpdateManifestWork(client, scheme, managedCluster, yamls)
+	mwyamls, err := createOrUpdateSplitManifestWork(client, scheme, managedCluster, yamls)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -134,6 +162,13 @@ func createOrUpdateManifestWork(
 	if err := controllerutil.SetControllerReference(managedCluster, mw, scheme); err != nil {
 		return nil, err
 	}
+	metadata := getPropagatedMetadata(managedCluster)
+	metadata.apply(mw)
+	setTemplatesVersion(mw)
+	setOwnerLabels(mw, managedCluster)
+	if getManifestWorkApplyStrategy() == applyStrategyServerSideApply {
+		return applyManifestWork(client, mw)
+	}
 	log.Info("Create/update of Import manifestWork", "name", mw.Name, "namespace", mw.Namespace)
 	oldManifestWork := &workv1.ManifestWork{}
 	err := client.Get(context.TODO(), types.NamespacedName{Name: mw.Name, Namespace: mw.Namespace}, oldManifestWork)
@@ -147,7 +182,10 @@ func createOrUpdateManifestWork(
 			return nil, err
 		}
 	} else {
-		if !reflect.DeepEqual(oldManifestWork.Spec, mw.Spec) {
+		metadataChanged := metadata.apply(oldManifestWork)
+		versionChanged := setTemplatesVersion(oldManifestWork)
+		ownerChanged := setOwnerLabels(oldManifestWork, managedCluster)
+		if metadataChanged || versionChanged || ownerChanged || !reflect.DeepEqual(oldManifestWork.Spec, mw.Spec) {
 			log.Info("Exist then Update of Import manifestWork", "name", mw.Name, "namespace", mw.Namespace)
 			oldManifestWork.Spec = mw.Spec
 			if err := client.Update(context.TODO(), oldManifestWork); err != nil {
@@ -158,6 +196,22 @@ func createOrUpdateManifestWork(
 	return mw, nil
 }
 
+//applyManifestWork server-side applies the manifestWork, the ownerReference and labels
+//are part of the applied configuration and so owned by the controller field manager
+func applyManifestWork(c client.Client, mw *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+	log.Info("Server-side apply of Import manifestWork", "name", mw.Name, "namespace", mw.Namespace)
+	mw.TypeMeta = metav1.TypeMeta{
+		APIVersion: workv1.SchemeGroupVersion.String(),
+		Kind:       "ManifestWork",
+	}
+	mw.ResourceVersion = ""
+	if err := c.Patch(context.TODO(), mw, client.Apply,
+		client.FieldOwner(fieldManager()), client.ForceOwnership); err != nil {
+		return nil, err
+	}
+	return mw, nil
+}
+
 func deleteKlusterletManifestWorks(
 	client client.Client,
 	managedCluster *clusterv1.ManagedCluster,
@@ -171,6 +225,16 @@ func deleteKlusterletManifestWorks(
 	if errCRDs != nil {
 		return err
 	}
+	//Delete the CRD manifestWork parts if it was split
+	parts, err := listManifestWorkParts(client, mwNsN.Name+manifestWorkCRDSPostfix, mwNsN.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, name := range parts {
+		if err := deleteManifestWork(client, name, mwNsN.Namespace); err != nil {
+			return err
+		}
+	}
 
 	return nil
 	//The manifestworks yamls should not be deleted otherwize
@@ -199,29 +263,60 @@ func deleteManifestWork(client client.Client, name, namespace string) error {
 }
 
 func deleteAllOtherManifestWork(c client.Client, instance *clusterv1.ManagedCluster) error {
-	mwNsN, err := manifestWorkNsN(instance)
+	names, err := listAllOtherManifestWork(c, instance)
 	if err != nil {
 		return err
 	}
+	for _, name := range names {
+		err := deleteManifestWork(c, name, instance.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//manifestWorkDeletionRequeueAfter is the delay between the checks of the deletion of the manifestworks
+//during the cleanup of a deleted ManagedCluster, the deletion events also trigger a check
+const manifestWorkDeletionRequeueAfter = 30 * time.Second
+
+//newManifestWorkDeletionPredicate filters the events to the deletion of the manifestworks other than
+//the klusterlet ones, the cleanup of a deleted ManagedCluster waits on them
+func newManifestWorkDeletionPredicate() predicate.Predicate {
+	return predicate.Predicate(predicate.Funcs{
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return e.Meta != nil &&
+				!isKlusterletManifestWork(e.Meta.GetName(), e.Meta.GetNamespace()+manifestWorkNamePostfix)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool { return false },
+	})
+}
+
+//listAllOtherManifestWork returns the names of the manifestworks of the cluster namespace
+//which are not the klusterlet manifestworks, the addons manifestworks for example
+func listAllOtherManifestWork(c client.Client, instance *clusterv1.ManagedCluster) ([]string, error) {
+	mwNsN, err := manifestWorkNsN(instance)
+	if err != nil {
+		return nil, err
+	}
 
 	mws := &workv1.ManifestWorkList{}
 	err = c.List(context.TODO(), mws, &client.ListOptions{
 		Namespace: mwNsN.Namespace,
 	})
-
 	if err != nil {
-		return err
+		return nil, err
 	}
+	names := make([]string, 0)
 	for _, mw := range mws.Items {
-		if mw.GetName() == mwNsN.Name || mw.GetName() == mwNsN.Name+manifestWorkCRDSPostfix {
+		if isKlusterletManifestWork(mw.GetName(), mwNsN.Name) {
 			continue
 		}
-		err := deleteManifestWork(c, mw.GetName(), mw.GetNamespace())
-		if err != nil {
-			return err
-		}
+		names = append(names, mw.GetName())
 	}
-	return nil
+	return names, nil
 }
 
 func evictKlusterletManifestWorks(
@@ -239,7 +334,30 @@ func evictKlusterletManifestWorks(
 	}
 
 	//Delete the YAML manifestWork
-	return evictManifestWork(client, mwNsN.Name, mwNsN.Namespace)
+	if err := evictManifestWork(client, mwNsN.Name, mwNsN.Namespace); err != nil {
+		return err
+	}
+
+	//Delete the manifestWork parts if they were split
+	for _, name := range []string{mwNsN.Name + manifestWorkCRDSPostfix, mwNsN.Name} {
+		parts, err := listManifestWorkParts(client, name, mwNsN.Namespace)
+		if err != nil {
+			return err
+		}
+		for _, part := range parts {
+			if err := evictManifestWork(client, part, mwNsN.Namespace); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//isKlusterletManifestWork returns true if name is one of the klusterlet manifestworks or of their parts
+func isKlusterletManifestWork(name, klusterletManifestWorkName string) bool {
+	crdsName := klusterletManifestWorkName + manifestWorkCRDSPostfix
+	return name == klusterletManifestWorkName || name == crdsName ||
+		isManifestWorkPart(name, klusterletManifestWorkName) || isManifestWorkPart(name, crdsName)
 }
 
 func evictManifestWork(client client.Client, name, namespace string) error {
@@ -275,8 +393,7 @@ func evictAllOtherManifestWork(c client.Client, instance *clusterv1.ManagedClust
 		return err
 	}
 	for _, mw := range mws.Items {
-		if (mw.GetName() == mwNsN.Name || mw.GetName() == mwNsN.Name+manifestWorkCRDSPostfix) &&
-			mw.GetNamespace() == mwNsN.Namespace {
+		if isKlusterletManifestWork(mw.GetName(), mwNsN.Name) && mw.GetNamespace() == mwNsN.Namespace {
 			continue
 		}
 		err := evictManifestWork(c, mw.GetName(), mw.GetNamespace())
@@ -21,6 +21,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
 func init() {
@@ -184,7 +185,7 @@ func Test_newManifestWorks(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Logf("Test name: %s", tt.name)
-			crds, yamls, err := generateImportYAMLs(testClient, tt.args.managedCluster, []string{})
+			crds, yamls, err := generateImportYAMLs(testClient, nil, tt.args.managedCluster, []string{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("generateImportYAMLs error=%v, wantErr %v", err, tt.wantErr)
 			}
@@ -443,11 +444,11 @@ func Test_createOrUpdateManifestWork(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Logf("Test name: %s", tt.name)
-			crds, yamls, err := generateImportYAMLs(tt.args.client, tt.args.managedCluster, []string{})
+			crds, yamls, err := generateImportYAMLs(tt.args.client, nil, tt.args.managedCluster, []string{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("generateImportYAMLs error=%v, wantErr %v", err, tt.wantErr)
 			}
-			gotCRDs, gotYAMLs, err := createOrUpdateManifestWorks(tt.args.client, testScheme, tt.args.managedCluster, crds, yamls)
+			gotCRDs, gotYAMLs, err := createOrUpdateManifestWorks(tt.args.client, testScheme, tt.args.managedCluster, crds, yamls, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("createManifestWork() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -480,6 +481,28 @@ func Test_createOrUpdateManifestWork(t *testing.T) {
 	}
 }
 
+func Test_getManifestWorkApplyStrategy(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  manifestWorkApplyStrategy
+	}{
+		{name: "default", value: "", want: applyStrategyUpdate},
+		{name: "update", value: "Update", want: applyStrategyUpdate},
+		{name: "server-side apply", value: "ServerSideApply", want: applyStrategyServerSideApply},
+		{name: "unknown", value: "Replace", want: applyStrategyUpdate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(manifestWorkApplyStrategyEnvVarName, tt.value)
+			defer os.Unsetenv(manifestWorkApplyStrategyEnvVarName)
+			if got := getManifestWorkApplyStrategy(); got != tt.want {
+				t.Errorf("getManifestWorkApplyStrategy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_deleteManifestWorks(t *testing.T) {
 	os.Setenv("DEFAULT_IMAGE_PULL_SECRET", imagePullSecretNameSecret)
 	os.Setenv("POD_NAMESPACE", managedClusterNameSecret)
@@ -802,3 +825,91 @@ func Test_evictAllOtherManifestWork(t *testing.T) {
 		})
 	}
 }
+
+func Test_listAllOtherManifestWork(t *testing.T) {
+	testScheme := scheme.Scheme
+	testScheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+	testScheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	newManifestWork := func(name string) *workv1.ManifestWork {
+		return &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "othermanifestwork",
+			},
+		}
+	}
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "othermanifestwork",
+		},
+	}
+	c := fake.NewFakeClientWithScheme(testScheme,
+		newManifestWork("othermanifestwork"+manifestWorkNamePostfix),
+		newManifestWork("othermanifestwork"+manifestWorkNamePostfix+manifestWorkCRDSPostfix),
+		newManifestWork("othermanifestwork"+manifestWorkNamePostfix+manifestWorkPartInfix+"1"),
+		newManifestWork("othermanifestwork-addon"),
+	)
+	got, err := listAllOtherManifestWork(c, managedCluster)
+	if err != nil {
+		t.Errorf("listAllOtherManifestWork() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, []string{"othermanifestwork-addon"}) {
+		t.Errorf("listAllOtherManifestWork() = %v, want [othermanifestwork-addon]", got)
+	}
+}
+
+func Test_newManifestWorkDeletionPredicate(t *testing.T) {
+	newManifestWork := func(name string) *workv1.ManifestWork {
+		return &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "mycluster",
+			},
+		}
+	}
+	klusterlet := newManifestWork("mycluster" + manifestWorkNamePostfix)
+	crds := newManifestWork("mycluster" + manifestWorkNamePostfix + manifestWorkCRDSPostfix)
+	addon := newManifestWork("mycluster-addon")
+	p := newManifestWorkDeletionPredicate()
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{
+			name: "addon deleted",
+			got:  p.Delete(event.DeleteEvent{Meta: addon, Object: addon}),
+			want: true,
+		},
+		{
+			name: "klusterlet deleted",
+			got:  p.Delete(event.DeleteEvent{Meta: klusterlet, Object: klusterlet}),
+			want: false,
+		},
+		{
+			name: "klusterlet crds deleted",
+			got:  p.Delete(event.DeleteEvent{Meta: crds, Object: crds}),
+			want: false,
+		},
+		{
+			name: "addon created",
+			got:  p.Create(event.CreateEvent{Meta: addon, Object: addon}),
+			want: false,
+		},
+		{
+			name: "addon updated",
+			got:  p.Update(event.UpdateEvent{MetaOld: addon, ObjectOld: addon, MetaNew: addon, ObjectNew: addon}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("predicate = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//importMethodAnnotation records how the klusterlet was deployed on the managed cluster, to audit how
+//the clusters of a fleet were onboarded
+const importMethodAnnotation = "import.open-cluster-management.io/import-method"
+
+//The import methods, importMethodManifestWork is only recorded for the clusters which joined without
+//being imported by the controller (manual import)
+const (
+	importMethodManifestWork                = "manifestwork"
+	importMethodHiveKubeconfig              = "hive-kubeconfig"
+	importMethodAutoImportKubeconfig        = "auto-import-kubeconfig"
+	importMethodAutoImportToken             = "auto-import-token"
+	importMethodAutoImportExec              = "auto-import-exec"
+	importMethodAutoImportClientCertificate = "auto-import-client-certificate"
+	importMethodSelfManaged                 = "self-managed"
+)
+
+//resolveImportMethod returns the method importCluster uses for the cluster, in the same order,
+//empty if the auto-import-secret has no usable credentials
+func resolveImportMethod(
+	managedCluster *clusterv1.ManagedCluster,
+	clusterDeployment *hivev1.ClusterDeployment,
+	autoImportSecret *corev1.Secret,
+) string {
+	switch {
+	case IsSelfManaged(managedCluster):
+		return importMethodSelfManaged
+	case autoImportSecret != nil:
+		return autoImportSecretMethod(autoImportSecret)
+	case clusterDeployment != nil:
+		return importMethodHiveKubeconfig
+	}
+	return ""
+}
+
+//autoImportSecretMethod returns the import method of the credentials of the auto-import-secret
+func autoImportSecretMethod(autoImportSecret *corev1.Secret) string {
+	has := func(key string) bool {
+		_, ok := autoImportSecret.Data[key]
+		return ok
+	}
+	switch {
+	case has("kubeconfig"):
+		return importMethodAutoImportKubeconfig
+	case !has("server"):
+		return ""
+	case has("token"):
+		return importMethodAutoImportToken
+	case has(autoImportSecretExecKey):
+		return importMethodAutoImportExec
+	case has(autoImportSecretClientCertificateKey) && has(autoImportSecretClientKeyKey):
+		return importMethodAutoImportClientCertificate
+	}
+	return ""
+}
+
+//recordImportMethod sets the import method on the ManagedCluster if it changed
+func (r *ReconcileManagedCluster) recordImportMethod(managedCluster *clusterv1.ManagedCluster, method string) error {
+	if method == "" || managedCluster.GetAnnotations()[importMethodAnnotation] == method {
+		return nil
+	}
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	annotations := managedCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[importMethodAnnotation] = method
+	managedCluster.SetAnnotations(annotations)
+	return r.client.Patch(context.TODO(), managedCluster, patch)
+}
+
+//recordJoinedImportMethod records the manifestwork method for an online cluster without import method,
+//the klusterlet was deployed outside of the controller and is kept by the manifestworks
+func (r *ReconcileManagedCluster) recordJoinedImportMethod(managedCluster *clusterv1.ManagedCluster) error {
+	if _, ok := managedCluster.GetAnnotations()[importMethodAnnotation]; ok {
+		return nil
+	}
+	return r.recordImportMethod(managedCluster, importMethodManifestWork)
+}
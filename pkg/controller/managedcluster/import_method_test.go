@@ -0,0 +1,156 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_resolveImportMethod(t *testing.T) {
+	secret := func(keys ...string) *corev1.Secret {
+		s := &corev1.Secret{Data: map[string][]byte{}}
+		for _, k := range keys {
+			s.Data[k] = []byte("value")
+		}
+		return s
+	}
+	tests := []struct {
+		name              string
+		labels            map[string]string
+		clusterDeployment *hivev1.ClusterDeployment
+		autoImportSecret  *corev1.Secret
+		want              string
+	}{
+		{
+			name: "not imported by the controller",
+			want: "",
+		},
+		{
+			name:   "self managed",
+			labels: map[string]string{SelfManagedLabel: "true"},
+			want:   importMethodSelfManaged,
+		},
+		{
+			name:              "hive",
+			clusterDeployment: &hivev1.ClusterDeployment{},
+			want:              importMethodHiveKubeconfig,
+		},
+		{
+			name:             "kubeconfig",
+			autoImportSecret: secret("kubeconfig", "autoImportRetry"),
+			want:             importMethodAutoImportKubeconfig,
+		},
+		{
+			name:             "token",
+			autoImportSecret: secret("token", "server"),
+			want:             importMethodAutoImportToken,
+		},
+		{
+			name:             "exec",
+			autoImportSecret: secret(autoImportSecretExecKey, "server"),
+			want:             importMethodAutoImportExec,
+		},
+		{
+			name: "client certificate",
+			autoImportSecret: secret(autoImportSecretClientCertificateKey, autoImportSecretClientKeyKey,
+				"server"),
+			want: importMethodAutoImportClientCertificate,
+		},
+		{
+			name:             "no credentials",
+			autoImportSecret: secret("token"),
+			want:             "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cluster",
+					Labels: tt.labels,
+				},
+			}
+			if got := resolveImportMethod(managedCluster, tt.clusterDeployment, tt.autoImportSecret); got != tt.want {
+				t.Errorf("resolveImportMethod() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_recordImportMethod(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	tests := []struct {
+		name       string
+		annotation string
+		record     func(r *ReconcileManagedCluster, mc *clusterv1.ManagedCluster) error
+		want       string
+	}{
+		{
+			name: "import recorded",
+			record: func(r *ReconcileManagedCluster, mc *clusterv1.ManagedCluster) error {
+				return r.recordImportMethod(mc, importMethodAutoImportToken)
+			},
+			want: importMethodAutoImportToken,
+		},
+		{
+			name:       "new import recorded",
+			annotation: importMethodManifestWork,
+			record: func(r *ReconcileManagedCluster, mc *clusterv1.ManagedCluster) error {
+				return r.recordImportMethod(mc, importMethodHiveKubeconfig)
+			},
+			want: importMethodHiveKubeconfig,
+		},
+		{
+			name: "joined cluster",
+			record: func(r *ReconcileManagedCluster, mc *clusterv1.ManagedCluster) error {
+				return r.recordJoinedImportMethod(mc)
+			},
+			want: importMethodManifestWork,
+		},
+		{
+			name:       "joined cluster keeps its import method",
+			annotation: importMethodAutoImportKubeconfig,
+			record: func(r *ReconcileManagedCluster, mc *clusterv1.ManagedCluster) error {
+				return r.recordJoinedImportMethod(mc)
+			},
+			want: importMethodAutoImportKubeconfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			}
+			if tt.annotation != "" {
+				managedCluster.Annotations = map[string]string{importMethodAnnotation: tt.annotation}
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+				scheme: testscheme,
+			}
+			if err := tt.record(r, managedCluster); err != nil {
+				t.Fatal(err)
+			}
+			got := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if v := got.GetAnnotations()[importMethodAnnotation]; v != tt.want {
+				t.Errorf("import method = %q, want %q", v, tt.want)
+			}
+		})
+	}
+}
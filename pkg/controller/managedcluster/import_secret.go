@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/open-cluster-management/applier/pkg/templateprocessor"
 	corev1 "k8s.io/api/core/v1"
@@ -35,37 +36,61 @@ func importSecretNsN(managedCluster *clusterv1.ManagedCluster) (types.Namespaced
 	}
 	return types.NamespacedName{
 		Name:      managedCluster.Name + importSecretNamePostfix,
-		Namespace: managedCluster.Name,
+		Namespace: importSecretNamespace(managedCluster),
 	}, nil
 }
 
+//importSecretNamespace returns the namespace of the import secret, the IMPORT_SECRET_NAMESPACE
+//if set, otherwise the cluster namespace
+func importSecretNamespace(managedCluster *clusterv1.ManagedCluster) string {
+	if namespace := os.Getenv(importSecretNamespaceEnvVarName); namespace != "" {
+		return namespace
+	}
+	return managedCluster.Name
+}
+
+//isImportSecretOutsideClusterNamespace returns true if the import secret is stored in a dedicated namespace,
+//it is then not removed with the cluster namespace
+func isImportSecretOutsideClusterNamespace(managedCluster *clusterv1.ManagedCluster) bool {
+	return importSecretNamespace(managedCluster) != managedCluster.Name
+}
+
+//deleteImportSecret deletes the import secret of the cluster
+func deleteImportSecret(c client.Client, managedCluster *clusterv1.ManagedCluster) error {
+	secretNsN, err := importSecretNsN(managedCluster)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretNsN.Name,
+			Namespace: secretNsN.Namespace,
+		},
+	}
+	if err := c.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 func newImportSecret(
 	managedCluster *clusterv1.ManagedCluster,
 	crds []*unstructured.Unstructured,
 	yamls []*unstructured.Unstructured,
 ) (*corev1.Secret, error) {
-	importYAML := new(bytes.Buffer)
-	crdsYAML := new(bytes.Buffer)
-
 	secretNsN, err := importSecretNsN(managedCluster)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, crd := range crds {
-		b, err := templateprocessor.ToYAMLUnstructured(crd)
-		if err != nil {
-			return nil, err
-		}
-		crdsYAML.WriteString(fmt.Sprintf("\n---\n%s", string(b)))
+	crdsYAML, err := toYAMLDocuments(crds)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, y := range yamls {
-		b, err := templateprocessor.ToYAMLUnstructured(y)
-		if err != nil {
-			return nil, err
-		}
-		importYAML.WriteString(fmt.Sprintf("\n---\n%s", string(b)))
+	importYAML, err := toYAMLDocuments(yamls)
+	if err != nil {
+		return nil, err
 	}
 
 	secret := &corev1.Secret{
@@ -73,16 +98,33 @@ func newImportSecret(
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretNsN.Name,
 			Namespace: secretNsN.Namespace,
+			//the cluster label identifies the secret of the cluster in a dedicated namespace
+			Labels: map[string]string{
+				clusterLabel: managedCluster.Name,
+			},
 		},
 		Data: map[string][]byte{
-			importYAMLKey: importYAML.Bytes(),
-			crdsYAMLKey:   crdsYAML.Bytes(),
+			importYAMLKey: importYAML,
+			crdsYAMLKey:   crdsYAML,
 		},
 	}
 
 	return secret, nil
 }
 
+//toYAMLDocuments converts the objects to a multi-document YAML, each document starts with a separator
+func toYAMLDocuments(objs []*unstructured.Unstructured) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for _, obj := range objs {
+		b, err := templateprocessor.ToYAMLUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(fmt.Sprintf("\n---\n%s", string(b)))
+	}
+	return buf.Bytes(), nil
+}
+
 func createOrUpdateImportSecret(
 	client client.Client,
 	scheme *runtime.Scheme,
@@ -97,6 +139,9 @@ func createOrUpdateImportSecret(
 	if err := controllerutil.SetControllerReference(managedCluster, secret, scheme); err != nil {
 		return nil, err
 	}
+	metadata := getPropagatedMetadata(managedCluster)
+	metadata.apply(secret)
+	setOwnerLabels(secret, managedCluster)
 
 	log.Info("Create/update of Import secret", "name", secret.Name, "namespace", secret.Namespace)
 	oldImportSecret := &corev1.Secret{}
@@ -111,9 +156,20 @@ func createOrUpdateImportSecret(
 			return nil, err
 		}
 	} else {
-		if !bytes.Equal(oldImportSecret.Data[importYAMLKey], secret.Data[importYAMLKey]) ||
-			!bytes.Equal(oldImportSecret.Data[crdsYAMLKey], secret.Data[crdsYAMLKey]) {
-			oldImportSecret.Data = secret.Data
+		metadataChanged := metadata.apply(oldImportSecret)
+		if oldImportSecret.Labels[clusterLabel] != managedCluster.Name {
+			if oldImportSecret.Labels == nil {
+				oldImportSecret.Labels = make(map[string]string)
+			}
+			oldImportSecret.Labels[clusterLabel] = managedCluster.Name
+			metadataChanged = true
+		}
+		if setOwnerLabels(oldImportSecret, managedCluster) {
+			metadataChanged = true
+		}
+		data, dataChanged := mergeImportSecretData(oldImportSecret.Data, secret.Data, importSecretExternalKeys())
+		if metadataChanged || dataChanged {
+			oldImportSecret.Data = data
 			if err := client.Update(context.TODO(), oldImportSecret); err != nil {
 				return nil, err
 			}
@@ -0,0 +1,54 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"bytes"
+	"os"
+	"strings"
+)
+
+//importSecretExternalKeys returns the keys of the import secret owned by an external controller,
+//for example a secret rotator updating the token of the import.yaml
+func importSecretExternalKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, k := range strings.Split(os.Getenv(importSecretExternalKeysEnvVarName), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys[k] = true
+		}
+	}
+	return keys
+}
+
+//mergeImportSecretData returns the data of the import secret to store and true if it differs from the
+//current data. The keys generated by the controller are overwritten, the external keys are only set
+//when they are missing and the external keys not generated by the controller are kept. The other keys
+//of the current data are dropped.
+func mergeImportSecretData(current, generated map[string][]byte, external map[string]bool) (map[string][]byte, bool) {
+	data := make(map[string][]byte, len(generated))
+	for k, v := range generated {
+		if cv, ok := current[k]; ok && external[k] {
+			v = cv
+		}
+		data[k] = v
+	}
+	for k := range external {
+		if cv, ok := current[k]; ok {
+			data[k] = cv
+		}
+	}
+
+	changed := false
+	for k, v := range data {
+		if cv, ok := current[k]; !ok || !bytes.Equal(cv, v) {
+			changed = true
+		}
+	}
+	//A key dropped from the data, for example a key the controller doesn't generate anymore, is removed
+	for k := range current {
+		if _, ok := data[k]; !ok {
+			changed = true
+		}
+	}
+	return data, changed
+}
@@ -0,0 +1,164 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_mergeImportSecretData(t *testing.T) {
+	generated := map[string][]byte{importYAMLKey: []byte("import"), crdsYAMLKey: []byte("crds")}
+	tests := []struct {
+		name        string
+		current     map[string][]byte
+		external    map[string]bool
+		want        map[string][]byte
+		wantChanged bool
+	}{
+		{
+			name:        "new secret",
+			current:     nil,
+			want:        generated,
+			wantChanged: true,
+		},
+		{
+			name:        "up to date",
+			current:     map[string][]byte{importYAMLKey: []byte("import"), crdsYAMLKey: []byte("crds")},
+			want:        generated,
+			wantChanged: false,
+		},
+		{
+			name:        "generated key reverted",
+			current:     map[string][]byte{importYAMLKey: []byte("rotated"), crdsYAMLKey: []byte("crds")},
+			want:        generated,
+			wantChanged: true,
+		},
+		{
+			name:        "external key kept",
+			current:     map[string][]byte{importYAMLKey: []byte("rotated"), crdsYAMLKey: []byte("crds")},
+			external:    map[string]bool{importYAMLKey: true},
+			want:        map[string][]byte{importYAMLKey: []byte("rotated"), crdsYAMLKey: []byte("crds")},
+			wantChanged: false,
+		},
+		{
+			name:        "missing external key generated",
+			current:     map[string][]byte{crdsYAMLKey: []byte("crds")},
+			external:    map[string]bool{importYAMLKey: true},
+			want:        generated,
+			wantChanged: true,
+		},
+		{
+			name: "external key not generated kept",
+			current: map[string][]byte{importYAMLKey: []byte("import"), crdsYAMLKey: []byte("crds"),
+				"token": []byte("rotated")},
+			external: map[string]bool{"token": true},
+			want: map[string][]byte{importYAMLKey: []byte("import"), crdsYAMLKey: []byte("crds"),
+				"token": []byte("rotated")},
+			wantChanged: false,
+		},
+		{
+			name: "stale key removed",
+			current: map[string][]byte{importYAMLKey: []byte("import"), crdsYAMLKey: []byte("crds"),
+				"unknown": []byte("value")},
+			want:        generated,
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := mergeImportSecretData(tt.current, generated, tt.external)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeImportSecretData() = %v, want %v", got, tt.want)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("mergeImportSecretData() changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func Test_createOrUpdateImportSecret_externalKeys(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+	}
+	yamls := []*unstructured.Unstructured{{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": "open-cluster-management-agent"},
+	}}}
+	c := fake.NewFakeClientWithScheme(testscheme, managedCluster)
+	secret, err := createOrUpdateImportSecret(c, testscheme, managedCluster, nil, yamls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := secret.Data[importYAMLKey]
+	secretNsN, err := importSecretNsN(managedCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//An external rotator updates the import.yaml and adds its own key
+	rotated := &corev1.Secret{}
+	if err := c.Get(context.TODO(), secretNsN, rotated); err != nil {
+		t.Fatal(err)
+	}
+	rotated.Data[importYAMLKey] = []byte("rotated")
+	rotated.Data["rotation"] = []byte("1")
+	if err := c.Update(context.TODO(), rotated); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		externalKeys string
+		wantImport   string
+		wantRotation bool
+	}{
+		{
+			name:         "external keys",
+			externalKeys: importYAMLKey + ", rotation",
+			wantImport:   "rotated",
+			wantRotation: true,
+		},
+		{
+			name:         "no external key",
+			externalKeys: "",
+			wantImport:   string(generated),
+			wantRotation: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(importSecretExternalKeysEnvVarName, tt.externalKeys)
+			defer os.Unsetenv(importSecretExternalKeysEnvVarName)
+			if _, err := createOrUpdateImportSecret(c, testscheme, managedCluster, nil, yamls); err != nil {
+				t.Fatal(err)
+			}
+			got := &corev1.Secret{}
+			if err := c.Get(context.TODO(), secretNsN, got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got.Data[importYAMLKey]) != tt.wantImport {
+				t.Errorf("import.yaml = %q, want %q", got.Data[importYAMLKey], tt.wantImport)
+			}
+			if _, ok := got.Data["rotation"]; ok != tt.wantRotation {
+				t.Errorf("rotation key kept = %v, want %v", ok, tt.wantRotation)
+			}
+		})
+	}
+}
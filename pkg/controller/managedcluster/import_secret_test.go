@@ -6,6 +6,7 @@
 package managedcluster
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"reflect"
@@ -20,6 +21,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -219,7 +221,7 @@ func Test_newImportSecret(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Logf("Test name: %s", tt.name)
-			crds, yamls, err := generateImportYAMLs(tt.args.client, tt.args.managedCluster, []string{})
+			crds, yamls, err := generateImportYAMLs(tt.args.client, nil, tt.args.managedCluster, []string{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("generateImportYAMLs error=%v, wantErr %v", err, tt.wantErr)
 			}
@@ -316,7 +318,7 @@ func Test_createOrUpdateImportSecret(t *testing.T) {
 		imagePullSecret,
 	)
 
-	crds, yamls, err := generateImportYAMLs(fakeClient, managedCluster, []string{})
+	crds, yamls, err := generateImportYAMLs(fakeClient, nil, managedCluster, []string{})
 	if err != nil {
 		t.Errorf("generateImportYAMLs error=%v", err)
 	}
@@ -326,7 +328,7 @@ func Test_createOrUpdateImportSecret(t *testing.T) {
 		t.Errorf("fail to initialize import secret, error = %v", err)
 	}
 
-	crdsUpdate, yamlsUpdate, err := generateImportYAMLs(fakeClient, managedCluster, []string{})
+	crdsUpdate, yamlsUpdate, err := generateImportYAMLs(fakeClient, nil, managedCluster, []string{})
 	if err != nil {
 		t.Errorf("generateImportYAMLs error=%v", err)
 	}
@@ -481,6 +483,10 @@ func TestTemplating(t *testing.T) {
 		HubKubeConfigSecretName   string
 		HubKubeConfigSecret       string
 		RegistrationOperatorImage string
+		KlusterletResources       string
+		KlusterletArgs            []string
+		KlusterletReplicas        int
+		KlusterletClusterRoleName string
 	}{
 		ClusterName:               "klusterlet",
 		KlusterletNamespace:       "KlusterletNamespace",
@@ -492,6 +498,7 @@ func TestTemplating(t *testing.T) {
 		HubKubeConfigSecretName:   "HubKubeConfigSecretName",
 		HubKubeConfigSecret:       "HubKubeConfigSecret",
 		RegistrationOperatorImage: "RegistrationOperatorImage",
+		KlusterletClusterRoleName: "klusterlet",
 	}
 
 	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
@@ -551,3 +558,51 @@ func newBootstrapServiceAccount(managedCluster *clusterv1.ManagedCluster) (*core
 
 	return sa, nil
 }
+
+func Test_importSecretInDedicatedNamespace(t *testing.T) {
+	os.Setenv(importSecretNamespaceEnvVarName, "import-secrets")
+	defer os.Unsetenv(importSecretNamespaceEnvVarName)
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dedicated",
+		},
+	}
+	secretNsN, err := importSecretNsN(managedCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secretNsN.Namespace != "import-secrets" || secretNsN.Name != "dedicated"+importSecretNamePostfix {
+		t.Errorf("importSecretNsN() = %v, want import-secrets/dedicated-import", secretNsN)
+	}
+	if !isImportSecretOutsideClusterNamespace(managedCluster) {
+		t.Error("expected the import secret to be outside the cluster namespace")
+	}
+
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	c := fake.NewFakeClientWithScheme(testscheme, managedCluster)
+	if _, err := createOrUpdateImportSecret(c, testscheme, managedCluster, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), secretNsN, secret); err != nil {
+		t.Fatal(err)
+	}
+	if secret.Labels[clusterLabel] != "dedicated" {
+		t.Errorf("expected the cluster label on the import secret, got %v", secret.Labels)
+	}
+	if !isImportSecret(secret) {
+		t.Error("expected the secret to be recognized as an import secret")
+	}
+
+	if err := deleteImportSecret(c, managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(context.TODO(), secretNsN, &corev1.Secret{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the import secret to be deleted, got %v", err)
+	}
+	if err := deleteImportSecret(c, managedCluster); err != nil {
+		t.Errorf("expected the deletion of a missing import secret to succeed, got %v", err)
+	}
+}
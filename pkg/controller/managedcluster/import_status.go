@@ -0,0 +1,112 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//importStatusAnnotation holds the import status of the ManagedCluster as JSON, for external tooling
+//aggregating the imports, its fields and values are stable unlike the condition messages
+const importStatusAnnotation = "import.open-cluster-management.io/import-status"
+
+//ImportPhase summarizes the import condition of a ManagedCluster
+type ImportPhase string
+
+const (
+	//ImportPhasePending means the import was not attempted yet (manual import or no auto-import-secret)
+	ImportPhasePending ImportPhase = "Pending"
+	//ImportPhaseWaiting means the import waits on a prerequisite (namespace, cluster installation...)
+	ImportPhaseWaiting ImportPhase = "Waiting"
+	//ImportPhaseImported means the last import succeeded
+	ImportPhaseImported ImportPhase = "Imported"
+	//ImportPhaseFailed means the last import failed, the reason tells why
+	ImportPhaseFailed ImportPhase = "Failed"
+)
+
+//importWaitingReasons are the reasons of the import condition set while waiting on a prerequisite
+var importWaitingReasons = []string{
+	reasonWaitingForClusterNamespace,
+	reasonWaitingForNamespaceTermination,
+	reasonClusterNamespaceTerminating,
+	reasonClusterNotInstalled,
+}
+
+//ImportStatus is the machine-readable import status stored in the importStatusAnnotation
+type ImportStatus struct {
+	Phase ImportPhase `json:"phase"`
+	//Reason is the reason of the ManagedClusterImportSucceeded condition
+	Reason string `json:"reason,omitempty"`
+	//LastImportTime is the time of the last successful import
+	LastImportTime *metav1.Time `json:"lastImportTime,omitempty"`
+	//RetriesRemaining and RetryTotal are set once an auto-import attempt failed
+	RetriesRemaining *int `json:"retriesRemaining,omitempty"`
+	RetryTotal       *int `json:"retryTotal,omitempty"`
+	//Offline is true if the cluster is not available
+	Offline bool `json:"offline"`
+}
+
+//newImportStatus computes the import status from the conditions of the ManagedCluster
+func newImportStatus(managedCluster *clusterv1.ManagedCluster) ImportStatus {
+	status := ImportStatus{
+		Phase:   ImportPhasePending,
+		Offline: checkOffLine(managedCluster),
+	}
+	if c := meta.FindStatusCondition(managedCluster.Status.Conditions, importConditionType()); c != nil {
+		status.Reason = c.Reason
+		switch {
+		case c.Status == metav1.ConditionTrue:
+			status.Phase = ImportPhaseImported
+			lastImportTime := c.LastTransitionTime
+			status.LastImportTime = &lastImportTime
+		case isImportWaitingReason(c.Reason):
+			status.Phase = ImportPhaseWaiting
+		default:
+			status.Phase = ImportPhaseFailed
+		}
+	}
+	if c := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionAutoImportRetriesRemaining); c != nil &&
+		c.Reason != reasonAutoImportSucceeded {
+		var remaining, total int
+		if _, err := fmt.Sscanf(c.Message, autoImportRetriesMessageFormat, &remaining, &total); err == nil {
+			status.RetriesRemaining = &remaining
+			status.RetryTotal = &total
+		}
+	}
+	return status
+}
+
+func isImportWaitingReason(reason string) bool {
+	for _, r := range importWaitingReasons {
+		if reason == r {
+			return true
+		}
+	}
+	return false
+}
+
+//syncImportStatus updates the import status annotation of the ManagedCluster if it changed
+func (r *ReconcileManagedCluster) syncImportStatus(managedCluster *clusterv1.ManagedCluster) error {
+	b, err := json.Marshal(newImportStatus(managedCluster))
+	if err != nil {
+		return err
+	}
+	if managedCluster.GetAnnotations()[importStatusAnnotation] == string(b) {
+		return nil
+	}
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	annotations := managedCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[importStatusAnnotation] = string(b)
+	managedCluster.SetAnnotations(annotations)
+	return r.client.Patch(context.TODO(), managedCluster, patch)
+}
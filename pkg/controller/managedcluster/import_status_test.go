@@ -0,0 +1,151 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_newImportStatus(t *testing.T) {
+	imported := metav1.Condition{
+		Type:               ManagedClusterImportSucceeded,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonManagedClusterImported,
+		LastTransitionTime: metav1.Now(),
+	}
+	tests := []struct {
+		name          string
+		conditions    []metav1.Condition
+		wantPhase     ImportPhase
+		wantReason    string
+		wantRetries   int
+		wantTotal     int
+		wantRetryInfo bool
+		wantOffline   bool
+	}{
+		{
+			name:      "no condition",
+			wantPhase: ImportPhasePending,
+		},
+		{
+			name:       "imported",
+			conditions: []metav1.Condition{imported},
+			wantPhase:  ImportPhaseImported,
+			wantReason: reasonManagedClusterImported,
+		},
+		{
+			name: "waiting for the namespace",
+			conditions: []metav1.Condition{{
+				Type:   ManagedClusterImportSucceeded,
+				Status: metav1.ConditionFalse,
+				Reason: reasonWaitingForClusterNamespace,
+			}},
+			wantPhase:  ImportPhaseWaiting,
+			wantReason: reasonWaitingForClusterNamespace,
+		},
+		{
+			name: "failed with retries",
+			conditions: []metav1.Condition{
+				{
+					Type:   ManagedClusterImportSucceeded,
+					Status: metav1.ConditionFalse,
+					Reason: reasonManagedClusterUnreachable,
+				},
+				newAutoImportRetriesCondition(2, 5),
+				{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: metav1.ConditionUnknown,
+				},
+			},
+			wantPhase:     ImportPhaseFailed,
+			wantReason:    reasonManagedClusterUnreachable,
+			wantRetryInfo: true,
+			wantRetries:   2,
+			wantTotal:     5,
+			wantOffline:   true,
+		},
+		{
+			name: "retries exhausted",
+			conditions: []metav1.Condition{
+				{
+					Type:   ManagedClusterImportSucceeded,
+					Status: metav1.ConditionFalse,
+					Reason: reasonManagedClusterUnreachable,
+				},
+				newAutoImportRetriesCondition(-1, 5),
+			},
+			wantPhase:     ImportPhaseFailed,
+			wantReason:    reasonManagedClusterUnreachable,
+			wantRetryInfo: true,
+			wantRetries:   0,
+			wantTotal:     5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := tt.conditions
+			if !tt.wantOffline {
+				conditions = append(conditions, metav1.Condition{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: metav1.ConditionTrue,
+				})
+			}
+			managedCluster := &clusterv1.ManagedCluster{
+				Status: clusterv1.ManagedClusterStatus{Conditions: conditions},
+			}
+			got := newImportStatus(managedCluster)
+			if got.Phase != tt.wantPhase || got.Reason != tt.wantReason {
+				t.Errorf("newImportStatus() = %s/%s, want %s/%s", got.Phase, got.Reason, tt.wantPhase, tt.wantReason)
+			}
+			if (got.LastImportTime != nil) != (tt.wantPhase == ImportPhaseImported) {
+				t.Errorf("newImportStatus() LastImportTime = %v", got.LastImportTime)
+			}
+			if (got.RetriesRemaining != nil) != tt.wantRetryInfo {
+				t.Fatalf("newImportStatus() RetriesRemaining = %v, want retry info %v", got.RetriesRemaining, tt.wantRetryInfo)
+			}
+			if tt.wantRetryInfo && (*got.RetriesRemaining != tt.wantRetries || *got.RetryTotal != tt.wantTotal) {
+				t.Errorf("newImportStatus() retries = %d/%d, want %d/%d",
+					*got.RetriesRemaining, *got.RetryTotal, tt.wantRetries, tt.wantTotal)
+			}
+			if got.Offline != tt.wantOffline {
+				t.Errorf("newImportStatus() Offline = %v, want %v", got.Offline, tt.wantOffline)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_syncImportStatus(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	if err := r.syncImportStatus(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	mc := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+		t.Fatal(err)
+	}
+	status := ImportStatus{}
+	if err := json.Unmarshal([]byte(mc.GetAnnotations()[importStatusAnnotation]), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Phase != ImportPhasePending {
+		t.Errorf("import status phase = %s, want %s", status.Phase, ImportPhasePending)
+	}
+}
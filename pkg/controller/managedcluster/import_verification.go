@@ -0,0 +1,105 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//importVerificationDelay returns the delay after a successful import before the klusterlet is checked
+//again on the managed cluster, 0 disables the verification
+func importVerificationDelay() time.Duration {
+	return getEnvDuration(importVerificationDelayEnvVarName, 0)
+}
+
+//importVerification is a verification due after a successful import, with the client of the import
+//as the auto-import-secret is consumed
+type importVerification struct {
+	client client.Client
+	due    time.Time
+}
+
+//importVerifications are the verifications scheduled per ManagedCluster
+type importVerifications struct {
+	mu      sync.Mutex
+	pending map[string]importVerification
+}
+
+var pendingImportVerifications = &importVerifications{
+	pending: map[string]importVerification{},
+}
+
+//schedule records a verification of the import of the cluster at due
+func (v *importVerifications) schedule(name string, c client.Client, due time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pending[name] = importVerification{client: c, due: due}
+}
+
+//take returns the client of the verification of the cluster if it is due, it is then removed, or the
+//delay before it is due. It returns false if no verification is scheduled.
+func (v *importVerifications) take(name string, now time.Time) (client.Client, time.Duration, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	verification, ok := v.pending[name]
+	if !ok {
+		return nil, 0, false
+	}
+	if wait := verification.due.Sub(now); wait > 0 {
+		return nil, wait, true
+	}
+	delete(v.pending, name)
+	return verification.client, 0, true
+}
+
+//forget drops the verification of the cluster, it is online or deleted
+func (v *importVerifications) forget(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.pending, name)
+}
+
+//verifyImport checks the Klusterlet is still on the managed cluster, the import is applied again if it
+//vanished. The verification is best-effort, it is skipped if the Klusterlet can't be read.
+func (r *ReconcileManagedCluster) verifyImport(
+	managedCluster *clusterv1.ManagedCluster,
+	managedClusterClient client.Client,
+) (reconcile.Result, error) {
+	log := clusterLogger(managedCluster.Name)
+	name, err := getKlusterletName(managedCluster)
+	if err != nil {
+		log.Info("Unable to verify the import", "error", fmt.Sprint(err))
+		return reconcile.Result{}, nil
+	}
+	klusterlet := &unstructured.Unstructured{}
+	klusterlet.SetAPIVersion("operator.open-cluster-management.io/v1")
+	klusterlet.SetKind("Klusterlet")
+	err = managedClusterClient.Get(context.TODO(), types.NamespacedName{Name: name}, klusterlet)
+	if err == nil {
+		log.Info("The klusterlet is still present after the import")
+		return reconcile.Result{}, nil
+	}
+	if !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		log.Info("Unable to verify the import", "error", fmt.Sprint(err))
+		return reconcile.Result{}, nil
+	}
+
+	log.Info("The klusterlet vanished after the import, importing again")
+	result, err := r.importClusterWithClient(managedCluster, nil, managedClusterClient)
+	if err != nil {
+		//setConditionImport returns the import error when the condition is set
+		return result, r.setConditionImport(managedCluster, classifyImportError(err), "")
+	}
+	return result, nil
+}
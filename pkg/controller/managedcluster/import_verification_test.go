@@ -0,0 +1,91 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	operatorv1 "github.com/open-cluster-management/api/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_importVerifications(t *testing.T) {
+	v := &importVerifications{pending: map[string]importVerification{}}
+	now := time.Now()
+	c := fake.NewFakeClientWithScheme(scheme.Scheme)
+
+	if _, _, ok := v.take("cluster", now); ok {
+		t.Fatal("expected no verification")
+	}
+	v.schedule("cluster", c, now.Add(time.Minute))
+	got, wait, ok := v.take("cluster", now)
+	if !ok || got != nil || wait != time.Minute {
+		t.Fatalf("take() = %v, %v, %v, want a verification due in 1m", got, wait, ok)
+	}
+	got, wait, ok = v.take("cluster", now.Add(time.Minute))
+	if !ok || got != c || wait != 0 {
+		t.Fatalf("take() = %v, %v, %v, want the due verification", got, wait, ok)
+	}
+	if _, _, ok := v.take("cluster", now.Add(time.Minute)); ok {
+		t.Error("expected the verification to be done once")
+	}
+
+	v.schedule("cluster", c, now)
+	v.forget("cluster")
+	if _, _, ok := v.take("cluster", now); ok {
+		t.Error("expected the verification to be forgotten")
+	}
+}
+
+func TestReconcileManagedCluster_verifyImport(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+	}
+	schemeManaged := runtime.NewScheme()
+	schemeManaged.AddKnownTypes(operatorv1.SchemeGroupVersion, &operatorv1.Klusterlet{})
+	klusterlet := &operatorv1.Klusterlet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: klusterletName,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		remoteScheme  *runtime.Scheme
+		remoteObjects []runtime.Object
+	}{
+		{
+			name:          "klusterlet present",
+			remoteScheme:  schemeManaged,
+			remoteObjects: []runtime.Object{klusterlet},
+		},
+		{
+			name:         "klusterlet not readable",
+			remoteScheme: runtime.NewScheme(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(scheme.Scheme),
+				scheme: scheme.Scheme,
+			}
+			remoteClient := fake.NewFakeClientWithScheme(tt.remoteScheme, tt.remoteObjects...)
+			got, err := r.verifyImport(managedCluster, remoteClient)
+			if err != nil {
+				t.Fatalf("verifyImport() error = %v", err)
+			}
+			if got != (reconcile.Result{}) {
+				t.Errorf("verifyImport() = %v, want no requeue", got)
+			}
+		})
+	}
+}
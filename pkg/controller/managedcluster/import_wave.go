@@ -0,0 +1,96 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//importWaveAnnotation is the wave of a staged fleet onboarding, a non-negative integer. The clusters of a
+//wave are imported once all the clusters of the lower waves are imported.
+const importWaveAnnotation = "import.open-cluster-management.io/import-wave"
+
+const reasonWaitingForImportWave = "WaitingForImportWave"
+
+const importWaveRequeueAfter = time.Minute
+
+//getImportWave returns the wave of the ManagedCluster and false if it is not part of a staged rollout
+func getImportWave(managedCluster *clusterv1.ManagedCluster) (int, bool) {
+	v, ok := managedCluster.GetAnnotations()[importWaveAnnotation]
+	if !ok {
+		return 0, false
+	}
+	wave, err := strconv.Atoi(v)
+	if err != nil || wave < 0 {
+		logSubsystemImport.V(4).Infof("Invalid value %q for the annotation %s of %s, it is ignored",
+			v, importWaveAnnotation, managedCluster.Name)
+		return 0, false
+	}
+	return wave, true
+}
+
+//isImportedForWave returns true if the ManagedCluster no longer holds back the next waves,
+//an online cluster is imported even if its import condition was never set
+func isImportedForWave(managedCluster *clusterv1.ManagedCluster) bool {
+	if meta.IsStatusConditionTrue(managedCluster.Status.Conditions, importConditionType()) {
+		return true
+	}
+	return !checkOffLine(managedCluster)
+}
+
+//activeImportWave returns the lowest wave having a selected ManagedCluster not imported yet and false
+//if all the waves are imported
+func activeImportWave(c client.Client, selectorMatches func(*clusterv1.ManagedCluster) bool) (int, bool, error) {
+	managedClusters := &clusterv1.ManagedClusterList{}
+	if err := c.List(context.TODO(), managedClusters); err != nil {
+		return 0, false, err
+	}
+	active, found := 0, false
+	for i := range managedClusters.Items {
+		mc := &managedClusters.Items[i]
+		if mc.DeletionTimestamp != nil || !selectorMatches(mc) || isImportedForWave(mc) {
+			continue
+		}
+		wave, ok := getImportWave(mc)
+		if !ok {
+			continue
+		}
+		if !found || wave < active {
+			active, found = wave, true
+		}
+	}
+	return active, found, nil
+}
+
+//waitForImportWave returns true if the ManagedCluster belongs to a wave after the active one,
+//its import is then held back and its import condition set
+func (r *ReconcileManagedCluster) waitForImportWave(managedCluster *clusterv1.ManagedCluster) (bool, error) {
+	wave, ok := getImportWave(managedCluster)
+	if !ok || isImportedForWave(managedCluster) {
+		return false, nil
+	}
+	active, found, err := activeImportWave(r.client, func(mc *clusterv1.ManagedCluster) bool {
+		return isManagedClusterSelected(r.selector, mc)
+	})
+	if err != nil {
+		return false, err
+	}
+	if !found || wave <= active {
+		return false, nil
+	}
+	return true, r.setCondition(managedCluster, metav1.Condition{
+		Type:   importConditionType(),
+		Status: metav1.ConditionFalse,
+		Reason: reasonWaitingForImportWave,
+		Message: fmt.Sprintf("The cluster is in the import wave %d, waiting for the wave %d to be imported",
+			wave, active),
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileManagedCluster_waitForImportWave(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{}, &clusterv1.ManagedClusterList{})
+
+	newManagedCluster := func(name, wave string, imported bool) *clusterv1.ManagedCluster {
+		mc := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		if wave != "" {
+			mc.Annotations = map[string]string{importWaveAnnotation: wave}
+		}
+		if imported {
+			mc.Status.Conditions = []metav1.Condition{
+				{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: metav1.ConditionTrue,
+				},
+			}
+		}
+		return mc
+	}
+
+	tests := []struct {
+		name        string
+		cluster     *clusterv1.ManagedCluster
+		others      []runtime.Object
+		wantWaiting bool
+	}{
+		{
+			name:        "no wave",
+			cluster:     newManagedCluster("cluster", "", false),
+			others:      []runtime.Object{newManagedCluster("first", "0", false)},
+			wantWaiting: false,
+		},
+		{
+			name:        "invalid wave",
+			cluster:     newManagedCluster("cluster", "second", false),
+			others:      []runtime.Object{newManagedCluster("first", "0", false)},
+			wantWaiting: false,
+		},
+		{
+			name:        "active wave",
+			cluster:     newManagedCluster("cluster", "0", false),
+			others:      []runtime.Object{newManagedCluster("second", "1", false)},
+			wantWaiting: false,
+		},
+		{
+			name:        "same wave as the active one",
+			cluster:     newManagedCluster("cluster", "1", false),
+			others:      []runtime.Object{newManagedCluster("first", "0", true), newManagedCluster("other", "1", false)},
+			wantWaiting: false,
+		},
+		{
+			name:        "lower wave not imported",
+			cluster:     newManagedCluster("cluster", "1", false),
+			others:      []runtime.Object{newManagedCluster("first", "0", false)},
+			wantWaiting: true,
+		},
+		{
+			name:        "lower wave imported",
+			cluster:     newManagedCluster("cluster", "1", false),
+			others:      []runtime.Object{newManagedCluster("first", "0", true)},
+			wantWaiting: false,
+		},
+		{
+			name:        "cluster already imported",
+			cluster:     newManagedCluster("cluster", "1", true),
+			others:      []runtime.Object{newManagedCluster("first", "0", false)},
+			wantWaiting: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := append([]runtime.Object{tt.cluster}, tt.others...)
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, objs...),
+				scheme: testscheme,
+			}
+			waiting, err := r.waitForImportWave(tt.cluster)
+			if err != nil {
+				t.Fatalf("waitForImportWave() error = %v", err)
+			}
+			if waiting != tt.wantWaiting {
+				t.Errorf("waitForImportWave() = %v, want %v", waiting, tt.wantWaiting)
+			}
+			mc := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: tt.cluster.Name}, mc); err != nil {
+				t.Fatal(err)
+			}
+			condition := meta.FindStatusCondition(mc.Status.Conditions, importConditionType())
+			gotReason := condition != nil && condition.Reason == reasonWaitingForImportWave
+			if gotReason != tt.wantWaiting {
+				t.Errorf("condition = %v, want waiting %v", condition, tt.wantWaiting)
+			}
+		})
+	}
+}
@@ -45,6 +45,26 @@ func Test_createKubeconfigData(t *testing.T) {
 		},
 	}
 
+	testInfraConfigPath := &ocinfrav1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Spec: ocinfrav1.InfrastructureSpec{},
+		Status: ocinfrav1.InfrastructureStatus{
+			APIServerURL: "https://my-dns-name.com:8443/k8s/",
+		},
+	}
+
+	testInfraConfigInvalid := &ocinfrav1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Spec: ocinfrav1.InfrastructureSpec{},
+		Status: ocinfrav1.InfrastructureStatus{
+			APIServerURL: "my-dns-name.com:6443",
+		},
+	}
+
 	testTokenSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-sa-token",
@@ -152,6 +172,28 @@ func Test_createKubeconfigData(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "custom port and path prefix",
+			args: args{
+				client: fake.NewFakeClientWithScheme(s, testInfraConfigPath, apiserverConfig, secretCorrect),
+				secret: testTokenSecret,
+			},
+			want: wantData{
+				serverURL:   "https://my-dns-name.com:8443/k8s",
+				useInsecure: false,
+				certData:    []byte("custom-cert-data"),
+				token:       "fake-token",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid server url",
+			args: args{
+				client: fake.NewFakeClientWithScheme(s, testInfraConfigInvalid),
+				secret: testTokenSecret,
+			},
+			wantErr: true,
+		},
 		{
 			name: "use default when cert not found",
 			args: args{
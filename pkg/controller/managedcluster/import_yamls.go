@@ -12,8 +12,6 @@ import (
 	"fmt"
 	"os"
 
-	"k8s.io/klog"
-
 	"net/url"
 
 	corev1 "k8s.io/api/core/v1"
@@ -24,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,29 +39,24 @@ const (
 
 func generateImportYAMLs(
 	client client.Client,
+	kubeClient kubernetes.Interface,
 	managedCluster *clusterv1.ManagedCluster,
 	excluded []string,
 ) (yamls []*unstructured.Unstructured, crds []*unstructured.Unstructured, err error) {
 
-	klog.V(4).Info("Create templateProcessor")
+	logSubsystemImport.V(4).Info("Create templateProcessor")
 	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	klog.V(4).Info("TemplateResources klusterlet/crds")
+	logSubsystemImport.V(4).Info("TemplateResources klusterlet/crds")
 	crds, err = tp.TemplateResourcesInPathUnstructured("klusterlet/crds", nil, true, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	bootStrapSecret, err := getBootstrapSecret(client, managedCluster)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	klog.V(4).Infof("createKubeconfigData for bootsrapSecret %s", bootStrapSecret.Name)
-	bootstrapKubeconfigData, err := createKubeconfigData(client, bootStrapSecret)
+	bootstrapKubeconfigData, err := getBootstrapKubeconfigData(client, kubeClient, managedCluster)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -93,6 +87,59 @@ func generateImportYAMLs(
 		return nil, nil, fmt.Errorf(envVarNotDefined, workImageEnvVarName)
 	}
 
+	regionRegistry, _, err := getRegionRegistry(client, managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	registrationOperatorImageName = overrideImageRegistry(registrationOperatorImageName, regionRegistry)
+	registrationImageName = overrideImageRegistry(registrationImageName, regionRegistry)
+	workImageName = overrideImageRegistry(workImageName, regionRegistry)
+
+	klusterletResources, err := getKlusterletResources(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klusterletArgs, err := getKlusterletArgs(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agentNamespace, err := getKlusterletNamespace(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klusterletReplicas, err := getKlusterletReplicas(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klusterletCRName, err := getKlusterletName(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klusterletDeployMode, err := getKlusterletDeployMode(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klusterletLogLevel, err := getKlusterletLogLevel(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klusterletClusterRoleName, err := getKlusterletClusterRoleName(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klusterletAdminAggregateClusterRoleName, err := getKlusterletAdminAggregateClusterRoleName(managedCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	config := struct {
 		KlusterletNamespace       string
 		ManagedClusterNamespace   string
@@ -104,9 +151,18 @@ func generateImportYAMLs(
 		RegistrationOperatorImage string
 		RegistrationImageName     string
 		WorkImageName             string
+		KlusterletResources       string
+		KlusterletArgs            []string
+		KlusterletReplicas        int
+		KlusterletName            string
+		KlusterletDeployMode      string
+		KlusterletLogLevel        string
+		KlusterletClusterRoleName string
+
+		KlusterletAdminAggregateClusterRoleName string
 	}{
 		ManagedClusterNamespace:   managedCluster.Name,
-		KlusterletNamespace:       klusterletNamespace,
+		KlusterletNamespace:       agentNamespace,
 		BootstrapKubeconfig:       base64.StdEncoding.EncodeToString(bootstrapKubeconfigData),
 		UseImagePullSecret:        useImagePullSecret,
 		ImagePullSecretName:       managedClusterImagePullSecretName,
@@ -115,6 +171,15 @@ func generateImportYAMLs(
 		RegistrationOperatorImage: registrationOperatorImageName,
 		RegistrationImageName:     registrationImageName,
 		WorkImageName:             workImageName,
+		KlusterletResources:       klusterletResources,
+		KlusterletArgs:            klusterletArgs,
+		KlusterletReplicas:        klusterletReplicas,
+		KlusterletName:            klusterletCRName,
+		KlusterletDeployMode:      klusterletDeployMode,
+		KlusterletLogLevel:        klusterletLogLevel,
+		KlusterletClusterRoleName: klusterletClusterRoleName,
+
+		KlusterletAdminAggregateClusterRoleName: klusterletAdminAggregateClusterRoleName,
 	}
 
 	tp, err = templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
@@ -165,7 +230,8 @@ func getValidCertificatesFromURL(serverURL string, rootCAs *x509.CertPool) ([]*x
 		log.Error(err, "failed to parse url: "+serverURL)
 		return nil, err
 	}
-	log.Info("getting certificate of " + u.Hostname() + ":" + u.Port())
+	address := kubeAPIServerDialAddress(u)
+	log.Info("getting certificate of " + address)
 	conf := &tls.Config{
 		// server should support tls1.2
 		MinVersion: tls.VersionTLS12,
@@ -175,7 +241,7 @@ func getValidCertificatesFromURL(serverURL string, rootCAs *x509.CertPool) ([]*x
 		conf.RootCAs = rootCAs
 	}
 
-	conn, err := tls.Dial("tcp", u.Hostname()+":"+u.Port(), conf)
+	conn, err := tls.Dial("tcp", address, conf)
 
 	if err != nil {
 		log.Error(err, "failed to dial "+serverURL)
@@ -208,9 +274,19 @@ func createKubeconfigData(client client.Client, bootStrapSecret *corev1.Secret)
 	if err != nil {
 		return nil, err
 	}
+	kubeAPIServer, err = validateKubeAPIServerURL(kubeAPIServer)
+	if err != nil {
+		return nil, err
+	}
 
-	var certData []byte
-	if u, err := url.Parse(kubeAPIServer); err == nil {
+	//The CA configured for the hub takes precedence, for a serving certificate not signed by the cluster CA
+	certData, err := getBootstrapCAFromSource(client)
+	if err != nil {
+		return nil, err
+	}
+	if len(certData) != 0 {
+		log.V(2).Info("Using the configured bootstrap CA")
+	} else if u, err := url.Parse(kubeAPIServer); err == nil {
 		apiServerCertSecretName, err := getKubeAPIServerSecretName(client, u.Hostname())
 		if err != nil {
 			return nil, err
@@ -249,9 +325,11 @@ func createKubeconfigData(client client.Client, bootStrapSecret *corev1.Secret)
 		}
 	}
 
+	clusterName := bootstrapKubeconfigClusterName()
+	contextName := bootstrapKubeconfigContextName()
 	bootstrapConfig := clientcmdapi.Config{
 		// Define a cluster stanza based on the bootstrap kubeconfig.
-		Clusters: map[string]*clientcmdapi.Cluster{"default-cluster": {
+		Clusters: map[string]*clientcmdapi.Cluster{clusterName: {
 			Server:                   kubeAPIServer,
 			InsecureSkipTLSVerify:    false,
 			CertificateAuthorityData: certData,
@@ -261,12 +339,12 @@ func createKubeconfigData(client client.Client, bootStrapSecret *corev1.Secret)
 			Token: string(saToken),
 		}},
 		// Define a context that connects the auth info and cluster, and set it as the default
-		Contexts: map[string]*clientcmdapi.Context{"default-context": {
-			Cluster:   "default-cluster",
+		Contexts: map[string]*clientcmdapi.Context{contextName: {
+			Cluster:   clusterName,
 			AuthInfo:  "default-auth",
 			Namespace: "default",
 		}},
-		CurrentContext: "default-context",
+		CurrentContext: contextName,
 	}
 
 	return runtime.Encode(clientcmdlatest.Codec, &bootstrapConfig)
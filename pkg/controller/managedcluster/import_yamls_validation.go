@@ -0,0 +1,46 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const reasonInvalidImportYAMLs = "InvalidImportYAMLs"
+
+var errInvalidImportYAMLs = errors.New("invalid generated import manifests")
+
+//validateImportYAMLs checks the crds and yamls rendered for the import secret are not empty and each
+//document is a manifest, a template rendered with a missing value fails here rather than on the klusterlet
+func validateImportYAMLs(crds []*unstructured.Unstructured, yamls []*unstructured.Unstructured) error {
+	for _, rendered := range []struct {
+		key  string
+		objs []*unstructured.Unstructured
+	}{{crdsYAMLKey, crds}, {importYAMLKey, yamls}} {
+		key, objs := rendered.key, rendered.objs
+		if len(objs) == 0 {
+			return fmt.Errorf("%w: %s is empty", errInvalidImportYAMLs, key)
+		}
+		docs, err := toYAMLDocuments(objs)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", errInvalidImportYAMLs, key, err)
+		}
+		for i, doc := range yamlDocumentSeparator.Split(string(docs), -1) {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+			if _, err := parseManifest(doc); err != nil {
+				return fmt.Errorf("%w: %s document %d: %v", errInvalidImportYAMLs, key, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func isInvalidImportYAMLs(err error) bool {
+	return errors.Is(err, errInvalidImportYAMLs)
+}
@@ -0,0 +1,73 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_validateImportYAMLs(t *testing.T) {
+	newObj := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if apiVersion != "" {
+			u.SetAPIVersion(apiVersion)
+		}
+		if kind != "" {
+			u.SetKind(kind)
+		}
+		if name != "" {
+			u.SetName(name)
+		}
+		return u
+	}
+	crd := newObj("apiextensions.k8s.io/v1", "CustomResourceDefinition", "klusterlets.operator.open-cluster-management.io")
+	deployment := newObj("apps/v1", "Deployment", "klusterlet")
+
+	tests := []struct {
+		name    string
+		crds    []*unstructured.Unstructured
+		yamls   []*unstructured.Unstructured
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			crds:  []*unstructured.Unstructured{crd},
+			yamls: []*unstructured.Unstructured{deployment},
+		},
+		{
+			name:    "empty crds",
+			yamls:   []*unstructured.Unstructured{deployment},
+			wantErr: true,
+		},
+		{
+			name:    "empty yamls",
+			crds:    []*unstructured.Unstructured{crd},
+			wantErr: true,
+		},
+		{
+			name:    "missing kind",
+			crds:    []*unstructured.Unstructured{crd},
+			yamls:   []*unstructured.Unstructured{deployment, newObj("v1", "", "klusterlet")},
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			crds:    []*unstructured.Unstructured{crd},
+			yamls:   []*unstructured.Unstructured{newObj("v1", "ServiceAccount", "")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImportYAMLs(tt.crds, tt.yamls)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateImportYAMLs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !isInvalidImportYAMLs(err) {
+				t.Errorf("expected an invalid import YAMLs error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+//autoImportSecretInsecureSkipTLSVerifyKey set to "true" in the auto-import-secret skips the verification
+//of the managed cluster certificate, for lab clusters with self-signed certificates
+const autoImportSecretInsecureSkipTLSVerifyKey = "insecure-skip-tls-verify"
+
+const (
+	ConditionInsecureSkipTLSVerify    string = "ImportInsecureSkipTLSVerify"
+	reasonInsecureSkipTLSVerifyUsed   string = "InsecureSkipTLSVerifyUsed"
+	reasonInsecureSkipTLSVerifyUnused string = "InsecureSkipTLSVerifyUnused"
+)
+
+//insecureSkipTLSVerifyRequested returns true if the auto-import-secret asks to skip the TLS verification
+func insecureSkipTLSVerifyRequested(autoImportSecret *corev1.Secret) (bool, error) {
+	v, ok := autoImportSecret.Data[autoImportSecretInsecureSkipTLSVerifyKey]
+	if !ok {
+		return false, nil
+	}
+	insecure, err := strconv.ParseBool(string(v))
+	if err != nil {
+		return false, newImportError(ErrInvalidSecret,
+			fmt.Errorf("invalid %s value %q", autoImportSecretInsecureSkipTLSVerifyKey, string(v)))
+	}
+	return insecure, nil
+}
+
+//checkInsecureSkipTLSVerify returns true if the TLS verification must be skipped for the import.
+//It fails if the auto-import-secret asks for it while the controller doesn't allow it, when it is used
+//a Warning event is recorded and the condition is raised. The condition is only patched when it changes.
+func (r *ReconcileManagedCluster) checkInsecureSkipTLSVerify(
+	managedCluster *clusterv1.ManagedCluster,
+	autoImportSecret *corev1.Secret,
+) (bool, error) {
+	insecure, err := insecureSkipTLSVerifyRequested(autoImportSecret)
+	if err != nil {
+		return false, err
+	}
+	current := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionInsecureSkipTLSVerify)
+	if !insecure {
+		if current != nil && current.Status == metav1.ConditionTrue {
+			return false, r.setCondition(managedCluster, metav1.Condition{
+				Type:    ConditionInsecureSkipTLSVerify,
+				Status:  metav1.ConditionFalse,
+				Reason:  reasonInsecureSkipTLSVerifyUnused,
+				Message: "The managed cluster certificate is verified",
+			})
+		}
+		return false, nil
+	}
+	if !getEnvBool(allowInsecureSkipTLSVerifyEnvVarName, false) {
+		return false, newImportError(ErrInvalidSecret,
+			fmt.Errorf("%s is set in the auto-import-secret but is not allowed by the controller, set %s to true to allow it",
+				autoImportSecretInsecureSkipTLSVerifyKey, allowInsecureSkipTLSVerifyEnvVarName))
+	}
+	message := "The managed cluster certificate is NOT verified during the import, " +
+		"do not use insecure-skip-tls-verify in production"
+	log.Info("WARNING: skipping the TLS verification", "managedcluster", managedCluster.Name)
+	r.recordEvent(managedCluster, corev1.EventTypeWarning, reasonInsecureSkipTLSVerifyUsed, message)
+	if current != nil && current.Status == metav1.ConditionTrue {
+		return true, nil
+	}
+	return true, r.setCondition(managedCluster, metav1.Condition{
+		Type:    ConditionInsecureSkipTLSVerify,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonInsecureSkipTLSVerifyUsed,
+		Message: message,
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileManagedCluster_checkInsecureSkipTLSVerify(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	tests := []struct {
+		name          string
+		value         string
+		allowed       string
+		want          bool
+		wantErr       bool
+		wantCondition bool
+		wantEvent     bool
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name:  "disabled",
+			value: "false",
+		},
+		{
+			name:    "invalid value",
+			value:   "sure",
+			allowed: "true",
+			wantErr: true,
+		},
+		{
+			name:    "not allowed by the controller",
+			value:   "true",
+			wantErr: true,
+		},
+		{
+			name:          "allowed",
+			value:         "true",
+			allowed:       "true",
+			want:          true,
+			wantCondition: true,
+			wantEvent:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(allowInsecureSkipTLSVerifyEnvVarName, tt.allowed)
+			defer os.Unsetenv(allowInsecureSkipTLSVerifyEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "labcluster",
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      autoImportSecretName,
+					Namespace: managedCluster.Name,
+				},
+				Data: map[string][]byte{},
+			}
+			if tt.value != "" {
+				secret.Data[autoImportSecretInsecureSkipTLSVerifyKey] = []byte(tt.value)
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &ReconcileManagedCluster{
+				client:   fake.NewFakeClientWithScheme(testscheme, managedCluster),
+				scheme:   testscheme,
+				recorder: recorder,
+			}
+			got, err := r.checkInsecureSkipTLSVerify(managedCluster, secret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkInsecureSkipTLSVerify() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSecret) {
+				t.Errorf("expected an invalid secret error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("checkInsecureSkipTLSVerify() = %v, want %v", got, tt.want)
+			}
+			if meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionInsecureSkipTLSVerify) != tt.wantCondition {
+				t.Errorf("expected condition %s true %v, got %v",
+					ConditionInsecureSkipTLSVerify, tt.wantCondition, managedCluster.Status.Conditions)
+			}
+			if (len(recorder.Events) != 0) != tt.wantEvent {
+				t.Errorf("expected a warning event %v, got %d events", tt.wantEvent, len(recorder.Events))
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+//klusterletArgsAnnotation defines extra args of the klusterlet operator container as a JSON array,
+//for example ["--disable-leader-election"]
+const klusterletArgsAnnotation = "import.open-cluster-management.io/klusterlet-args"
+
+//klusterletFeatureGatesAnnotation defines the feature gates of the klusterlet operator container,
+//for example "FeatureA=true,FeatureB=false"
+const klusterletFeatureGatesAnnotation = "import.open-cluster-management.io/klusterlet-feature-gates"
+
+//klusterletArgsEnvVarName and klusterletFeatureGatesEnvVarName are the controller defaults
+//when the annotations are not set
+const (
+	klusterletArgsEnvVarName         = "KLUSTERLET_ARGS"
+	klusterletFeatureGatesEnvVarName = "KLUSTERLET_FEATURE_GATES"
+)
+
+const reasonInvalidKlusterletArgs = "InvalidKlusterletArgs"
+
+var (
+	klusterletArgRegexp         = regexp.MustCompile(`^--[a-zA-Z0-9][a-zA-Z0-9-]*(=.*)?$`)
+	klusterletFeatureGateRegexp = regexp.MustCompile(`^[a-zA-Z0-9]+=(true|false)$`)
+)
+
+//getKlusterletArgs returns the validated extra args of the klusterlet operator container,
+//each arg is quoted so it can be inlined in the yaml templates
+func getKlusterletArgs(managedCluster *clusterv1.ManagedCluster) ([]string, error) {
+	args := []string{}
+	v, ok := managedCluster.GetAnnotations()[klusterletArgsAnnotation]
+	if !ok {
+		v = os.Getenv(klusterletArgsEnvVarName)
+	}
+	if v != "" {
+		if err := json.Unmarshal([]byte(v), &args); err != nil {
+			return nil, newConfigError(reasonInvalidKlusterletArgs, "invalid klusterlet args: %s", err.Error())
+		}
+	}
+	for _, arg := range args {
+		if !klusterletArgRegexp.MatchString(arg) {
+			return nil, newConfigError(reasonInvalidKlusterletArgs,
+				"invalid klusterlet args: %q is not a --flag or --flag=value", arg)
+		}
+		if strings.HasPrefix(arg, "--feature-gates") {
+			return nil, newConfigError(reasonInvalidKlusterletArgs,
+				"invalid klusterlet args: the feature gates must be set with the annotation %s",
+				klusterletFeatureGatesAnnotation)
+		}
+	}
+
+	gates, ok := managedCluster.GetAnnotations()[klusterletFeatureGatesAnnotation]
+	if !ok {
+		gates = os.Getenv(klusterletFeatureGatesEnvVarName)
+	}
+	if gates != "" {
+		for _, gate := range strings.Split(gates, ",") {
+			if !klusterletFeatureGateRegexp.MatchString(gate) {
+				return nil, newConfigError(reasonInvalidKlusterletArgs,
+					"invalid klusterlet args: %q is not a Feature=true|false feature gate", gate)
+			}
+		}
+		args = append(args, "--feature-gates="+gates)
+	}
+
+	level, err := getKlusterletLogLevel(managedCluster)
+	if err != nil {
+		return nil, err
+	}
+	args = withKlusterletLogLevel(args, level)
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		b, err := json.Marshal(arg)
+		if err != nil {
+			return nil, err
+		}
+		quoted[i] = string(b)
+	}
+	return quoted, nil
+}
@@ -0,0 +1,140 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/templateprocessor"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+func Test_getKlusterletArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		envArgs     string
+		envGates    string
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name: "not set",
+			want: []string{},
+		},
+		{
+			name:        "args from annotation",
+			annotations: map[string]string{klusterletArgsAnnotation: `["--disable-leader-election","--v=4"]`},
+			want:        []string{`"--disable-leader-election"`, `"--v=4"`},
+		},
+		{
+			name:    "args from controller default",
+			envArgs: `["--v=2"]`,
+			want:    []string{`"--v=2"`},
+		},
+		{
+			name:        "annotation takes precedence",
+			annotations: map[string]string{klusterletArgsAnnotation: `["--v=4"]`},
+			envArgs:     `["--v=2"]`,
+			want:        []string{`"--v=4"`},
+		},
+		{
+			name:        "feature gates",
+			annotations: map[string]string{klusterletFeatureGatesAnnotation: "FeatureA=true,FeatureB=false"},
+			want:        []string{`"--feature-gates=FeatureA=true,FeatureB=false"`},
+		},
+		{
+			name:     "args and feature gates",
+			envArgs:  `["--v=2"]`,
+			envGates: "FeatureA=true",
+			want:     []string{`"--v=2"`, `"--feature-gates=FeatureA=true"`},
+		},
+		{
+			name:        "not a json array",
+			annotations: map[string]string{klusterletArgsAnnotation: "--v=4"},
+			wantErr:     true,
+		},
+		{
+			name:        "not a flag",
+			annotations: map[string]string{klusterletArgsAnnotation: `["v=4"]`},
+			wantErr:     true,
+		},
+		{
+			name:        "multi-line value",
+			annotations: map[string]string{klusterletArgsAnnotation: `["--v=4\n        privileged: true"]`},
+			wantErr:     true,
+		},
+		{
+			name:        "feature gates in args",
+			annotations: map[string]string{klusterletArgsAnnotation: `["--feature-gates=FeatureA=true"]`},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid feature gate",
+			annotations: map[string]string{klusterletFeatureGatesAnnotation: "FeatureA=on"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(klusterletArgsEnvVarName, tt.envArgs)
+			os.Setenv(klusterletFeatureGatesEnvVarName, tt.envGates)
+			defer os.Unsetenv(klusterletArgsEnvVarName)
+			defer os.Unsetenv(klusterletFeatureGatesEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mycluster",
+					Annotations: tt.annotations,
+				},
+			}
+			got, err := getKlusterletArgs(managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getKlusterletArgs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && configErrorReason(err) != reasonInvalidKlusterletArgs {
+				t.Errorf("expected an invalid klusterlet args error, got %v", err)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getKlusterletArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_klusterletArgsTemplating(t *testing.T) {
+	config := struct {
+		KlusterletNamespace       string
+		RegistrationOperatorImage string
+		KlusterletResources       string
+		KlusterletArgs            []string
+		KlusterletReplicas        int
+	}{
+		KlusterletNamespace:       "open-cluster-management-agent",
+		RegistrationOperatorImage: "registration-operator:latest",
+		KlusterletArgs:            []string{`"--v=4"`, `"--feature-gates=FeatureA=true"`},
+	}
+	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := tp.TemplateResource("klusterlet/operator.yaml", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := yaml.Unmarshal(result, deployment); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/registration-operator", "klusterlet", "--v=4", "--feature-gates=FeatureA=true"}
+	if got := deployment.Spec.Template.Spec.Containers[0].Args; !reflect.DeepEqual(got, want) {
+		t.Errorf("klusterlet args = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+//klusterletDeployModeAnnotation selects the deploy mode of the klusterlet on the managed cluster,
+//Singleton runs the registration and work agents in a single pod for a lighter footprint
+const klusterletDeployModeAnnotation = "import.open-cluster-management.io/klusterlet-deploy-mode"
+
+const (
+	//klusterletDeployModeDefault deploys the registration and work agents separately
+	klusterletDeployModeDefault = "Default"
+	//klusterletDeployModeSingleton deploys the registration and work agents in a single pod
+	klusterletDeployModeSingleton = "Singleton"
+)
+
+const reasonInvalidKlusterletDeployMode = "InvalidKlusterletDeployMode"
+
+//getKlusterletDeployMode returns the deploy mode rendered in the Klusterlet CR, it is empty when the
+//annotation is not set or Default so the Klusterlet of the clusters already imported is unchanged
+func getKlusterletDeployMode(managedCluster *clusterv1.ManagedCluster) (string, error) {
+	mode, ok := managedCluster.GetAnnotations()[klusterletDeployModeAnnotation]
+	if !ok {
+		return "", nil
+	}
+	switch mode {
+	case klusterletDeployModeDefault:
+		return "", nil
+	case klusterletDeployModeSingleton:
+		return mode, nil
+	}
+	return "", newConfigError(reasonInvalidKlusterletDeployMode,
+		"invalid klusterlet deploy mode: %s must be %s or %s, got %q",
+		klusterletDeployModeAnnotation, klusterletDeployModeDefault, klusterletDeployModeSingleton, mode)
+}
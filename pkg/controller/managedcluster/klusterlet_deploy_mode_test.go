@@ -0,0 +1,127 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/templateprocessor"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+func Test_getKlusterletDeployMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "no annotation",
+			want: "",
+		},
+		{
+			name:        "default mode",
+			annotations: map[string]string{klusterletDeployModeAnnotation: "Default"},
+			want:        "",
+		},
+		{
+			name:        "singleton mode",
+			annotations: map[string]string{klusterletDeployModeAnnotation: "Singleton"},
+			want:        klusterletDeployModeSingleton,
+		},
+		{
+			name:        "unknown mode",
+			annotations: map[string]string{klusterletDeployModeAnnotation: "singleton"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster",
+					Annotations: tt.annotations,
+				},
+			}
+			got, err := getKlusterletDeployMode(managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getKlusterletDeployMode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && configErrorReason(err) != reasonInvalidKlusterletDeployMode {
+				t.Errorf("expected an invalid klusterlet deploy mode error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getKlusterletDeployMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_klusterletDeployModeTemplating(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployMode string
+		wantMode   string
+		wantFound  bool
+	}{
+		{
+			name:      "default mode",
+			wantFound: false,
+		},
+		{
+			name:       "singleton mode",
+			deployMode: klusterletDeployModeSingleton,
+			wantMode:   "Singleton",
+			wantFound:  true,
+		},
+	}
+	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := struct {
+				KlusterletNamespace     string
+				ManagedClusterNamespace string
+				RegistrationImageName   string
+				WorkImageName           string
+				UseImagePullSecret      bool
+				ImagePullSecretName     string
+				KlusterletName          string
+				KlusterletDeployMode    string
+				KlusterletLogLevel      string
+				KlusterletResources     string
+			}{
+				KlusterletNamespace:     "open-cluster-management-agent",
+				ManagedClusterNamespace: "cluster",
+				RegistrationImageName:   "registration:latest",
+				WorkImageName:           "work:latest",
+				KlusterletName:          klusterletName,
+				KlusterletDeployMode:    tt.deployMode,
+			}
+			result, err := tp.TemplateResource("klusterlet/klusterlet.yaml", config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			klusterlet := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(result, &klusterlet.Object); err != nil {
+				t.Fatal(err)
+			}
+			mode, found, err := unstructured.NestedString(klusterlet.Object, "spec", "deployOption", "mode")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.wantFound || mode != tt.wantMode {
+				t.Errorf("deployOption.mode = %q (found %v), want %q (found %v)", mode, found, tt.wantMode, tt.wantFound)
+			}
+		})
+	}
+}
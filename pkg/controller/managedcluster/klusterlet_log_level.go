@@ -0,0 +1,62 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+//klusterletLogLevelAnnotation defines the klog verbosity of the klusterlet operator container and of
+//the registration and work agents, for example "4" to troubleshoot the managed cluster side
+const klusterletLogLevelAnnotation = "import.open-cluster-management.io/klusterlet-log-level"
+
+//klusterletLogLevelEnvVarName is the controller default when the annotation is not set
+const klusterletLogLevelEnvVarName = "KLUSTERLET_LOG_LEVEL"
+
+//maxKlusterletLogLevel bounds the log level, the higher levels dump the requests and responses
+const maxKlusterletLogLevel = 10
+
+const reasonInvalidKlusterletLogLevel = "InvalidKlusterletLogLevel"
+
+//getKlusterletLogLevel returns the validated log level of the klusterlet containers, "" when neither the
+//annotation nor the environment is set, the containers keep their default level. The operator gets it with
+//--v and the agents with the logLevel of their configuration in the Klusterlet CR.
+func getKlusterletLogLevel(managedCluster *clusterv1.ManagedCluster) (string, error) {
+	v, ok := managedCluster.GetAnnotations()[klusterletLogLevelAnnotation]
+	if !ok {
+		v = os.Getenv(klusterletLogLevelEnvVarName)
+	}
+	if v == "" {
+		return "", nil
+	}
+	level, err := strconv.Atoi(v)
+	if err != nil {
+		return "", newConfigError(reasonInvalidKlusterletLogLevel,
+			"invalid klusterlet log level: %q is not an integer", v)
+	}
+	if level < 0 || level > maxKlusterletLogLevel {
+		return "", newConfigError(reasonInvalidKlusterletLogLevel,
+			"invalid klusterlet log level: %d is not between 0 and %d", level, maxKlusterletLogLevel)
+	}
+	return strconv.Itoa(level), nil
+}
+
+//withKlusterletLogLevel replaces the --v args by the log level, the log level takes precedence over
+//the verbosity set in the klusterlet args
+func withKlusterletLogLevel(args []string, level string) []string {
+	if level == "" {
+		return args
+	}
+	result := make([]string, 0, len(args)+1)
+	for _, arg := range args {
+		if arg == "--v" || strings.HasPrefix(arg, "--v=") {
+			continue
+		}
+		result = append(result, arg)
+	}
+	return append(result, "--v="+level)
+}
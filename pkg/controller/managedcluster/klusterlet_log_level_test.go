@@ -0,0 +1,172 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getKlusterletLogLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		env         string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "default",
+			want: "",
+		},
+		{
+			name: "from the environment",
+			env:  "2",
+			want: "2",
+		},
+		{
+			name:        "annotation overrides the environment",
+			annotations: map[string]string{klusterletLogLevelAnnotation: "4"},
+			env:         "2",
+			want:        "4",
+		},
+		{
+			name:        "zero",
+			annotations: map[string]string{klusterletLogLevelAnnotation: "0"},
+			want:        "0",
+		},
+		{
+			name:        "not an integer",
+			annotations: map[string]string{klusterletLogLevelAnnotation: "debug"},
+			wantErr:     true,
+		},
+		{
+			name:        "negative",
+			annotations: map[string]string{klusterletLogLevelAnnotation: "-1"},
+			wantErr:     true,
+		},
+		{
+			name:    "too high",
+			env:     "11",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(klusterletLogLevelEnvVarName, tt.env)
+			defer os.Unsetenv(klusterletLogLevelEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mycluster",
+					Annotations: tt.annotations,
+				},
+			}
+			got, err := getKlusterletLogLevel(managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getKlusterletLogLevel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && configErrorReason(err) != reasonInvalidKlusterletLogLevel {
+				t.Errorf("expected an invalid klusterlet log level error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getKlusterletLogLevel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getKlusterletArgs_logLevel(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+			Annotations: map[string]string{
+				klusterletArgsAnnotation:     `["--v=2","--disable-leader-election"]`,
+				klusterletLogLevelAnnotation: "6",
+			},
+		},
+	}
+	got, err := getKlusterletArgs(managedCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`"--disable-leader-election"`, `"--v=6"`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getKlusterletArgs() = %v, want %v", got, want)
+	}
+
+	managedCluster.Annotations[klusterletLogLevelAnnotation] = "verbose"
+	if _, err := getKlusterletArgs(managedCluster); configErrorReason(err) != reasonInvalidKlusterletLogLevel {
+		t.Errorf("expected an invalid klusterlet log level error, got %v", err)
+	}
+}
+
+//Test_generateImportYAMLs_klusterletLogLevel checks the log level applies to every klusterlet container,
+//the operator and the registration and work agents it deploys
+func Test_generateImportYAMLs_klusterletLogLevel(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster1",
+			Annotations: map[string]string{klusterletLogLevelAnnotation: "4"},
+		},
+	}
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, newImportObjects(t, managedCluster)...)
+	yamls, _, err := generateImportYAMLs(c, nil, managedCluster, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var operator, klusterlet *unstructured.Unstructured
+	for _, u := range yamls {
+		switch u.GetKind() {
+		case "Deployment":
+			operator = u
+		case "Klusterlet":
+			klusterlet = u
+		}
+	}
+	if operator == nil || klusterlet == nil {
+		t.Fatalf("expected the klusterlet operator and the Klusterlet CR to be rendered")
+	}
+
+	containers, _, err := unstructured.NestedSlice(operator.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) == 0 {
+		t.Fatalf("expected the klusterlet operator containers to be rendered")
+	}
+	for _, container := range containers {
+		args, _, err := unstructured.NestedStringSlice(container.(map[string]interface{}), "args")
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, arg := range args {
+			if arg == "--v=4" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected the container %v to log at the level 4, got the args %v",
+				container.(map[string]interface{})["name"], args)
+		}
+	}
+
+	for _, agent := range []string{"registrationConfiguration", "workConfiguration"} {
+		level, found, err := unstructured.NestedFieldNoCopy(klusterlet.Object, "spec", agent, "logLevel")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || fmt.Sprint(level) != "4" {
+			t.Errorf("expected the %s of the Klusterlet to set the log level 4, got %v (found %t)", agent, level, found)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+//klusterletName is the name of the Klusterlet CR applied on the managed cluster
+const klusterletName = "klusterlet"
+
+//klusterletNameAnnotation overrides the name of the Klusterlet CR applied on the managed cluster,
+//to match an existing naming convention
+const klusterletNameAnnotation = "import.open-cluster-management.io/klusterlet-name"
+
+const reasonInvalidKlusterletName = "InvalidKlusterletName"
+
+//getKlusterletName returns the name of the Klusterlet CR of the managed cluster, klusterlet with the hub
+//identifier when the annotation is not set
+func getKlusterletName(managedCluster *clusterv1.ManagedCluster) (string, error) {
+	name, ok := managedCluster.GetAnnotations()[klusterletNameAnnotation]
+	if !ok {
+		return withHubIdentifier(managedCluster, klusterletName)
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return "", newConfigError(reasonInvalidKlusterletName, "invalid klusterlet name: %s %q: %s",
+			klusterletNameAnnotation, name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
@@ -0,0 +1,101 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/templateprocessor"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+func Test_getKlusterletName(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "no annotation",
+			want: klusterletName,
+		},
+		{
+			name:        "custom name",
+			annotations: map[string]string{klusterletNameAnnotation: "klusterlet-hub1"},
+			want:        "klusterlet-hub1",
+		},
+		{
+			name:        "empty name",
+			annotations: map[string]string{klusterletNameAnnotation: ""},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid name",
+			annotations: map[string]string{klusterletNameAnnotation: "Klusterlet_1"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster",
+					Annotations: tt.annotations,
+				},
+			}
+			got, err := getKlusterletName(managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getKlusterletName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && configErrorReason(err) != reasonInvalidKlusterletName {
+				t.Errorf("expected an invalid klusterlet name error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getKlusterletName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_klusterletNameTemplating(t *testing.T) {
+	config := struct {
+		KlusterletNamespace     string
+		ManagedClusterNamespace string
+		RegistrationImageName   string
+		WorkImageName           string
+		UseImagePullSecret      bool
+		ImagePullSecretName     string
+		KlusterletName          string
+		KlusterletDeployMode    string
+		KlusterletLogLevel      string
+		KlusterletResources     string
+	}{
+		KlusterletNamespace:     "open-cluster-management-agent",
+		ManagedClusterNamespace: "cluster",
+		RegistrationImageName:   "registration:latest",
+		WorkImageName:           "work:latest",
+		KlusterletName:          "klusterlet-hub1",
+	}
+	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := tp.TemplateResource("klusterlet/klusterlet.yaml", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	klusterlet := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(result, &klusterlet.Object); err != nil {
+		t.Fatal(err)
+	}
+	if klusterlet.GetName() != "klusterlet-hub1" {
+		t.Errorf("expected the klusterlet name klusterlet-hub1, got %q", klusterlet.GetName())
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+//hubIdentifierAnnotation identifies the hub importing the cluster, the klusterlet is deployed in a
+//namespace derived from it and its cluster scoped resources are named after it so several hubs can
+//import the same cluster
+const hubIdentifierAnnotation = "import.open-cluster-management.io/hub-identifier"
+
+//hubIdentifierEnvVarName is the hub identifier used when the annotation is not set
+//and hubIdentifierPositionEnvVarName places it as a "suffix" (default) or a "prefix"
+//of the klusterlet namespace and of the names of the klusterlet cluster scoped resources
+const (
+	hubIdentifierEnvVarName         = "HUB_IDENTIFIER"
+	hubIdentifierPositionEnvVarName = "HUB_IDENTIFIER_POSITION"
+)
+
+const (
+	hubIdentifierPositionSuffix = "suffix"
+	hubIdentifierPositionPrefix = "prefix"
+)
+
+//klusterletClusterRoleName is the name of the ClusterRole and the ClusterRoleBinding of the klusterlet operator
+const klusterletClusterRoleName = "klusterlet"
+
+//klusterletAdminAggregateClusterRoleName is the name of the ClusterRole aggregating the klusterlet permissions
+//to the admin role
+const klusterletAdminAggregateClusterRoleName = "open-cluster-management:klusterlet-admin-aggregate-clusterrole"
+
+const reasonInvalidKlusterletNamespace = "InvalidKlusterletNamespace"
+
+//getHubIdentifier returns the identifier of the hub importing the cluster, empty when not set
+func getHubIdentifier(managedCluster *clusterv1.ManagedCluster) string {
+	hubIdentifier, ok := managedCluster.GetAnnotations()[hubIdentifierAnnotation]
+	if !ok {
+		hubIdentifier = os.Getenv(hubIdentifierEnvVarName)
+	}
+	return hubIdentifier
+}
+
+//withHubIdentifier returns the name with the hub identifier of the cluster at the configured position,
+//the name is unchanged when no hub identifier is set
+func withHubIdentifier(managedCluster *clusterv1.ManagedCluster, name string) (string, error) {
+	hubIdentifier := getHubIdentifier(managedCluster)
+	if hubIdentifier == "" {
+		return name, nil
+	}
+	switch position := strings.ToLower(os.Getenv(hubIdentifierPositionEnvVarName)); position {
+	case "", hubIdentifierPositionSuffix:
+		return name + "-" + hubIdentifier, nil
+	case hubIdentifierPositionPrefix:
+		return hubIdentifier + "-" + name, nil
+	default:
+		return "", newConfigError(reasonInvalidKlusterletNamespace,
+			"invalid klusterlet namespace: %s must be %s or %s, got %q",
+			hubIdentifierPositionEnvVarName, hubIdentifierPositionSuffix, hubIdentifierPositionPrefix, position)
+	}
+}
+
+//getKlusterletNamespace returns the namespace of the klusterlet on the managed cluster,
+//open-cluster-management-agent when no hub identifier is set
+func getKlusterletNamespace(managedCluster *clusterv1.ManagedCluster) (string, error) {
+	namespace, err := withHubIdentifier(managedCluster, klusterletNamespace)
+	if err != nil || namespace == klusterletNamespace {
+		return namespace, err
+	}
+	hubIdentifier := getHubIdentifier(managedCluster)
+	if errs := validation.IsDNS1123Label(namespace); len(errs) != 0 {
+		return "", newConfigError(reasonInvalidKlusterletNamespace,
+			"invalid klusterlet namespace: %q derived from the hub identifier %q: %s",
+			namespace, hubIdentifier, strings.Join(errs, ", "))
+	}
+	return namespace, nil
+}
+
+//getKlusterletClusterRoleName returns the name of the ClusterRole and the ClusterRoleBinding of the klusterlet
+//operator, klusterlet when no hub identifier is set
+func getKlusterletClusterRoleName(managedCluster *clusterv1.ManagedCluster) (string, error) {
+	return withHubIdentifier(managedCluster, klusterletClusterRoleName)
+}
+
+//getKlusterletAdminAggregateClusterRoleName returns the name of the ClusterRole aggregating the klusterlet
+//permissions to the admin role, as each hub deletes it with its klusterlet
+func getKlusterletAdminAggregateClusterRoleName(managedCluster *clusterv1.ManagedCluster) (string, error) {
+	return withHubIdentifier(managedCluster, klusterletAdminAggregateClusterRoleName)
+}
@@ -0,0 +1,156 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getKlusterletNamespace(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		envID       string
+		envPosition string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "no hub identifier",
+			want: klusterletNamespace,
+		},
+		{
+			name:        "annotation as suffix",
+			annotations: map[string]string{hubIdentifierAnnotation: "hub1"},
+			want:        "open-cluster-management-agent-hub1",
+		},
+		{
+			name:        "annotation as prefix",
+			annotations: map[string]string{hubIdentifierAnnotation: "hub1"},
+			envPosition: "prefix",
+			want:        "hub1-open-cluster-management-agent",
+		},
+		{
+			name:  "hub identifier from the environment",
+			envID: "hub2",
+			want:  "open-cluster-management-agent-hub2",
+		},
+		{
+			name:        "annotation overrides the environment",
+			annotations: map[string]string{hubIdentifierAnnotation: "hub1"},
+			envID:       "hub2",
+			want:        "open-cluster-management-agent-hub1",
+		},
+		{
+			name:        "empty annotation disables the environment",
+			annotations: map[string]string{hubIdentifierAnnotation: ""},
+			envID:       "hub2",
+			want:        klusterletNamespace,
+		},
+		{
+			name:        "invalid hub identifier",
+			annotations: map[string]string{hubIdentifierAnnotation: "Hub_1"},
+			wantErr:     true,
+		},
+		{
+			name:        "namespace too long",
+			annotations: map[string]string{hubIdentifierAnnotation: "a-very-long-hub-identifier-which-does-not-fit"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid position",
+			annotations: map[string]string{hubIdentifierAnnotation: "hub1"},
+			envPosition: "middle",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(hubIdentifierEnvVarName, tt.envID)
+			os.Setenv(hubIdentifierPositionEnvVarName, tt.envPosition)
+			defer os.Unsetenv(hubIdentifierEnvVarName)
+			defer os.Unsetenv(hubIdentifierPositionEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster1",
+					Annotations: tt.annotations,
+				},
+			}
+			got, err := getKlusterletNamespace(managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getKlusterletNamespace() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && configErrorReason(err) != reasonInvalidKlusterletNamespace {
+				t.Errorf("expected an invalid klusterlet namespace error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getKlusterletNamespace() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_generateImportYAMLs_severalHubs(t *testing.T) {
+	newManagedCluster := func(hubIdentifier string) *clusterv1.ManagedCluster {
+		managedCluster := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1",
+			},
+		}
+		if hubIdentifier != "" {
+			managedCluster.Annotations = map[string]string{hubIdentifierAnnotation: hubIdentifier}
+		}
+		return managedCluster
+	}
+	c := fake.NewFakeClientWithScheme(scheme.Scheme, newImportObjects(t, newManagedCluster(""))...)
+	renderedNames := func(hubIdentifier string) map[string]bool {
+		_, yamls, err := generateImportYAMLs(c, nil, newManagedCluster(hubIdentifier), []string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make(map[string]bool)
+		for _, u := range yamls {
+			names[manifestID(u)] = true
+		}
+		return names
+	}
+
+	//Without hub identifier the names are unchanged
+	defaults := renderedNames("")
+	for _, id := range []string{
+		"ClusterRole klusterlet",
+		"ClusterRoleBinding klusterlet",
+		"ClusterRole open-cluster-management:klusterlet-admin-aggregate-clusterrole",
+		"Klusterlet klusterlet",
+	} {
+		if !defaults[id] {
+			t.Errorf("expected %s to be rendered, got %v", id, defaults)
+		}
+	}
+
+	//The klusterlets of two hubs don't share any resource
+	hub1 := renderedNames("hub1")
+	hub2 := renderedNames("hub2")
+	for id := range hub2 {
+		if hub1[id] {
+			t.Errorf("%s is rendered for both hubs", id)
+		}
+	}
+	for _, id := range []string{
+		"ClusterRole klusterlet-hub1",
+		"ClusterRoleBinding klusterlet-hub1",
+		"ClusterRole open-cluster-management:klusterlet-admin-aggregate-clusterrole-hub1",
+		"Klusterlet klusterlet-hub1",
+	} {
+		if !hub1[id] {
+			t.Errorf("expected %s to be rendered, got %v", id, hub1)
+		}
+	}
+}
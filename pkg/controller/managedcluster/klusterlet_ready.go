@@ -0,0 +1,127 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//klusterletOperatorName is the name of the klusterlet operator deployment on the managed cluster
+const klusterletOperatorName = "klusterlet"
+
+//klusterletReadyPollInterval is the delay between the checks of the klusterlet operator deployment
+const klusterletReadyPollInterval = 5 * time.Second
+
+//defaultKlusterletReadyTimeout is the maximum time an auto-import waits for the klusterlet operator
+const defaultKlusterletReadyTimeout = time.Minute
+
+//klusterletNotReadyRequeueAfter is the delay before the next attempt when the klusterlet is not ready
+const klusterletNotReadyRequeueAfter = 30 * time.Second
+
+const reasonKlusterletNotReady = "KlusterletNotReady"
+
+const reasonWaitingForKlusterletReady = "WaitingForKlusterletReady"
+
+var errKlusterletNotReady = errors.New("klusterlet not ready")
+
+func klusterletReadyTimeout() time.Duration {
+	return getEnvDuration(klusterletReadyTimeoutEnvVarName, defaultKlusterletReadyTimeout)
+}
+
+//isDeploymentAvailable returns true if the deployment reports the Available condition
+func isDeploymentAvailable(deployment *appsv1.Deployment) bool {
+	for _, c := range deployment.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+//klusterletReadinessChecks are the deadlines of the klusterlet readiness checks per ManagedCluster, the
+//deployment is checked once per reconcile until it is available or the deadline is exceeded
+type klusterletReadinessChecks struct {
+	mu        sync.Mutex
+	deadlines map[string]time.Time
+}
+
+var pendingKlusterletReadiness = &klusterletReadinessChecks{
+	deadlines: map[string]time.Time{},
+}
+
+//start returns the deadline of the check of the cluster, it is set to now+timeout by the first check
+func (c *klusterletReadinessChecks) start(name string, now time.Time, timeout time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deadline, ok := c.deadlines[name]
+	if !ok {
+		deadline = now.Add(timeout)
+		c.deadlines[name] = deadline
+	}
+	return deadline
+}
+
+//isPending returns true if the klusterlet of the cluster is applied and its readiness is being checked
+func (c *klusterletReadinessChecks) isPending(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.deadlines[name]
+	return ok
+}
+
+//forget drops the check of the cluster, the klusterlet is ready, the deadline is exceeded or the cluster
+//is deleted
+func (c *klusterletReadinessChecks) forget(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.deadlines, name)
+}
+
+//checkKlusterletReady returns true if the klusterlet operator deployment is available, false while it's
+//not and the deadline is not exceeded. It fails with errKlusterletNotReady once the deadline is exceeded.
+func checkKlusterletReady(c client.Client, namespace string, timeout time.Duration, deadline, now time.Time) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: klusterletOperatorName, Namespace: namespace}, deployment)
+	if err == nil && isDeploymentAvailable(deployment) {
+		return true, nil
+	}
+	//the deployment may not be visible yet or the cluster briefly unreachable, check again
+	if now.Before(deadline) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: the deployment %s/%s is not available after %s: %s",
+			errKlusterletNotReady, namespace, klusterletOperatorName, timeout.String(), err.Error())
+	}
+	return false, fmt.Errorf("%w: the deployment %s/%s is not available after %s",
+		errKlusterletNotReady, namespace, klusterletOperatorName, timeout.String())
+}
+
+//setConditionWaitingForKlusterletReady reports the auto-import waits for the klusterlet operator
+func (r *ReconcileManagedCluster) setConditionWaitingForKlusterletReady(
+	managedCluster *clusterv1.ManagedCluster,
+	namespace string,
+) error {
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:   importConditionType(),
+		Status: metav1.ConditionFalse,
+		Reason: reasonWaitingForKlusterletReady,
+		Message: fmt.Sprintf("Waiting for the deployment %s/%s of the managed cluster to be available",
+			namespace, klusterletOperatorName),
+	})
+}
+
+func isKlusterletNotReady(err error) bool {
+	return errors.Is(err, errKlusterletNotReady)
+}
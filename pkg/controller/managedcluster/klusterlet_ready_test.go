@@ -0,0 +1,266 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	operatorv1 "github.com/open-cluster-management/api/operator/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newKlusterletDeployment(namespace string, available corev1.ConditionStatus) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      klusterletOperatorName,
+			Namespace: namespace,
+		},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{
+					Type:   appsv1.DeploymentAvailable,
+					Status: available,
+				},
+			},
+		},
+	}
+}
+
+func Test_checkKlusterletReady(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		objs         []runtime.Object
+		deadline     time.Time
+		want         bool
+		wantErr      bool
+		wantNotReady bool
+	}{
+		{
+			name:     "available",
+			objs:     []runtime.Object{newKlusterletDeployment(klusterletNamespace, corev1.ConditionTrue)},
+			deadline: now.Add(time.Minute),
+			want:     true,
+		},
+		{
+			name:     "available after the deadline",
+			objs:     []runtime.Object{newKlusterletDeployment(klusterletNamespace, corev1.ConditionTrue)},
+			deadline: now.Add(-time.Second),
+			want:     true,
+		},
+		{
+			name:     "not available before the deadline",
+			objs:     []runtime.Object{newKlusterletDeployment(klusterletNamespace, corev1.ConditionFalse)},
+			deadline: now.Add(time.Minute),
+			want:     false,
+		},
+		{
+			name:         "not available after the deadline",
+			objs:         []runtime.Object{newKlusterletDeployment(klusterletNamespace, corev1.ConditionFalse)},
+			deadline:     now.Add(-time.Second),
+			wantErr:      true,
+			wantNotReady: true,
+		},
+		{
+			name:     "not found before the deadline",
+			objs:     []runtime.Object{},
+			deadline: now.Add(time.Minute),
+			want:     false,
+		},
+		{
+			name:         "not found after the deadline",
+			objs:         []runtime.Object{},
+			deadline:     now.Add(-time.Second),
+			wantErr:      true,
+			wantNotReady: true,
+		},
+		{
+			name:         "other namespace",
+			objs:         []runtime.Object{newKlusterletDeployment("other", corev1.ConditionTrue)},
+			deadline:     now.Add(-time.Second),
+			wantErr:      true,
+			wantNotReady: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(scheme.Scheme, tt.objs...)
+			got, err := checkKlusterletReady(c, klusterletNamespace, time.Minute, tt.deadline, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkKlusterletReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("checkKlusterletReady() = %v, want %v", got, tt.want)
+			}
+			if isKlusterletNotReady(err) != tt.wantNotReady {
+				t.Errorf("isKlusterletNotReady() = %v, want %v", isKlusterletNotReady(err), tt.wantNotReady)
+			}
+			if tt.wantNotReady && importErrorReason(err) != reasonKlusterletNotReady {
+				t.Errorf("importErrorReason() = %s, want %s", importErrorReason(err), reasonKlusterletNotReady)
+			}
+		})
+	}
+}
+
+func Test_klusterletReadinessChecks(t *testing.T) {
+	checks := &klusterletReadinessChecks{deadlines: map[string]time.Time{}}
+	now := time.Now()
+	if checks.isPending("cluster1") {
+		t.Error("expected no pending check")
+	}
+	deadline := checks.start("cluster1", now, time.Minute)
+	if !deadline.Equal(now.Add(time.Minute)) {
+		t.Errorf("start() = %s, want %s", deadline, now.Add(time.Minute))
+	}
+	if !checks.isPending("cluster1") {
+		t.Error("expected a pending check")
+	}
+	//The next checks keep the deadline of the first one
+	if got := checks.start("cluster1", now.Add(30*time.Second), time.Minute); !got.Equal(deadline) {
+		t.Errorf("start() = %s, want %s", got, deadline)
+	}
+	if checks.isPending("cluster2") {
+		t.Error("expected no pending check for another cluster")
+	}
+	checks.forget("cluster1")
+	if checks.isPending("cluster1") {
+		t.Error("expected the check to be forgotten")
+	}
+	if got := checks.start("cluster1", now.Add(time.Hour), time.Minute); !got.Equal(now.Add(time.Hour + time.Minute)) {
+		t.Errorf("start() = %s, want a new deadline", got)
+	}
+}
+
+func TestReconcileManagedCluster_importClusterWithClient_klusterletNotReady(t *testing.T) {
+	os.Setenv(klusterletReadyTimeoutEnvVarName, "1m")
+	defer os.Unsetenv(klusterletReadyTimeoutEnvVarName)
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(operatorv1.SchemeGroupVersion, &operatorv1.Klusterlet{})
+
+	mc := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "notready",
+		},
+	}
+	defer pendingKlusterletReadiness.forget(mc.Name)
+	autoImportSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      autoImportSecretName,
+			Namespace: mc.Name,
+		},
+		Data: map[string][]byte{autoImportRetryName: []byte("5")},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, append(newImportObjects(t, mc), autoImportSecret)...),
+		scheme: testscheme,
+	}
+	managedClusterClient := fake.NewFakeClientWithScheme(testscheme)
+	secretNsN := types.NamespacedName{Name: autoImportSecretName, Namespace: mc.Name}
+
+	//The klusterlet operator is not available yet, the reconcile doesn't wait for it
+	got, err := r.importClusterWithClient(mc, autoImportSecret, managedClusterClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := reconcile.Result{Requeue: true, RequeueAfter: klusterletReadyPollInterval}
+	if got != want {
+		t.Errorf("importClusterWithClient() = %v, want %v", got, want)
+	}
+	if !pendingKlusterletReadiness.isPending(mc.Name) {
+		t.Error("expected the readiness check to be pending")
+	}
+	if err := r.client.Get(context.TODO(), secretNsN, &corev1.Secret{}); err != nil {
+		t.Errorf("expected the auto-import-secret to be kept until the klusterlet is ready, got %v", err)
+	}
+
+	//The klusterlet operator is available on the next reconcile
+	deployment := &appsv1.Deployment{}
+	if err := managedClusterClient.Get(context.TODO(),
+		types.NamespacedName{Name: klusterletOperatorName, Namespace: klusterletNamespace}, deployment); err != nil {
+		t.Fatal(err)
+	}
+	deployment.Status = newKlusterletDeployment(klusterletNamespace, corev1.ConditionTrue).Status
+	if err := managedClusterClient.Update(context.TODO(), deployment); err != nil {
+		t.Fatal(err)
+	}
+	got, err = r.importClusterWithClient(mc, autoImportSecret, managedClusterClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (reconcile.Result{}) {
+		t.Errorf("importClusterWithClient() = %v, want no requeue", got)
+	}
+	if pendingKlusterletReadiness.isPending(mc.Name) {
+		t.Error("expected the readiness check to be done")
+	}
+	if err := r.client.Get(context.TODO(), secretNsN, &corev1.Secret{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the auto-import-secret to be consumed, got %v", err)
+	}
+}
+
+func TestReconcileManagedCluster_Reconcile_forgetsKlusterletReadiness(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(hivev1.SchemeGroupVersion, &hivev1.ClusterDeployment{})
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	tests := []struct {
+		name      string
+		available metav1.ConditionStatus
+	}{
+		{
+			//the cluster joined on its own while the readiness of the klusterlet was checked
+			name:      "cluster online",
+			available: metav1.ConditionTrue,
+		},
+		{
+			//the auto-import-secret was deleted while the readiness of the klusterlet was checked
+			name:      "auto-import-secret deleted",
+			available: metav1.ConditionUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: managedClusterNameReconcile,
+				},
+				Status: clusterv1.ManagedClusterStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:   clusterv1.ManagedClusterConditionAvailable,
+							Status: tt.available,
+						},
+					},
+				},
+			}
+			defer pendingKlusterletReadiness.forget(mc.Name)
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, newImportObjects(t, mc)...),
+				scheme: testscheme,
+			}
+			pendingKlusterletReadiness.start(mc.Name, time.Now(), time.Minute)
+			if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: mc.Name}}); err != nil {
+				t.Fatal(err)
+			}
+			if pendingKlusterletReadiness.isPending(mc.Name) {
+				t.Error("expected the readiness check to be forgotten")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"strconv"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+//klusterletReplicasAnnotation defines the number of replicas of the klusterlet operator deployment,
+//for example "3" for HA on large managed clusters
+const klusterletReplicasAnnotation = "import.open-cluster-management.io/klusterlet-replicas"
+
+//klusterletReplicasEnvVarName is the controller default when the annotation is not set
+const klusterletReplicasEnvVarName = "KLUSTERLET_REPLICAS"
+
+//defaultKlusterletReplicas is the number of replicas when neither the annotation nor the environment is set
+const defaultKlusterletReplicas = 1
+
+//maxKlusterletReplicas bounds the replicas, the klusterlet operator runs with leader election
+const maxKlusterletReplicas = 5
+
+const reasonInvalidKlusterletReplicas = "InvalidKlusterletReplicas"
+
+//getKlusterletReplicas returns the validated number of replicas of the klusterlet operator deployment
+func getKlusterletReplicas(managedCluster *clusterv1.ManagedCluster) (int, error) {
+	v, ok := managedCluster.GetAnnotations()[klusterletReplicasAnnotation]
+	if !ok {
+		v = os.Getenv(klusterletReplicasEnvVarName)
+	}
+	if v == "" {
+		return defaultKlusterletReplicas, nil
+	}
+	replicas, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, newConfigError(reasonInvalidKlusterletReplicas,
+			"invalid klusterlet replicas: %q is not an integer", v)
+	}
+	if replicas < 1 || replicas > maxKlusterletReplicas {
+		return 0, newConfigError(reasonInvalidKlusterletReplicas,
+			"invalid klusterlet replicas: %d is not between 1 and %d", replicas, maxKlusterletReplicas)
+	}
+	return replicas, nil
+}
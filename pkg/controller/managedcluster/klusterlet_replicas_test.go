@@ -0,0 +1,128 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/templateprocessor"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+func Test_getKlusterletReplicas(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		env         string
+		want        int
+		wantErr     bool
+	}{
+		{
+			name: "default",
+			want: defaultKlusterletReplicas,
+		},
+		{
+			name: "from the environment",
+			env:  "2",
+			want: 2,
+		},
+		{
+			name:        "annotation overrides the environment",
+			annotations: map[string]string{klusterletReplicasAnnotation: "3"},
+			env:         "2",
+			want:        3,
+		},
+		{
+			name:        "not an integer",
+			annotations: map[string]string{klusterletReplicasAnnotation: "three"},
+			wantErr:     true,
+		},
+		{
+			name:        "zero",
+			annotations: map[string]string{klusterletReplicasAnnotation: "0"},
+			wantErr:     true,
+		},
+		{
+			name:    "too many",
+			env:     "6",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(klusterletReplicasEnvVarName, tt.env)
+			defer os.Unsetenv(klusterletReplicasEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mycluster",
+					Annotations: tt.annotations,
+				},
+			}
+			got, err := getKlusterletReplicas(managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getKlusterletReplicas() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && configErrorReason(err) != reasonInvalidKlusterletReplicas {
+				t.Errorf("expected an invalid klusterlet replicas error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getKlusterletReplicas() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_klusterletReplicasTemplating(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int
+		want     int32
+	}{
+		{
+			name: "not set",
+			want: 1,
+		},
+		{
+			name:     "set",
+			replicas: 3,
+			want:     3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := struct {
+				KlusterletNamespace       string
+				RegistrationOperatorImage string
+				KlusterletResources       string
+				KlusterletArgs            []string
+				KlusterletReplicas        int
+			}{
+				KlusterletNamespace:       "open-cluster-management-agent",
+				RegistrationOperatorImage: "registration-operator:latest",
+				KlusterletReplicas:        tt.replicas,
+			}
+			tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := tp.TemplateResource("klusterlet/operator.yaml", config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			deployment := &appsv1.Deployment{}
+			if err := yaml.Unmarshal(result, deployment); err != nil {
+				t.Fatal(err)
+			}
+			if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != tt.want {
+				t.Errorf("replicas = %v, want %d", deployment.Spec.Replicas, tt.want)
+			}
+		})
+	}
+}
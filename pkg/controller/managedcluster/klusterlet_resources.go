@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"encoding/json"
+	"os"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+//klusterletResourcesAnnotation defines the resource requirements of the klusterlet containers as JSON,
+//for example {"requests":{"cpu":"50m","memory":"64Mi"},"limits":{"memory":"256Mi"}}. They are set on the
+//klusterlet operator container and on the Klusterlet CR for the registration and work agents.
+const klusterletResourcesAnnotation = "import.open-cluster-management.io/klusterlet-resources"
+
+//klusterletResourcesEnvVarName is the controller default when the annotation is not set
+const klusterletResourcesEnvVarName = "KLUSTERLET_RESOURCES"
+
+const reasonInvalidKlusterletResources = "InvalidKlusterletResources"
+
+//getKlusterletResources returns the validated resource requirements of the klusterlet containers
+//as a JSON flow which can be inlined in the yaml templates, an empty string means no requirements
+func getKlusterletResources(managedCluster *clusterv1.ManagedCluster) (string, error) {
+	v, ok := managedCluster.GetAnnotations()[klusterletResourcesAnnotation]
+	if !ok {
+		v = os.Getenv(klusterletResourcesEnvVarName)
+	}
+	if v == "" {
+		return "", nil
+	}
+	resources := &corev1.ResourceRequirements{}
+	if err := json.Unmarshal([]byte(v), resources); err != nil {
+		return "", newConfigError(reasonInvalidKlusterletResources, "invalid klusterlet resources: %s", err.Error())
+	}
+	for name, request := range resources.Requests {
+		if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return "", newConfigError(reasonInvalidKlusterletResources,
+				"invalid klusterlet resources: %s request %s is greater than its limit %s",
+				name, request.String(), limit.String())
+		}
+	}
+	b, err := json.Marshal(resources)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,175 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/templateprocessor"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+func Test_getKlusterletResources(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation *string
+		env        string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name: "not set",
+			want: "",
+		},
+		{
+			name:       "from annotation",
+			annotation: stringPtr(`{"requests":{"cpu":"50m","memory":"64Mi"},"limits":{"memory":"256Mi"}}`),
+			want:       `{"limits":{"memory":"256Mi"},"requests":{"cpu":"50m","memory":"64Mi"}}`,
+		},
+		{
+			name: "from controller default",
+			env:  `{"requests":{"cpu":"100m"}}`,
+			want: `{"requests":{"cpu":"100m"}}`,
+		},
+		{
+			name:       "annotation takes precedence",
+			annotation: stringPtr(`{"requests":{"cpu":"50m"}}`),
+			env:        `{"requests":{"cpu":"100m"}}`,
+			want:       `{"requests":{"cpu":"50m"}}`,
+		},
+		{
+			name:       "invalid quantity",
+			annotation: stringPtr(`{"requests":{"cpu":"a lot"}}`),
+			wantErr:    true,
+		},
+		{
+			name:       "request greater than limit",
+			annotation: stringPtr(`{"requests":{"memory":"1Gi"},"limits":{"memory":"256Mi"}}`),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(klusterletResourcesEnvVarName, tt.env)
+			defer os.Unsetenv(klusterletResourcesEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mycluster",
+				},
+			}
+			if tt.annotation != nil {
+				managedCluster.SetAnnotations(map[string]string{klusterletResourcesAnnotation: *tt.annotation})
+			}
+			got, err := getKlusterletResources(managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getKlusterletResources() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && configErrorReason(err) != reasonInvalidKlusterletResources {
+				t.Errorf("expected an invalid klusterlet resources error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getKlusterletResources() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_klusterletResourcesTemplating(t *testing.T) {
+	config := struct {
+		KlusterletNamespace       string
+		RegistrationOperatorImage string
+		KlusterletResources       string
+		KlusterletArgs            []string
+		KlusterletReplicas        int
+	}{
+		KlusterletNamespace:       "open-cluster-management-agent",
+		RegistrationOperatorImage: "registration-operator:latest",
+		KlusterletResources:       `{"limits":{"memory":"256Mi"},"requests":{"cpu":"50m"}}`,
+	}
+	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := tp.TemplateResource("klusterlet/operator.yaml", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := yaml.Unmarshal(result, deployment); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		if !c.Resources.Limits[corev1.ResourceMemory].Equal(resource.MustParse("256Mi")) ||
+			!c.Resources.Requests[corev1.ResourceCPU].Equal(resource.MustParse("50m")) {
+			t.Errorf("container %s resources = %v", c.Name, c.Resources)
+		}
+	}
+}
+
+func Test_klusterletResourcesTemplating_agents(t *testing.T) {
+	config := struct {
+		KlusterletNamespace     string
+		ManagedClusterNamespace string
+		RegistrationImageName   string
+		WorkImageName           string
+		UseImagePullSecret      bool
+		ImagePullSecretName     string
+		KlusterletName          string
+		KlusterletDeployMode    string
+		KlusterletLogLevel      string
+		KlusterletResources     string
+	}{
+		KlusterletNamespace:     "open-cluster-management-agent",
+		ManagedClusterNamespace: "cluster",
+		RegistrationImageName:   "registration:latest",
+		WorkImageName:           "work:latest",
+		KlusterletName:          "klusterlet",
+		KlusterletResources:     `{"limits":{"memory":"256Mi"},"requests":{"cpu":"50m"}}`,
+	}
+	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := tp.TemplateResource("klusterlet/klusterlet.yaml", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	klusterlet := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(result, &klusterlet.Object); err != nil {
+		t.Fatal(err)
+	}
+	requirementType, _, err := unstructured.NestedString(klusterlet.Object, "spec", "resourceRequirement", "type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requirementType != "ResourceRequirement" {
+		t.Errorf("resourceRequirement.type = %q, want ResourceRequirement", requirementType)
+	}
+	memory, _, err := unstructured.NestedString(klusterlet.Object,
+		"spec", "resourceRequirement", "resourceRequirements", "limits", "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu, _, err := unstructured.NestedString(klusterlet.Object,
+		"spec", "resourceRequirement", "resourceRequirements", "requests", "cpu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memory != "256Mi" || cpu != "50m" {
+		t.Errorf("agents resources = limits.memory %q requests.cpu %q, want 256Mi and 50m", memory, cpu)
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
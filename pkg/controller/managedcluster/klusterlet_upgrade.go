@@ -0,0 +1,111 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+)
+
+//templatesVersionAnnotation is the version of the controller templates the klusterlet manifestworks
+//were rendered from
+const templatesVersionAnnotation = "import.open-cluster-management.io/templates-version"
+
+//defaultKlusterletUpgradeInterval is the minimum delay between two klusterlet upgrades
+const defaultKlusterletUpgradeInterval = 10 * time.Second
+
+//setTemplatesVersion stores the version of the controller templates on the manifestwork,
+//it returns true if the annotation changed
+func setTemplatesVersion(mw *workv1.ManifestWork) bool {
+	version := getTemplatesVersion()
+	annotations := mw.GetAnnotations()
+	if annotations[templatesVersionAnnotation] == version {
+		return false
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[templatesVersionAnnotation] = version
+	mw.SetAnnotations(annotations)
+	return true
+}
+
+//isKlusterletUpgrade returns true if the klusterlet manifestwork of the cluster exists and was rendered
+//from other templates than the controller ones, a manifestwork applied before the version tracking
+//has no version and is upgraded too
+func isKlusterletUpgrade(c client.Client, managedCluster *clusterv1.ManagedCluster) (bool, error) {
+	mwNsN, err := manifestWorkNsN(managedCluster)
+	if err != nil {
+		return false, err
+	}
+	mw := &workv1.ManifestWork{}
+	if err := c.Get(context.TODO(), mwNsN, mw); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return mw.GetAnnotations()[templatesVersionAnnotation] != getTemplatesVersion(), nil
+}
+
+//klusterletUpgradeThrottle spreads the klusterlet upgrades of the clusters over time, so a new controller
+//version doesn't upgrade all the klusterlets at once. Each cluster gets a slot at least interval after
+//the previous one and keeps it until it is used.
+type klusterletUpgradeThrottle struct {
+	mutex sync.Mutex
+	next  time.Time
+	slots map[string]time.Time
+}
+
+var klusterletUpgrades = &klusterletUpgradeThrottle{}
+
+//reserve returns the time the cluster has to wait before upgrading its klusterlet, 0 if it can upgrade now
+func (t *klusterletUpgradeThrottle) reserve(clusterName string, now time.Time, interval time.Duration) time.Duration {
+	if interval == 0 {
+		return 0
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.slots == nil {
+		t.slots = make(map[string]time.Time)
+	}
+	slot, ok := t.slots[clusterName]
+	if !ok {
+		slot = now
+		if t.next.After(slot) {
+			slot = t.next
+		}
+		t.next = slot.Add(interval)
+		t.slots[clusterName] = slot
+	}
+	if now.Before(slot) {
+		return slot.Sub(now)
+	}
+	delete(t.slots, clusterName)
+	return 0
+}
+
+//forget drops the slot of a removed cluster, so the slots of the deleted clusters don't pile up
+func (t *klusterletUpgradeThrottle) forget(clusterName string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.slots, clusterName)
+}
+
+//klusterletUpgradeWait returns the time the cluster has to wait before upgrading its klusterlet,
+//0 if the manifestworks can be applied now
+func (r *ReconcileManagedCluster) klusterletUpgradeWait(managedCluster *clusterv1.ManagedCluster) (time.Duration, error) {
+	upgrade, err := isKlusterletUpgrade(r.client, managedCluster)
+	if err != nil || !upgrade {
+		return 0, err
+	}
+	interval := getEnvDuration(klusterletUpgradeIntervalEnvVarName, defaultKlusterletUpgradeInterval)
+	return klusterletUpgrades.reserve(managedCluster.Name, time.Now(), interval), nil
+}
@@ -0,0 +1,123 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+)
+
+func Test_isKlusterletUpgrade(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{})
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+		},
+	}
+	newManifestWork := func(annotations map[string]string) *workv1.ManifestWork {
+		return &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster1" + manifestWorkNamePostfix,
+				Namespace:   "cluster1",
+				Annotations: annotations,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    bool
+	}{
+		{
+			name: "no manifestwork",
+			want: false,
+		},
+		{
+			name: "current version",
+			objects: []runtime.Object{
+				newManifestWork(map[string]string{templatesVersionAnnotation: getTemplatesVersion()}),
+			},
+			want: false,
+		},
+		{
+			name: "older version",
+			objects: []runtime.Object{
+				newManifestWork(map[string]string{templatesVersionAnnotation: "older"}),
+			},
+			want: true,
+		},
+		{
+			name:    "no version",
+			objects: []runtime.Object{newManifestWork(nil)},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(testscheme, tt.objects...)
+			got, err := isKlusterletUpgrade(c, managedCluster)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("isKlusterletUpgrade() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setTemplatesVersion(t *testing.T) {
+	mw := &workv1.ManifestWork{}
+	if !setTemplatesVersion(mw) {
+		t.Error("expected the version to be set")
+	}
+	if mw.GetAnnotations()[templatesVersionAnnotation] != getTemplatesVersion() {
+		t.Errorf("expected the version %s, got %v", getTemplatesVersion(), mw.GetAnnotations())
+	}
+	if setTemplatesVersion(mw) {
+		t.Error("expected the version to be unchanged")
+	}
+}
+
+func Test_klusterletUpgradeThrottle_reserve(t *testing.T) {
+	throttle := &klusterletUpgradeThrottle{}
+	now := time.Now()
+	interval := 10 * time.Second
+
+	if wait := throttle.reserve("cluster1", now, interval); wait != 0 {
+		t.Errorf("expected cluster1 to upgrade now, got %s", wait)
+	}
+	if wait := throttle.reserve("cluster2", now, interval); wait != interval {
+		t.Errorf("expected cluster2 to wait %s, got %s", interval, wait)
+	}
+	if wait := throttle.reserve("cluster3", now, interval); wait != 2*interval {
+		t.Errorf("expected cluster3 to wait %s, got %s", 2*interval, wait)
+	}
+	//the requeued cluster keeps its slot
+	if wait := throttle.reserve("cluster2", now.Add(interval/2), interval); wait != interval/2 {
+		t.Errorf("expected cluster2 to wait %s, got %s", interval/2, wait)
+	}
+	if wait := throttle.reserve("cluster2", now.Add(interval), interval); wait != 0 {
+		t.Errorf("expected cluster2 to upgrade now, got %s", wait)
+	}
+	if wait := throttle.reserve("cluster4", now, 0); wait != 0 {
+		t.Errorf("expected no throttling with a 0 interval, got %s", wait)
+	}
+
+	//the slot of a removed cluster is dropped
+	throttle.forget("cluster3")
+	if _, ok := throttle.slots["cluster3"]; ok {
+		t.Errorf("expected the slot of cluster3 to be dropped, got %v", throttle.slots)
+	}
+	throttle.forget("unknown")
+}
@@ -0,0 +1,43 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+//autoImportSecretContextKey is the context of the kubeconfig of the auto-import-secret used to import
+//the cluster, for the kubeconfigs with several contexts. The current context is used by default.
+const autoImportSecretContextKey = "context"
+
+const reasonKubeconfigContextNotFound = "KubeconfigContextNotFound"
+
+var errKubeconfigContextNotFound = fmt.Errorf("%w: the context of the auto-import-secret is not in its kubeconfig",
+	ErrInvalidSecret)
+
+//validateKubeconfigContext checks the context selected by the overrides exists in the kubeconfig
+func validateKubeconfigContext(config *clientcmdapi.Config, overrides *clientcmd.ConfigOverrides) error {
+	if overrides.CurrentContext == "" {
+		return nil
+	}
+	if _, ok := config.Contexts[overrides.CurrentContext]; ok {
+		return nil
+	}
+	contexts := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return fmt.Errorf("%w: %q not found in %s", errKubeconfigContextNotFound, overrides.CurrentContext,
+		strings.Join(contexts, ","))
+}
+
+func isKubeconfigContextNotFound(err error) bool {
+	return errors.Is(err, errKubeconfigContextNotFound)
+}
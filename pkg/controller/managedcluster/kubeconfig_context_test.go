@@ -0,0 +1,86 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_newRestConfigFromKubeConfig_context(t *testing.T) {
+	kubeconfig, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"cluster1": {Server: "https://api.cluster1.com:6443"},
+			"cluster2": {Server: "https://api.cluster2.com:6443"},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"admin": {Token: "fake-token"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"context1": {Cluster: "cluster1", AuthInfo: "admin"},
+			"context2": {Cluster: "cluster2", AuthInfo: "admin"},
+		},
+		CurrentContext: "context1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		context   string
+		dialer    string
+		wantHost  string
+		wantSNI   string
+		wantError bool
+	}{
+		{
+			name:     "current context",
+			wantHost: "https://api.cluster1.com:6443",
+		},
+		{
+			name:     "selected context",
+			context:  "context2",
+			wantHost: "https://api.cluster2.com:6443",
+		},
+		{
+			name:     "selected context with dial server",
+			context:  "context2",
+			dialer:   "https://tunnel.hub.com:8443",
+			wantHost: "https://tunnel.hub.com:8443",
+			wantSNI:  "api.cluster2.com",
+		},
+		{
+			name:      "unknown context",
+			context:   "context3",
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides := &clientcmd.ConfigOverrides{CurrentContext: tt.context}
+			overrides.ClusterInfo.Server = tt.dialer
+			restConfig, err := newRestConfigFromKubeConfig(kubeconfig, overrides)
+			if tt.wantError {
+				if !isKubeconfigContextNotFound(err) || !errors.Is(err, ErrInvalidSecret) {
+					t.Fatalf("expected a context not found error, got %v", err)
+				}
+				if reason := importErrorReason(err); reason != reasonKubeconfigContextNotFound {
+					t.Errorf("importErrorReason() = %s, want %s", reason, reasonKubeconfigContextNotFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if restConfig.Host != tt.wantHost {
+				t.Errorf("Host = %s, want %s", restConfig.Host, tt.wantHost)
+			}
+			if restConfig.TLSClientConfig.ServerName != tt.wantSNI {
+				t.Errorf("ServerName = %s, want %s", restConfig.TLSClientConfig.ServerName, tt.wantSNI)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/klog"
+)
+
+//logSubsystem is an area of the controller whose log verbosity can be raised on its own
+type logSubsystem string
+
+const (
+	//logSubsystemImport covers the import of the clusters, the auto-import and the import secret
+	logSubsystemImport logSubsystem = "import"
+	//logSubsystemManifestWork covers the klusterlet and the other manifestworks
+	logSubsystemManifestWork logSubsystem = "manifestwork"
+	//logSubsystemNamespace covers the cluster namespaces
+	logSubsystemNamespace logSubsystem = "namespace"
+)
+
+var (
+	logVerbosityOnce sync.Once
+	logVerbosity     map[logSubsystem]klog.Level
+)
+
+//parseLogVerbosity parses the <subsystem>=<level> list of the LOG_VERBOSITY environment variable,
+//the invalid entries are ignored
+func parseLogVerbosity(v string) map[logSubsystem]klog.Level {
+	levels := make(map[logSubsystem]klog.Level)
+	for _, entry := range strings.Split(v, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			log.Info("Invalid log verbosity, ignoring it", "env", logVerbosityEnvVarName, "value", entry)
+			continue
+		}
+		subsystem := logSubsystem(strings.TrimSpace(kv[0]))
+		switch subsystem {
+		case logSubsystemImport, logSubsystemManifestWork, logSubsystemNamespace:
+		default:
+			log.Info("Unknown log subsystem, ignoring it", "env", logVerbosityEnvVarName, "value", entry)
+			continue
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil || level < 0 {
+			log.Info("Invalid log verbosity, ignoring it", "env", logVerbosityEnvVarName, "value", entry)
+			continue
+		}
+		levels[subsystem] = klog.Level(level)
+	}
+	return levels
+}
+
+//V returns whether the logs of the subsystem at the level are enabled, either by the global
+//-v flag or by the verbosity of the subsystem. The environment variable is read once.
+func (s logSubsystem) V(level klog.Level) klog.Verbose {
+	if klog.V(level) {
+		return true
+	}
+	logVerbosityOnce.Do(func() {
+		logVerbosity = parseLogVerbosity(os.Getenv(logVerbosityEnvVarName))
+	})
+	return klog.Verbose(level <= logVerbosity[s])
+}
@@ -0,0 +1,47 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/klog"
+)
+
+func Test_parseLogVerbosity(t *testing.T) {
+	tests := []struct {
+		name string
+		v    string
+		want map[logSubsystem]klog.Level
+	}{
+		{
+			name: "not set",
+			v:    "",
+			want: map[logSubsystem]klog.Level{},
+		},
+		{
+			name: "several subsystems",
+			v:    "import=4, manifestwork=2,namespace=0",
+			want: map[logSubsystem]klog.Level{
+				logSubsystemImport:       4,
+				logSubsystemManifestWork: 2,
+				logSubsystemNamespace:    0,
+			},
+		},
+		{
+			name: "invalid entries ignored",
+			v:    "import=4,csr=2,manifestwork,namespace=high,import=-1",
+			want: map[logSubsystem]klog.Level{
+				logSubsystemImport: 4,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLogVerbosity(tt.v); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLogVerbosity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
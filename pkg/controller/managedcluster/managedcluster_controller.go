@@ -14,8 +14,11 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -40,12 +43,19 @@ const (
 )
 
 const clusterLabel string = "cluster.open-cluster-management.io/managedCluster"
-const selfManagedLabel string = "local-cluster"
+
+//SelfManagedLabel marks the ManagedCluster of the hub itself, shared with the webhooks
+const SelfManagedLabel string = "local-cluster"
+
 const autoImportRetryName string = "autoImportRetry"
 
 /* #nosec */
 const autoImportSecretName string = "auto-import-secret"
 const ManagedClusterImportSucceeded string = "ManagedClusterImportSucceeded"
+const reasonManagedClusterImported string = "ManagedClusterImported"
+
+const reasonWaitingForClusterNamespace string = "WaitingForClusterNamespace"
+const waitingForClusterNamespaceRequeueAfter = 10 * time.Second
 
 var log = logf.Log.WithName("controller_managedcluster")
 
@@ -54,13 +64,15 @@ var log = logf.Log.WithName("controller_managedcluster")
 * business logic.  Delete these comments after modifying this file.*
  */
 
-// customClient will do get secret without cache, other operations are like normal cache client
+// customClient will do get secret and configmap without cache, other operations are like normal cache client.
+// A cached Get would start an informer of all the secrets or configmaps of the hub.
 type customClient struct {
 	client.Client
 	APIReader client.Reader
 }
 
-// newCustomClient creates custom client to do get secret without cache
+// newCustomClient creates custom client to do get secret and configmap without cache,
+// apiReader is the uncached reader of the manager, tests can pass any client.Reader
 func newCustomClient(client client.Client, apiReader client.Reader) client.Client {
 	return customClient{
 		Client:    client,
@@ -69,7 +81,8 @@ func newCustomClient(client client.Client, apiReader client.Reader) client.Clien
 }
 
 func (cc customClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
-	if _, ok := obj.(*corev1.Secret); ok {
+	switch obj.(type) {
+	case *corev1.Secret, *corev1.ConfigMap:
 		return cc.APIReader.Get(ctx, key, obj)
 	}
 	return cc.Client.Get(ctx, key, obj)
@@ -107,15 +120,56 @@ func newManifestWorkSpecPredicate() predicate.Predicate {
 	})
 }
 
+//isImportSecret returns true if the object is named as the import secret of the cluster namespace,
+//or as the import secret of the cluster of its cluster label when it is in a dedicated namespace
+func isImportSecret(obj metav1.Object) bool {
+	if obj.GetName() == obj.GetNamespace()+importSecretNamePostfix {
+		return true
+	}
+	cluster, ok := obj.GetLabels()[clusterLabel]
+	return ok && obj.GetName() == cluster+importSecretNamePostfix
+}
+
+//newImportSecretPredicate filters the events to the deletion or the modification of the import secret
+func newImportSecretPredicate() predicate.Predicate {
+	return predicate.Predicate(predicate.Funcs{
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return e.Meta != nil && isImportSecret(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.MetaNew == nil || !isImportSecret(e.MetaNew) {
+				return false
+			}
+			newSecret, okNew := e.ObjectNew.(*corev1.Secret)
+			oldSecret, okOld := e.ObjectOld.(*corev1.Secret)
+			if okNew && okOld {
+				return !reflect.DeepEqual(newSecret.Data, oldSecret.Data)
+			}
+			return false
+		},
+	})
+}
+
 // blank assignment to verify that ReconcileManagedCluster implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileManagedCluster{}
 
 // ReconcileManagedCluster reconciles a ManagedCluster object
+// It must stay safe for concurrent reconciles: the fields are only set at creation,
+// the clients are goroutine safe and the applier is created for each reconcile.
 type ReconcileManagedCluster struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
-	scheme *runtime.Scheme
+	// apiReader reads directly from the apiserver, it is used to confirm a deletion seen in the cache
+	apiReader client.Reader
+	// kubeClient is used for the subresources not supported by the client, as the token requests
+	kubeClient kubernetes.Interface
+	scheme     *runtime.Scheme
+	recorder   record.EventRecorder
+	// selector restricts the reconciled ManagedClusters when the imports are sharded, nil selects all
+	selector labels.Selector
 }
 
 // Reconcile reads that state of the cluster for a ManagedCluster object and makes changes based on the state read
@@ -123,10 +177,26 @@ type ReconcileManagedCluster struct {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ReconcileManagedCluster) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+func (r *ReconcileManagedCluster) Reconcile(request reconcile.Request) (res reconcile.Result, retErr error) {
+	id := beginCorrelation(request.Name)
+	defer endCorrelation(request.Name)
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name,
+		"correlationID", id)
 	reqLogger.Info("Reconciling ManagedCluster")
 
+	steps := &reconcileSteps{}
+	defer func() {
+		steps.stop()
+		if errCond := r.setConditionImportSteps(steps, retErr); errCond != nil {
+			reqLogger.Error(errCond, "Failed to set the import steps condition")
+		}
+		if steps.managedCluster != nil {
+			if errStatus := r.syncImportStatus(steps.managedCluster); errStatus != nil {
+				reqLogger.Error(errStatus, "Failed to update the import status")
+			}
+		}
+	}()
+
 	// Fetch the ManagedCluster instance
 	instance := &clusterv1.ManagedCluster{}
 
@@ -139,12 +209,36 @@ func (r *ReconcileManagedCluster) Reconcile(request reconcile.Request) (reconcil
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			gone, err := r.managedClusterGone(request.Name)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if !gone {
+				reqLogger.Info("ManagedCluster not found in cache but still exists, skipping namespace deletion")
+				return reconcile.Result{Requeue: true}, nil
+			}
+			klusterletUpgrades.forget(request.Name)
+			hubManifestsToRetry.forget(request.Name)
+			forgetManifestWorkConflicts(request.Name)
+			if isReadOnly() {
+				reqLogger.Info("Read-only, would delete the cluster namespace")
+				deletions.release(request.Name)
+				return reconcile.Result{}, nil
+			}
+			//The slot held by the cleanup of the cluster, if not released or expired, is kept for the
+			//deletion of its namespace
+			if !deletions.acquire(request.Name, maxConcurrentDeletions(), time.Now()) {
+				return reconcile.Result{RequeueAfter: throttledRequeueAfter()}, nil
+			}
 			reqLogger.Info(fmt.Sprintf("deleteNamespace: %s", request.Name))
 			err = r.deleteNamespace(request.Name)
+			deletions.release(request.Name)
 			if err != nil {
-				reqLogger.Error(err, "Failed to delete namespace")
-				return reconcile.Result{Requeue: true, RequeueAfter: 1 * time.Minute}, nil
+				retryAfter := namespaceDeletionRetries.next(request.Name)
+				reqLogger.Error(err, "Failed to delete namespace", "retryAfter", retryAfter.String())
+				return reconcile.Result{Requeue: true, RequeueAfter: retryAfter}, nil
 			}
+			namespaceDeletionRetries.reset(request.Name)
 
 			return reconcile.Result{}, nil
 		}
@@ -152,10 +246,50 @@ func (r *ReconcileManagedCluster) Reconcile(request reconcile.Request) (reconcil
 		return reconcile.Result{}, err
 	}
 
+	if !isManagedClusterSelected(r.selector, instance) {
+		if logSubsystemImport.V(2) {
+			reqLogger.Info("ManagedCluster not selected by this controller, skipping")
+		}
+		//The cluster may have been unselected during its cleanup
+		deletions.release(instance.Name)
+		return reconcile.Result{}, nil
+	}
+
+	//Shadow mode, nothing is written
+	if isReadOnly() {
+		return reconcile.Result{}, r.reconcileReadOnly(reqLogger, instance)
+	}
+
 	if instance.DeletionTimestamp != nil {
 		return r.managedClusterDeletion(instance)
 	}
 
+	if isPaused(instance) {
+		reqLogger.Info("Reconciliation paused", "annotation", pausedAnnotation)
+		return reconcile.Result{}, r.setConditionPaused(instance, true)
+	}
+	if err := r.setConditionPaused(instance, false); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	//Never manage a system namespace, the cluster namespace is labeled and deleted with the cluster
+	if isProtectedNamespace(instance.Name) {
+		reqLogger.Info("The cluster namespace is protected, the cluster is not imported")
+		return reconcile.Result{}, r.reportProtectedNamespace(instance)
+	}
+
+	//Staged rollout, the clusters of a wave wait for the lower waves to be imported
+	waiting, err := r.waitForImportWave(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if waiting {
+		reqLogger.Info("Waiting for the lower import waves", "annotation", importWaveAnnotation)
+		return reconcile.Result{RequeueAfter: importWaveRequeueAfter}, nil
+	}
+
+	steps.managedCluster = instance
+	steps.start(stepAddFinalizer)
 	reqLogger.Info(fmt.Sprintf("AddFinalizer to instance: %s", instance.Name))
 	libgometav1.AddFinalizer(instance, managedClusterFinalizer)
 
@@ -173,37 +307,61 @@ func (r *ReconcileManagedCluster) Reconcile(request reconcile.Request) (reconcil
 		return reconcile.Result{}, err
 	}
 
+	if err := r.setConditionSelfManaged(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	steps.start(stepEnsureClusterNamespace)
 	//Add clusterLabel on ns if missing
 	ns := &corev1.Namespace{}
 	if err := r.client.Get(
 		context.TODO(),
 		types.NamespacedName{Namespace: "", Name: instance.Name},
 		ns); err != nil {
+		if errors.IsNotFound(err) {
+			//The cluster namespace is created by the registration, wait for it
+			reqLogger.Info("Waiting for the cluster namespace")
+			if err := r.setCondition(instance, metav1.Condition{
+				Type:    importConditionType(),
+				Status:  metav1.ConditionFalse,
+				Reason:  reasonWaitingForClusterNamespace,
+				Message: fmt.Sprintf("Waiting for the namespace %s to be created", instance.Name),
+			}); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: waitingForClusterNamespaceRequeueAfter}, nil
+		}
 		return reconcile.Result{}, err
 	}
-
-	labels := ns.GetLabels()
-	if labels == nil {
-		labels = make(map[string]string)
+	if ns.DeletionTimestamp != nil {
+		return r.waitForClusterNamespaceTermination(instance, nil)
+	}
+	if owner := getClusterNamespaceLabelConflict(ns, instance); owner != "" {
+		return r.reportClusterNamespaceLabelConflict(instance, owner)
 	}
-	if _, ok := labels[clusterLabel]; !ok {
-		labels[clusterLabel] = instance.Name
-		ns.SetLabels(labels)
+	workload, err := r.getNamespaceConflict(ns)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if workload != "" {
+		return r.reportNamespaceConflict(instance, workload)
+	}
+	if err := r.clearConditionImportWaiting(instance, reasonWaitingForClusterNamespace,
+		reasonWaitingForNamespaceTermination, reasonClusterNamespaceTerminating, reasonClusterNamespaceLabelConflict,
+		reasonNamespaceConflict, reasonWaitingForImportWave,
+		reasonWaitingForClusterRegistration); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if mergeNamespaceLabels(ns, instance.Name, getNamespaceLabels()) {
 		if err := r.client.Update(context.TODO(), ns); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
+	steps.start(stepCreateServiceAccount)
 	//Create the values for the yamls
-	config := struct {
-		ManagedClusterName          string
-		ManagedClusterNamespace     string
-		BootstrapServiceAccountName string
-	}{
-		ManagedClusterName:          instance.Name,
-		ManagedClusterNamespace:     instance.Name,
-		BootstrapServiceAccountName: instance.Name + bootstrapServiceAccountNamePostfix,
-	}
+	config := newHubManifestsValues(instance)
 
 	a, err := applier.NewApplier(
 		bindata.NewBindataReader(),
@@ -218,7 +376,16 @@ func (r *ReconcileManagedCluster) Reconcile(request reconcile.Request) (reconcil
 	}
 
 	sa := &corev1.ServiceAccount{}
-	if err := r.client.Get(context.TODO(),
+	if isBootstrapServiceAccountSpecified(instance) {
+		//The service account is provisioned by the user, it's never created nor deleted by the controller
+		if err := validateBootstrapServiceAccount(r.client, instance); err != nil {
+			if isInvalidBootstrapServiceAccount(err) {
+				//setConditionImport returns the import error when the condition is set
+				return reconcile.Result{}, r.setConditionImport(instance, err, "")
+			}
+			return reconcile.Result{}, err
+		}
+	} else if err := r.client.Get(context.TODO(),
 		types.NamespacedName{
 			Name:      instance.Name + bootstrapServiceAccountNamePostfix,
 			Namespace: instance.Name,
@@ -231,77 +398,338 @@ func (r *ReconcileManagedCluster) Reconcile(request reconcile.Request) (reconcil
 			"hub/managedcluster/manifests/managedcluster-service-account.yaml",
 			config,
 		)
+		if isNamespaceTerminating(err) {
+			//The namespace started terminating after it was read
+			return r.waitForClusterNamespaceTermination(instance, err)
+		}
+		if operations := getForbiddenOperations(err); operations != nil {
+			return r.reportControllerRBACInsufficient(instance, operations, err)
+		}
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
-	reqLogger.Info(fmt.Sprintf("CreateOrUpdateInPath hub/managedcluster/manifests except sa: %s", instance.Name))
-	err = a.CreateOrUpdateInPath(
-		"hub/managedcluster/manifests",
-		[]string{"hub/managedcluster/manifests/managedcluster-service-account.yaml"},
-		false,
-		config,
-	)
+	steps.start(stepRotateBootstrapToken)
+	rotated, err := r.rotateBootstrapToken(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if rotated {
+		return reconcile.Result{Requeue: true, RequeueAfter: bootstrapTokenRotationRequeueAfter}, nil
+	}
+	reissued, err := r.reissueBootstrapToken(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if reissued {
+		return reconcile.Result{Requeue: true, RequeueAfter: bootstrapTokenRotationRequeueAfter}, nil
+	}
 
+	steps.start(stepRepairBootstrapRBAC)
+	rbacDrift, err := repairBootstrapRBAC(r.client, instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	crds, yamls, err := generateImportYAMLs(r.client, instance, []string{})
+	steps.start(stepGenerateImportYAMLs)
+	crds, yamls, err := generateImportYAMLs(r.client, r.kubeClient, instance, []string{})
+	if err != nil {
+		if isConfigError(err) {
+			//setConditionImport returns the import error when the condition is set
+			return reconcile.Result{}, r.setConditionImport(instance, err, "")
+		}
+		return reconcile.Result{}, err
+	}
+	if err := r.setConditionRegionRegistry(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	extras, err := getExtraManifests(r.client, crds, yamls)
 	if err != nil {
+		if isConfigError(err) {
+			return reconcile.Result{}, r.setConditionImport(instance, err, "")
+		}
 		return reconcile.Result{}, err
 	}
 
-	reqLogger.Info(fmt.Sprintf("createOrUpdateImportSecret: %s", instance.Name))
-	_, err = createOrUpdateImportSecret(r.client, r.scheme, instance, crds, yamls)
+	//Skip the applies if the rendered configuration is already applied and the resources are unchanged
+	configHash, err := renderedConfigHash(instance, config, crds, append(yamls, extras...))
 	if err != nil {
-		reqLogger.Error(err, "create ManagedCluster Import Secret")
+		return reconcile.Result{}, err
+	}
+	upToDate, err := r.isRenderedConfigApplied(instance, configHash)
+	if isWorkAPIUnavailable(err) {
+		return r.reportWorkAPIUnavailable(instance, err)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	//A repaired bootstrap RBAC must be recreated by the applies
+	if rbacDrift != "" {
+		upToDate = false
+	}
+	if upToDate && logSubsystemImport.V(2) {
+		reqLogger.Info(fmt.Sprintf("Rendered configuration already applied, skipping the applies: %s", instance.Name))
+	}
+
+	steps.start(stepApplyHubManifests)
+	if !upToDate {
+		reqLogger.Info(fmt.Sprintf("CreateOrUpdate hub/managedcluster/manifests except sa: %s", instance.Name))
+		//Only the manifests which failed are re-attempted, unless the repaired RBAC must be recreated
+		var only map[string]bool
+		if rbacDrift == "" {
+			only = hubManifestsToRetry.get(instance.Name, configHash)
+		}
+		err = applyHubManifests(
+			a,
+			[]string{"hub/managedcluster/manifests/managedcluster-service-account.yaml"},
+			only,
+			config,
+		)
+		hubManifestsToRetry.record(instance.Name, configHash, err)
+		if isNamespaceTerminating(err) {
+			return r.waitForClusterNamespaceTermination(instance, err)
+		}
+		if errCond := r.setConditionHubManifestsApplied(instance, err); errCond != nil {
+			reqLogger.Error(errCond, "Failed to set the hub manifests condition")
+		}
+		if operations := getForbiddenOperations(err); operations != nil {
+			return r.reportControllerRBACInsufficient(instance, operations, err)
+		}
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	controllerRBACRetries.reset(instance.Name)
+	if err := r.clearConditionImportWaiting(instance, reasonControllerRBACInsufficient); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	if rbacDrift != "" {
+		if err := r.setCondition(instance, metav1.Condition{
+			Type:    ConditionBootstrapRBACDriftCorrected,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonBootstrapRBACRepaired,
+			Message: rbacDrift,
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	steps.start(stepCreateOrUpdateImportSecret)
+	if !upToDate {
+		reqLogger.Info(fmt.Sprintf("createOrUpdateImportSecret: %s", instance.Name))
+		if err := validateImportYAMLs(crds, yamls); err != nil {
+			reqLogger.Error(err, "Invalid import secret content")
+			return reconcile.Result{}, r.setConditionImport(instance, err, "")
+		}
+		_, err = createOrUpdateImportSecret(r.client, r.scheme, instance, crds, yamls)
+		if isNamespaceTerminating(err) {
+			return r.waitForClusterNamespaceTermination(instance, err)
+		}
+		if err != nil {
+			reqLogger.Error(err, "create ManagedCluster Import Secret")
+			return reconcile.Result{}, err
+		}
+		if err := r.clearConditionBootstrapTokenReissued(instance); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := r.clearConditionBootstrapTokenRotated(instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	//Remove syncset if exists as we are now using manifestworks
-	result, err := deleteKlusterletSyncSets(r.client, instance)
+	steps.start(stepDeleteKlusterletSyncSets)
+	result, syncSetsCleanup, err := deleteKlusterletSyncSets(r.client, instance)
 	if err != nil {
 		return result, err
 	}
+	if err := r.reportKlusterletSyncSetsCleanup(instance, syncSetsCleanup); err != nil {
+		return reconcile.Result{}, err
+	}
 
-	if !checkOffLine(instance) {
-		reqLogger.Info(fmt.Sprintf("createOrUpdateManifestWorks: %s", instance.Name))
-		_, _, err = createOrUpdateManifestWorks(r.client, r.scheme, instance, crds, yamls)
-		if err != nil {
-			reqLogger.Error(err, "Error while creating mw")
+	//The manifestworks of an import before the klusterlet was provisioned externally are stale
+	if isKlusterletProvisionedExternally(instance) {
+		if err := r.cleanUpStaleKlusterletManifestWorks(instance); err != nil {
+			if isWorkAPIUnavailable(err) {
+				return r.reportWorkAPIUnavailable(instance, err)
+			}
 			return reconcile.Result{}, err
 		}
+	}
+
+	if !checkOffLine(instance) {
+		//The manifestworks keep the klusterlet, no need to verify the import or to wait for its readiness
+		pendingImportVerifications.forget(instance.Name)
+		pendingKlusterletReadiness.forget(instance.Name)
+		steps.start(stepCreateOrUpdateManifestWorks)
+		if !isKlusterletProvisionedExternally(instance) {
+			if err := r.recordJoinedImportMethod(instance); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		if !upToDate && isKlusterletProvisionedExternally(instance) {
+			if logSubsystemManifestWork.V(2) {
+				reqLogger.Info(fmt.Sprintf("Klusterlet provisioned externally, skipping the manifestworks: %s", instance.Name))
+			}
+			if err := r.recordRenderedConfigApplied(instance, configHash); err != nil {
+				return reconcile.Result{}, err
+			}
+		} else if !upToDate {
+			//Spread the upgrades of the klusterlets when the controller templates changed
+			wait, err := r.klusterletUpgradeWait(instance)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if wait > 0 {
+				reqLogger.Info("Klusterlet upgrade throttled", "requeueAfter", wait.String())
+				return reconcile.Result{RequeueAfter: wait}, nil
+			}
+			reqLogger.Info(fmt.Sprintf("createOrUpdateManifestWorks: %s", instance.Name))
+			_, _, err = createOrUpdateManifestWorks(r.client, r.scheme, instance, crds, yamls, extras)
+			if isNamespaceTerminating(err) {
+				return r.waitForClusterNamespaceTermination(instance, err)
+			}
+			if isWorkAPIUnavailable(err) {
+				return r.reportWorkAPIUnavailable(instance, err)
+			}
+			if errCond := r.setConditionManifestWorkApplyConflict(instance, err); errCond != nil {
+				reqLogger.Error(errCond, "Failed to set the manifestwork apply conflict condition")
+			}
+			if errCond := r.setConditionManifestWorkSizeExceeded(instance, err); errCond != nil {
+				reqLogger.Error(errCond, "Failed to set the manifestwork size condition")
+			}
+			if err != nil {
+				reqLogger.Error(err, "Error while creating mw")
+				return reconcile.Result{}, err
+			}
+			if err := r.clearConditionWorkAPIUnavailable(instance); err != nil {
+				return reconcile.Result{}, err
+			}
+			if err := r.recordRenderedConfigApplied(instance, configHash); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
 	} else {
+		if !upToDate {
+			if err := r.recordRenderedConfigApplied(instance, configHash); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		//Verify once the klusterlet of a successful import is still there
+		if c, wait, ok := pendingImportVerifications.take(instance.Name, time.Now()); ok {
+			if wait > 0 {
+				return reconcile.Result{RequeueAfter: wait}, nil
+			}
+			return r.verifyImport(instance, c)
+		}
+		steps.start(stepToBeImported)
 		autoImportSecret, clusterDeployment, toImport, err := r.toBeImported(instance)
+		if isConfigError(err) {
+			return reconcile.Result{}, r.setConditionImport(instance, err, "")
+		}
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		//The readiness of the klusterlet is only checked for an auto-import
+		if autoImportSecret == nil {
+			pendingKlusterletReadiness.forget(instance.Name)
+		}
 
 		//Stop here if no auto-import
 		if !toImport {
-			klog.Infof("Not importing auto-import cluster: %s", instance.Name)
+			logSubsystemImport.V(0).Infof(correlated(instance.Name, "Not importing auto-import cluster: %s"), instance.Name)
+			steps.done()
 			return reconcile.Result{}, nil
 		}
 
+		//A brand-new cluster without conditions is not offline, it is not registered yet
+		if autoImportSecret != nil && isPendingRegistration(instance) {
+			reqLogger.Info("Waiting for the cluster registration before the auto-import")
+			return reconcile.Result{}, r.setConditionWaitingForClusterRegistration(instance)
+		}
+
+		//Give the endpoint of a cluster being provisioned the time to be ready
+		if autoImportSecret != nil {
+			delay := autoImportInitialDelay()
+			if remaining := autoImportDelayRemaining(instance, autoImportSecret, delay, time.Now()); remaining > 0 {
+				reqLogger.Info("Waiting before the first auto-import attempt", "requeueAfter", remaining.String())
+				return reconcile.Result{RequeueAfter: remaining}, r.setConditionWaitingForAutoImportDelay(instance, delay)
+			}
+		}
+
 		//Import the cluster
+		steps.start(stepImportCluster)
+		if IsSelfManaged(instance) {
+			unlock, err := r.lockSelfImport()
+			if err == errSelfImportLocked {
+				reqLogger.Info("The self-import is in progress elsewhere, requeue")
+				return reconcile.Result{RequeueAfter: selfImportLockedRequeueAfter}, nil
+			}
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			defer unlock()
+		}
+		if !imports.begin(instance.Name) {
+			//The controller is shutting down, the next instance will import the cluster
+			reqLogger.Info("Shutting down, the import is postponed")
+			return reconcile.Result{Requeue: true}, nil
+		}
 		result, err := r.importCluster(instance, clusterDeployment, autoImportSecret)
+		imports.end(instance.Name)
+		if isClusterNotInstalled(err) {
+			//Not a failure, the import is retried once the cluster is installed
+			if errCond := r.setConditionImport(instance, err, ""); errCond != err {
+				reqLogger.Error(errCond, "Failed to set the import condition")
+			}
+			return result, nil
+		}
+		if isTransientApplyError(err) {
+			//Retry with the exponential backoff of the controller, the cluster is not failed
+			if errCond := r.setConditionImport(instance, err, ""); errCond != err {
+				reqLogger.Error(errCond, "Failed to set the import condition")
+			}
+			reqLogger.Error(err, "Transient failure to apply the klusterlet, will retry")
+			steps.fail(err)
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if err != nil && autoImportSecret != nil {
+			//setConditionImport returns the import error when the condition is set
+			if errCond := r.setConditionImport(instance, err, fmt.Sprintf("Unable to import %s", instance.Name)); errCond != err {
+				reqLogger.Error(errCond, "Failed to set the import condition")
+			}
+			//The failed auto-import is retried with the exponential backoff of the controller
+			return reconcile.Result{}, err
+		}
 		if result.Requeue || err != nil {
 			return result, err
 		}
+		//The auto-import-secret is still there, check again on a schedule as an offline cluster
+		//doesn't generate events
+		if interval := offlineClusterRequeueInterval(); autoImportSecret != nil &&
+			result.RequeueAfter == 0 && interval > 0 {
+			result.RequeueAfter = interval
+		}
 		errCond := r.setConditionImport(instance, err, fmt.Sprintf("Unable to import %s", instance.Name))
 		if errCond != nil {
 			klog.Error(errCond)
 		}
+		if err := r.recordImportMethod(instance,
+			resolveImportMethod(instance, clusterDeployment, autoImportSecret)); err != nil {
+			return reconcile.Result{}, err
+		}
+		steps.done()
 		return result, err
 	}
+	steps.done()
 	return reconcile.Result{}, nil
 }
 
 func (r *ReconcileManagedCluster) toBeImported(managedCluster *clusterv1.ManagedCluster) (*corev1.Secret, *hivev1.ClusterDeployment, bool, error) {
 	//Check self managed
-	if v, ok := managedCluster.GetLabels()[selfManagedLabel]; ok {
+	if v, ok := managedCluster.GetLabels()[SelfManagedLabel]; ok {
 		toImport, err := strconv.ParseBool(v)
 		return nil, nil, toImport, err
 	}
@@ -318,54 +746,116 @@ func (r *ReconcileManagedCluster) toBeImported(managedCluster *clusterv1.Managed
 	if err == nil {
 		//clusterDeployment found and so need to be imported
 		return nil, clusterDeployment, true, nil
-	} else if !errors.IsNotFound(err) {
+	} else if !errors.IsNotFound(err) && !isClusterDeploymentCRDMissing(err) {
 		//Error
 		return nil, nil, false, err
 	}
 	//Check auto-import
-	klog.V(2).Info("Check autoImportRetry")
+	logSubsystemImport.V(2).Info(correlated(managedCluster.Name, "Check autoImportRetry"))
+	secretName, err := getAutoImportSecretName(managedCluster)
+	if err != nil {
+		return nil, nil, false, err
+	}
 	autoImportSecret := &corev1.Secret{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{
-		Name:      autoImportSecretName,
+		Name:      secretName,
 		Namespace: managedCluster.Name,
 	},
 		autoImportSecret)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			klog.Infof("Will not retry as autoImportSecret not found for %s", managedCluster.Name)
+			logSubsystemImport.V(0).Infof(correlated(managedCluster.Name, "Will not retry as autoImportSecret not found for %s"),
+				managedCluster.Name)
 			return nil, nil, false, nil
 		}
-		klog.Errorf("Unable to read the autoImportSecret Error: %s", err.Error())
+		klog.Errorf(correlated(managedCluster.Name, "Unable to read the autoImportSecret Error: %s"), err.Error())
 		return nil, nil, false, err
 	}
-	klog.Infof("Will retry as autoImportSecret is found for %s and counter still present", managedCluster.Name)
+	if isAutoImportSecretConsumed(autoImportSecret) {
+		logSubsystemImport.V(0).Infof(
+			correlated(managedCluster.Name, "Will not retry as the kept autoImportSecret is already consumed for %s"),
+			managedCluster.Name)
+		return nil, nil, false, nil
+	}
+	logSubsystemImport.V(0).Infof(
+		correlated(managedCluster.Name, "Will retry as autoImportSecret is found for %s and counter still present"),
+		managedCluster.Name)
 	return autoImportSecret, nil, true, nil
 }
 
 func (r *ReconcileManagedCluster) setConditionImport(managedCluster *clusterv1.ManagedCluster, errIn error, reason string) error {
 	newCondition := metav1.Condition{
-		Type:    ManagedClusterImportSucceeded,
+		Type:    importConditionType(),
 		Status:  metav1.ConditionTrue,
 		Message: "Import succeeded",
-		Reason:  "ManagedClusterImported",
+		Reason:  reasonManagedClusterImported,
 	}
 	if errIn != nil {
 		newCondition.Status = metav1.ConditionFalse
 		newCondition.Message = errIn.Error()
-		newCondition.Reason = "ManagedClusterNotImported"
+		newCondition.Reason = importErrorReason(errIn)
 		if reason != "" {
 			newCondition.Message += ": " + reason
 		}
 	}
-	patch := client.MergeFrom(managedCluster.DeepCopy())
-	meta.SetStatusCondition(&managedCluster.Status.Conditions, newCondition)
-	err := r.client.Status().Patch(context.TODO(), managedCluster, patch)
-	if err != nil {
+	if err := r.setCondition(managedCluster, newCondition); err != nil {
 		return err
 	}
 	return errIn
 }
 
+//clearConditionImportWaiting removes the import condition if it was set while waiting for one of the reasons
+func (r *ReconcileManagedCluster) clearConditionImportWaiting(managedCluster *clusterv1.ManagedCluster, reasons ...string) error {
+	c := meta.FindStatusCondition(managedCluster.Status.Conditions, importConditionType())
+	if c == nil {
+		return nil
+	}
+	for _, reason := range reasons {
+		if c.Reason == reason {
+			patch := client.MergeFrom(managedCluster.DeepCopy())
+			meta.RemoveStatusCondition(&managedCluster.Status.Conditions, importConditionType())
+			return r.client.Status().Patch(context.TODO(), managedCluster, patch)
+		}
+	}
+	return nil
+}
+
+//setConditionHubManifestsApplied reports which hub manifests failed to be applied,
+//the condition is only patched when it changes
+func (r *ReconcileManagedCluster) setConditionHubManifestsApplied(managedCluster *clusterv1.ManagedCluster, errIn error) error {
+	newCondition := metav1.Condition{
+		Type:    ConditionHubManifestsApplied,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonHubManifestsApplied,
+		Message: "All hub manifests are applied",
+	}
+	if errIn != nil {
+		newCondition.Status = metav1.ConditionFalse
+		newCondition.Reason = reasonHubManifestsApplyFailed
+		newCondition.Message = errIn.Error()
+	}
+	if c := meta.FindStatusCondition(managedCluster.Status.Conditions, newCondition.Type); c != nil &&
+		c.Status == newCondition.Status && c.Message == newCondition.Message {
+		return nil
+	}
+	return r.setCondition(managedCluster, newCondition)
+}
+
+//recordEvent records an event on the object if the reconciler has a recorder
+func (r *ReconcileManagedCluster) recordEvent(obj runtime.Object, eventType, reason, message string) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.Event(obj, eventType, reason, message)
+}
+
+//setCondition sets the condition on the managedCluster status and patches it
+func (r *ReconcileManagedCluster) setCondition(managedCluster *clusterv1.ManagedCluster, condition metav1.Condition) error {
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	meta.SetStatusCondition(&managedCluster.Status.Conditions, condition)
+	return r.client.Status().Patch(context.TODO(), managedCluster, patch)
+}
+
 func filterFinalizers(managedCluster *clusterv1.ManagedCluster, finalizers []string) []string {
 	results := make([]string, 0)
 	clusterFinalizers := managedCluster.GetFinalizers()
@@ -384,16 +874,30 @@ func filterFinalizers(managedCluster *clusterv1.ManagedCluster, finalizers []str
 	return results
 }
 
-func checkOffLine(managedCluster *clusterv1.ManagedCluster) bool {
-	for _, sc := range managedCluster.Status.Conditions {
-		if sc.Type == clusterv1.ManagedClusterConditionAvailable {
-			return sc.Status == metav1.ConditionUnknown || sc.Status == metav1.ConditionFalse
-		}
+//managedClusterGone confirms without cache that the ManagedCluster doesn't exist anymore,
+//a stale cache must not trigger the deletion of the cluster namespace
+func (r *ReconcileManagedCluster) managedClusterGone(name string) (bool, error) {
+	var reader client.Reader = r.client
+	if r.apiReader != nil {
+		reader = r.apiReader
+	}
+	err := reader.Get(context.TODO(), types.NamespacedName{Name: name}, &clusterv1.ManagedCluster{})
+	if err == nil {
+		return false, nil
+	}
+	if errors.IsNotFound(err) {
+		return true, nil
 	}
-	return true
+	return false, err
 }
 
 func (r *ReconcileManagedCluster) deleteNamespace(namespaceName string) error {
+	//The cluster namespace is named as the cluster
+	log := clusterLogger(namespaceName)
+	if isProtectedNamespace(namespaceName) {
+		log.Info("Namespace " + namespaceName + " is protected, it is not deleted")
+		return nil
+	}
 	ns := &corev1.Namespace{}
 	err := r.client.Get(
 		context.TODO(),
@@ -414,6 +918,13 @@ func (r *ReconcileManagedCluster) deleteNamespace(namespaceName string) error {
 		log.Info("Already in deletion")
 		return nil
 	}
+	//Only a namespace adopted by the import of the cluster is deleted, a namespace not labeled, even
+	//holding only secrets or configmaps, or labeled for another cluster is used by something else
+	if owner := ns.GetLabels()[clusterLabel]; owner != namespaceName {
+		log.Info("Namespace "+namespaceName+" is not labeled for the cluster, it is not deleted",
+			"label", clusterLabel, "value", owner)
+		return nil
+	}
 
 	clusterDeployment := &hivev1.ClusterDeployment{}
 	err = r.client.Get(
@@ -426,7 +937,7 @@ func (r *ReconcileManagedCluster) deleteNamespace(namespaceName string) error {
 	)
 	tobeDeleted := false
 	if err != nil {
-		if errors.IsNotFound(err) {
+		if errors.IsNotFound(err) || isClusterDeploymentCRDMissing(err) {
 			tobeDeleted = true
 		} else {
 			log.Error(err, "Failed to get cluster deployment")
@@ -438,6 +949,10 @@ func (r *ReconcileManagedCluster) deleteNamespace(namespaceName string) error {
 		if err != nil {
 			return err
 		}
+		blocker := fmt.Sprintf("ClusterDeployment %s/%s", clusterDeployment.Namespace, clusterDeployment.Name)
+		if err := r.reportNamespaceDeletionBlocked(ns, blocker); err != nil {
+			log.Error(err, "Failed to report the blocked namespace deletion")
+		}
 		return fmt.Errorf(
 			"can not delete namespace %s as ClusterDeployment %s still exist",
 			namespaceName,
@@ -445,6 +960,7 @@ func (r *ReconcileManagedCluster) deleteNamespace(namespaceName string) error {
 		)
 	}
 	if tobeDeleted {
+		r.reportNamespaceDeletionUnblocked(ns)
 		err = r.client.Delete(context.TODO(), ns)
 		if err != nil && !errors.IsNotFound(err) {
 			log.Error(err, "Failed to delete namespace")
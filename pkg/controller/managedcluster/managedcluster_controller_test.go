@@ -5,10 +5,12 @@ package managedcluster
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,12 +20,14 @@ import (
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -587,7 +591,8 @@ func TestReconcileManagedCluster_deleteNamespace(t *testing.T) {
 
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "mycluster",
+			Name:   "mycluster",
+			Labels: map[string]string{clusterLabel: "mycluster"},
 		},
 	}
 
@@ -725,6 +730,329 @@ func TestReconcileManagedCluster_deleteNamespace(t *testing.T) {
 	}
 }
 
+func TestReconcileManagedCluster_Reconcile_staleCache(t *testing.T) {
+	testscheme := scheme.Scheme
+
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(hivev1.SchemeGroupVersion, &hivev1.ClusterDeployment{})
+	testscheme.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Namespace{})
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "mycluster",
+			Labels: map[string]string{clusterLabel: "mycluster"},
+		},
+	}
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		apiReader   client.Reader
+		wantRequeue bool
+		wantNS      bool
+	}{
+		{
+			name:        "managedcluster still exists",
+			apiReader:   fake.NewFakeClientWithScheme(testscheme, managedCluster),
+			wantRequeue: true,
+			wantNS:      true,
+		},
+		{
+			name:        "managedcluster deleted",
+			apiReader:   fake.NewFakeClientWithScheme(testscheme),
+			wantRequeue: false,
+			wantNS:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcileManagedCluster{
+				client:    fake.NewFakeClientWithScheme(testscheme, ns.DeepCopy()),
+				apiReader: tt.apiReader,
+				scheme:    testscheme,
+			}
+			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "mycluster"}})
+			if err != nil {
+				t.Errorf("ReconcileManagedCluster.Reconcile() error = %v", err)
+				return
+			}
+			if got.Requeue != tt.wantRequeue {
+				t.Errorf("ReconcileManagedCluster.Reconcile() requeue = %v, want %v", got.Requeue, tt.wantRequeue)
+			}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, &corev1.Namespace{})
+			if tt.wantNS && err != nil {
+				t.Errorf("expected the namespace to be kept, got %v", err)
+			}
+			if !tt.wantNS && !errors.IsNotFound(err) {
+				t.Errorf("expected the namespace to be deleted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_Reconcile_waitingForClusterNamespace(t *testing.T) {
+	testscheme := scheme.Scheme
+
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Namespace{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "mycluster"}})
+	if err != nil {
+		t.Errorf("ReconcileManagedCluster.Reconcile() error = %v", err)
+		return
+	}
+	if !got.Requeue {
+		t.Errorf("ReconcileManagedCluster.Reconcile() expected a requeue")
+	}
+
+	gotCluster := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, gotCluster); err != nil {
+		t.Error(err)
+		return
+	}
+	c := meta.FindStatusCondition(gotCluster.Status.Conditions, ManagedClusterImportSucceeded)
+	if c == nil || c.Reason != reasonWaitingForClusterNamespace {
+		t.Errorf("expected the %s reason, got %v", reasonWaitingForClusterNamespace, c)
+		return
+	}
+
+	if err := r.clearConditionImportWaiting(gotCluster, reasonWaitingForClusterNamespace); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, gotCluster); err != nil {
+		t.Error(err)
+		return
+	}
+	if c := meta.FindStatusCondition(gotCluster.Status.Conditions, ManagedClusterImportSucceeded); c != nil {
+		t.Errorf("expected the condition to be removed, got %v", c)
+	}
+}
+
+// TestReconcileManagedCluster_Reconcile_concurrent reconciles several clusters in parallel,
+// run it with -race to detect a shared state between reconciles
+func TestReconcileManagedCluster_Reconcile_concurrent(t *testing.T) {
+	os.Setenv("DEFAULT_IMAGE_PULL_SECRET", imagePullSecretNameReconcile)
+	os.Setenv("POD_NAMESPACE", managedClusterNameReconcile)
+
+	testscheme := scheme.Scheme
+
+	testscheme.AddKnownTypes(hivev1.SchemeGroupVersion, &hivev1.ClusterDeployment{})
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWorkList{})
+	testscheme.AddKnownTypes(ocinfrav1.SchemeGroupVersion, &ocinfrav1.Infrastructure{}, &ocinfrav1.APIServer{})
+	testscheme.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Namespace{})
+
+	objs := []runtime.Object{
+		newFakeImagePullSecret(),
+		&ocinfrav1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster",
+			},
+			Status: ocinfrav1.InfrastructureStatus{
+				APIServerURL: "http://127.0.0.1:6443",
+			},
+		},
+	}
+	const nbClusters = 5
+	names := make([]string, nbClusters)
+	for i := range names {
+		names[i] = fmt.Sprintf("cluster-concurrent-%d", i)
+		managedCluster := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: names[i],
+			},
+		}
+		serviceAccount, err := newBootstrapServiceAccount(managedCluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokenSecret, err := serviceAccountTokenSecret(serviceAccount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		serviceAccount.Secrets = []corev1.ObjectReference{{Name: tokenSecret.Name}}
+		objs = append(objs,
+			managedCluster,
+			&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: names[i],
+				},
+			},
+			serviceAccount,
+			tokenSecret,
+		)
+	}
+
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, objs...),
+		scheme: testscheme,
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+				t.Errorf("ReconcileManagedCluster.Reconcile() %s error = %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	//Each cluster must get its own import secret
+	for _, name := range names {
+		importSecret := &corev1.Secret{}
+		if err := r.client.Get(context.TODO(),
+			types.NamespacedName{Name: name + importSecretNamePostfix, Namespace: name},
+			importSecret); err != nil {
+			t.Errorf("import secret of %s not found: %v", name, err)
+			continue
+		}
+		if !strings.Contains(string(importSecret.Data["import.yaml"]), name) {
+			t.Errorf("import secret of %s doesn't contain its cluster name", name)
+		}
+	}
+}
+
+func Test_newImportSecretPredicate(t *testing.T) {
+	newSecret := func(name string, data string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "mycluster",
+			},
+			Data: map[string][]byte{
+				importYAMLKey: []byte(data),
+			},
+		}
+	}
+	importSecret := newSecret("mycluster"+importSecretNamePostfix, "a")
+	otherSecret := newSecret("other", "a")
+	p := newImportSecretPredicate()
+
+	if p.Create(event.CreateEvent{Meta: importSecret, Object: importSecret}) {
+		t.Errorf("expected the creation of the import secret to be ignored")
+	}
+	if !p.Delete(event.DeleteEvent{Meta: importSecret, Object: importSecret}) {
+		t.Errorf("expected the deletion of the import secret to be processed")
+	}
+	if p.Delete(event.DeleteEvent{Meta: otherSecret, Object: otherSecret}) {
+		t.Errorf("expected the deletion of another secret to be ignored")
+	}
+	modifiedImportSecret := newSecret("mycluster"+importSecretNamePostfix, "b")
+	if !p.Update(event.UpdateEvent{
+		MetaOld: importSecret, ObjectOld: importSecret,
+		MetaNew: modifiedImportSecret, ObjectNew: modifiedImportSecret}) {
+		t.Errorf("expected the modification of the import secret to be processed")
+	}
+	if p.Update(event.UpdateEvent{
+		MetaOld: importSecret, ObjectOld: importSecret,
+		MetaNew: importSecret, ObjectNew: importSecret}) {
+		t.Errorf("expected an update without data change to be ignored")
+	}
+	modifiedOtherSecret := newSecret("other", "b")
+	if p.Update(event.UpdateEvent{
+		MetaOld: otherSecret, ObjectOld: otherSecret,
+		MetaNew: modifiedOtherSecret, ObjectNew: modifiedOtherSecret}) {
+		t.Errorf("expected the modification of another secret to be ignored")
+	}
+	dedicatedSecret := newSecret("mycluster"+importSecretNamePostfix, "a")
+	dedicatedSecret.Namespace = "import-secrets"
+	dedicatedSecret.Labels = map[string]string{clusterLabel: "mycluster"}
+	if !p.Delete(event.DeleteEvent{Meta: dedicatedSecret, Object: dedicatedSecret}) {
+		t.Errorf("expected the deletion of the import secret in a dedicated namespace to be processed")
+	}
+}
+
+//fakeAPIReader records the objects read through it, it stands for the uncached reader of the manager
+type fakeAPIReader struct {
+	client.Reader
+	gets []runtime.Object
+	err  error
+}
+
+func (r *fakeAPIReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	r.gets = append(r.gets, obj)
+	if r.err != nil {
+		return r.err
+	}
+	return r.Reader.Get(ctx, key, obj)
+}
+
+func Test_customClient_routing(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "test-namespace",
+		},
+	}
+	configmap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-configmap",
+			Namespace: "test-namespace",
+		},
+	}
+	secretKey := types.NamespacedName{Name: "test-secret", Namespace: "test-namespace"}
+	configmapKey := types.NamespacedName{Name: "test-configmap", Namespace: "test-namespace"}
+
+	t.Run("secret not yet in the cache is read from the apiserver", func(t *testing.T) {
+		reader := &fakeAPIReader{Reader: fake.NewFakeClient(secret)}
+		c := newCustomClient(fake.NewFakeClient(), reader)
+		if err := c.Get(context.TODO(), secretKey, &corev1.Secret{}); err != nil {
+			t.Errorf("custom client Get() got %v but wanted nil", err)
+		}
+		if len(reader.gets) != 1 {
+			t.Errorf("expected 1 read through the api reader, got %d", len(reader.gets))
+		}
+	})
+	t.Run("api reader error is not hidden by the cache", func(t *testing.T) {
+		reader := &fakeAPIReader{Reader: fake.NewFakeClient(secret), err: fmt.Errorf("apiserver unavailable")}
+		c := newCustomClient(fake.NewFakeClient(secret), reader)
+		if err := c.Get(context.TODO(), secretKey, &corev1.Secret{}); err == nil || err.Error() != "apiserver unavailable" {
+			t.Errorf("custom client Get() got %v but wanted the api reader error", err)
+		}
+	})
+	t.Run("configmap not in the cache is read from the apiserver", func(t *testing.T) {
+		reader := &fakeAPIReader{Reader: fake.NewFakeClient(configmap)}
+		c := newCustomClient(fake.NewFakeClient(), reader)
+		if err := c.Get(context.TODO(), configmapKey, &corev1.ConfigMap{}); err != nil {
+			t.Errorf("custom client Get() got %v but wanted nil", err)
+		}
+		if len(reader.gets) != 1 {
+			t.Errorf("expected 1 read through the api reader, got %d", len(reader.gets))
+		}
+	})
+	t.Run("other objects are not read from the apiserver", func(t *testing.T) {
+		reader := &fakeAPIReader{Reader: fake.NewFakeClient(configmap)}
+		c := newCustomClient(fake.NewFakeClient(configmap), reader)
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: "test-namespace"}, &corev1.Namespace{}); !errors.IsNotFound(err) {
+			t.Errorf("custom client Get() got %v but wanted not found from the cache", err)
+		}
+		if err := c.List(context.TODO(), &corev1.SecretList{}); err != nil {
+			t.Errorf("custom client List() got %v but wanted nil", err)
+		}
+		if len(reader.gets) != 0 {
+			t.Errorf("expected no read through the api reader, got %d", len(reader.gets))
+		}
+	})
+}
+
 func Test_newCustomClient(t *testing.T) {
 	secretA := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -804,3 +1132,35 @@ func Test_newCustomClient(t *testing.T) {
 	})
 
 }
+
+//newImportObjects returns the ManagedCluster and the hub objects its import needs: the cluster namespace,
+//the bootstrap service account with its token, the image pull secret and the infrastructure config
+func newImportObjects(t *testing.T, managedCluster *clusterv1.ManagedCluster) []runtime.Object {
+	os.Setenv("DEFAULT_IMAGE_PULL_SECRET", imagePullSecretNameReconcile)
+	os.Setenv("POD_NAMESPACE", managedClusterNameReconcile)
+	scheme.Scheme.AddKnownTypes(ocinfrav1.SchemeGroupVersion, &ocinfrav1.Infrastructure{}, &ocinfrav1.APIServer{})
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: managedCluster.Name,
+		},
+	}
+	infraConfig := &ocinfrav1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Status: ocinfrav1.InfrastructureStatus{
+			APIServerURL: "http://127.0.0.1:6443",
+		},
+	}
+	sa, err := newBootstrapServiceAccount(managedCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenSecret, err := serviceAccountTokenSecret(sa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sa.Secrets = []corev1.ObjectReference{{Name: tokenSecret.Name}}
+	return []runtime.Object{managedCluster, ns, sa, tokenSecret, newFakeImagePullSecret(), infraConfig}
+}
@@ -4,12 +4,17 @@ package managedcluster
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"strconv"
+	"net/http"
 	"time"
 
+	"github.com/ghodss/yaml"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog"
 
@@ -26,6 +31,98 @@ import (
 	"github.com/open-cluster-management/applier/pkg/templateprocessor"
 )
 
+const autoImportSecretExecKey = "exec"
+
+const reasonExecPluginMisconfigured = "ExecPluginMisconfigured"
+
+//autoImportSecretServerNameKey is the TLS server name to validate the managed cluster certificate against,
+//for endpoints behind a SNI router
+const autoImportSecretServerNameKey = "serverName"
+
+//autoImportSecretDialServerKey is the URL the controller dials to reach the managed cluster API server,
+//for clusters only reachable through a tunnel. The server of the auto-import-secret (or of its kubeconfig)
+//stays the real endpoint of the cluster, its host is the default TLS server name.
+const autoImportSecretDialServerKey = "dialServer"
+
+//tlsServerNameAnnotation is the TLS server name used when the auto-import-secret doesn't define one
+const tlsServerNameAnnotation = "import.open-cluster-management.io/tls-server-name"
+
+const reasonTLSServerNameMismatch = "TLSServerNameMismatch"
+
+var errInvalidExecConfig = fmt.Errorf("%w: invalid exec configuration in auto-import-secret", ErrInvalidSecret)
+
+//The keys of the auto-import-secret for a client certificate authentication, as in a kubeconfig
+const (
+	autoImportSecretClientCertificateKey = "client-certificate-data"
+	autoImportSecretClientKeyKey         = "client-key-data"
+	//autoImportSecretCAKey is the CA of the managed cluster API server, for the server based auto-import-secrets
+	autoImportSecretCAKey = "certificate-authority-data"
+)
+
+const reasonClientCertificateMismatch = "ClientCertificateMismatch"
+
+var errClientCertificateMismatch = fmt.Errorf("%w: the client certificate and key of the auto-import-secret don't match",
+	ErrInvalidSecret)
+
+//importErrorReason returns the condition reason for an import error
+func importErrorReason(err error) string {
+	if errors.Is(err, errCredentialRefreshFailed) {
+		return reasonCredentialRefreshFailed
+	}
+	if errors.Is(err, errInvalidExecConfig) {
+		return reasonExecPluginMisconfigured
+	}
+	if errors.Is(err, errInvalidRequestHeaders) {
+		return reasonInvalidRequestHeaders
+	}
+	if errors.Is(err, errClientCertificateMismatch) {
+		return reasonClientCertificateMismatch
+	}
+	if isKubeconfigContextNotFound(err) {
+		return reasonKubeconfigContextNotFound
+	}
+	if reason := configErrorReason(err); reason != "" {
+		return reason
+	}
+	if isInvalidImportYAMLs(err) {
+		return reasonInvalidImportYAMLs
+	}
+	if isKlusterletNotReady(err) {
+		return reasonKlusterletNotReady
+	}
+	if isInvalidBootstrapServiceAccount(err) {
+		return reasonInvalidBootstrapServiceAccount
+	}
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &hostnameErr) {
+		return reasonTLSServerNameMismatch
+	}
+	if errors.Is(err, errClockSkewDetected) {
+		return reasonClockSkewDetected
+	}
+	switch {
+	case errors.Is(err, ErrClusterNotInstalled):
+		return reasonClusterNotInstalled
+	case errors.Is(err, ErrInvalidSecret):
+		return reasonInvalidImportSecret
+	case errors.Is(err, ErrUnreachable):
+		return reasonManagedClusterUnreachable
+	case errors.Is(err, ErrConnectionFailed):
+		return reasonConnectionFailed
+	case errors.Is(err, ErrTransientApply):
+		return reasonTransientApplyError
+	case errors.Is(err, ErrInvalidManifests):
+		return reasonInvalidImportManifests
+	}
+	return reasonManagedClusterNotImported
+}
+
+//supportedExecAPIVersions are the client.authentication.k8s.io versions supported by client-go
+var supportedExecAPIVersions = []string{
+	"client.authentication.k8s.io/v1alpha1",
+	"client.authentication.k8s.io/v1beta1",
+}
+
 func (r *ReconcileManagedCluster) importCluster(
 	managedCluster *clusterv1.ManagedCluster,
 	clusterDeployment *hivev1.ClusterDeployment,
@@ -38,18 +135,19 @@ func (r *ReconcileManagedCluster) importCluster(
 	//A clusterDeployment exist then get the client
 	if clusterDeployment != nil {
 		if !clusterDeployment.Spec.Installed {
-			klog.Infof("cluster %s not yet installed", clusterDeployment.Name)
+			logSubsystemImport.V(0).Infof(correlated(managedCluster.Name, "cluster %s not yet installed"), clusterDeployment.Name)
 			return reconcile.Result{Requeue: true, RequeueAfter: 1 * time.Minute},
-				nil
+				newImportError(ErrClusterNotInstalled, fmt.Errorf("the ClusterDeployment %s is not installed yet", clusterDeployment.Name))
 		}
-		klog.Infof("Use hive client to import cluster %s", managedCluster.Name)
+		logSubsystemImport.V(0).Infof(correlated(managedCluster.Name, "Use hive client to import cluster %s"),
+			managedCluster.Name)
 		client, err = r.getManagedClusterClientFromHive(clusterDeployment, managedCluster)
 		if err != nil {
-			return reconcile.Result{}, err
+			return reconcile.Result{}, classifyImportError(err)
 		}
 		//Testing to avoid update which will generate roundtrip as the clusterDeployment is watched
 		if !libgometav1.HasFinalizer(clusterDeployment, managedClusterFinalizer) {
-			klog.Info("Add finalizer in clusterDeployment")
+			logSubsystemImport.V(0).Info(correlated(managedCluster.Name, "Add finalizer in clusterDeployment"))
 			libgometav1.AddFinalizer(clusterDeployment, managedClusterFinalizer)
 			err = r.client.Update(context.TODO(), clusterDeployment)
 			if err != nil {
@@ -60,19 +158,36 @@ func (r *ReconcileManagedCluster) importCluster(
 
 	//Check if auto-import and get client from the importSecret
 	if autoImportSecret != nil {
-		klog.Infof("Use autoImportSecret to import cluster %s", managedCluster.Name)
-		client, err = r.getManagedClusterClientFromAutoImportSecret(autoImportSecret)
+		logSubsystemImport.V(0).Infof(correlated(managedCluster.Name, "Use autoImportSecret to import cluster %s"),
+			managedCluster.Name)
+		client, err = r.getManagedClusterClientFromAutoImportSecret(managedCluster, autoImportSecret)
 	}
 
 	if err == nil {
 		res, err = r.importClusterWithClient(managedCluster, autoImportSecret, client)
 	}
 	if err != nil && autoImportSecret != nil {
+		res, err = r.retryImportWithRefreshedCredentials(managedCluster, autoImportSecret, res, err)
+	}
+	//A transient failure doesn't consume a retry of the auto-import
+	if err != nil && autoImportSecret != nil && !isTransientApplyError(err) {
 		errUpdate := r.updateAutoImportRetry(managedCluster, autoImportSecret)
 		if errUpdate != nil {
 			return res, errUpdate
 		}
 	}
+	//The klusterlet of a successful auto-import may still be starting, the import is not done yet
+	if err == nil && autoImportSecret != nil && !res.Requeue {
+		if errCond := r.clearConditionAutoImportRetries(managedCluster); errCond != nil {
+			return res, errCond
+		}
+	}
+	if delay := importVerificationDelay(); err == nil && !res.Requeue && delay > 0 {
+		pendingImportVerifications.schedule(managedCluster.Name, client, time.Now().Add(delay))
+		res.RequeueAfter = delay
+	}
+	//A large clock skew makes the authentication fail, report it rather than the failure
+	err = checkClockSkew(managedCluster, client, classifyImportError(err))
 
 	return res, err
 
@@ -92,75 +207,249 @@ func (r *ReconcileManagedCluster) getManagedClusterClientFromHive(
 		return nil, err
 	}
 
-	return getClientFromKubeConfig(managedClusterKubeSecret.Data["kubeconfig"])
+	return getClientFromKubeConfig(managedClusterKubeSecret.Data["kubeconfig"], newConfigOverrides(managedCluster, nil), nil)
 
 }
 
+//newConfigOverrides returns the overrides of the managed cluster client configuration,
+//the TLS server name of the auto-import-secret takes precedence over the annotation.
+//If the auto-import-secret has a dial server, the client connects to it and, without explicit
+//TLS server name, validates the certificate against the host of the server key.
+func newConfigOverrides(managedCluster *clusterv1.ManagedCluster, autoImportSecret *corev1.Secret) *clientcmd.ConfigOverrides {
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.ClusterInfo.TLSServerName = managedCluster.GetAnnotations()[tlsServerNameAnnotation]
+	if autoImportSecret != nil {
+		if serverName := autoImportSecret.Data[autoImportSecretServerNameKey]; len(serverName) != 0 {
+			overrides.ClusterInfo.TLSServerName = string(serverName)
+		}
+		if dialServer := autoImportSecret.Data[autoImportSecretDialServerKey]; len(dialServer) != 0 {
+			overrides.ClusterInfo.Server = string(dialServer)
+			if overrides.ClusterInfo.TLSServerName == "" {
+				overrides.ClusterInfo.TLSServerName = serverHostname(string(autoImportSecret.Data["server"]))
+			}
+		}
+	}
+	return overrides
+}
+
+//serverHostname returns the host of a server URL without port and brackets, empty if the URL is invalid
+func serverHostname(server string) string {
+	u, err := parseServerURL(withDefaultScheme(server))
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+//kubeconfigServer returns the server of the cluster of the context of the kubeconfig, the current
+//context if contextName is empty
+func kubeconfigServer(config *clientcmdapi.Config, contextName string) string {
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+	currentContext, ok := config.Contexts[contextName]
+	if !ok {
+		return ""
+	}
+	cluster, ok := config.Clusters[currentContext.Cluster]
+	if !ok {
+		return ""
+	}
+	return cluster.Server
+}
+
 //Get the client from the auto-import-secret
 func (r *ReconcileManagedCluster) getManagedClusterClientFromAutoImportSecret(
+	managedCluster *clusterv1.ManagedCluster,
 	autoImportSecret *corev1.Secret) (client.Client, error) {
+	overrides := newConfigOverrides(managedCluster, autoImportSecret)
+	insecure, err := r.checkInsecureSkipTLSVerify(managedCluster, autoImportSecret)
+	if err != nil {
+		return nil, err
+	}
+	overrides.ClusterInfo.InsecureSkipTLSVerify = insecure
+	headers, err := getAutoImportSecretHeaders(autoImportSecret)
+	if err != nil {
+		return nil, err
+	}
 	//generate client using kubeconfig
 	if k, ok := autoImportSecret.Data["kubeconfig"]; ok {
-		return getClientFromKubeConfig(k)
+		overrides.CurrentContext = string(autoImportSecret.Data[autoImportSecretContextKey])
+		return getClientFromKubeConfig(k, overrides, headers)
+	}
+	if ca := autoImportSecret.Data[autoImportSecretCAKey]; len(ca) != 0 {
+		overrides.ClusterInfo.CertificateAuthorityData = ca
 	}
 	token, tok := autoImportSecret.Data["token"]
 	server, sok := autoImportSecret.Data["server"]
 	if tok && sok {
-		return getClientFromToken(string(token), string(server))
+		return getClientFromToken(string(token), string(server), overrides, headers)
+	}
+	exec, eok := autoImportSecret.Data[autoImportSecretExecKey]
+	if eok && sok {
+		return getClientFromExec(exec, string(server), overrides, headers)
+	}
+	cert, cok := autoImportSecret.Data[autoImportSecretClientCertificateKey]
+	key, kok := autoImportSecret.Data[autoImportSecretClientKeyKey]
+	if cok && kok && sok {
+		return getClientFromClientCertificate(cert, key, string(server), overrides, headers)
+	}
+
+	return nil, newImportError(ErrInvalidSecret,
+		fmt.Errorf("kubeconfig, token and server, exec and server or client certificate, key and server are missing"))
+}
+
+//Create client from kubeconfig, the headers are added to its requests
+func getClientFromKubeConfig(kubeconfig []byte, overrides *clientcmd.ConfigOverrides, headers http.Header) (client.Client, error) {
+	rconfig, err := newRestConfigFromKubeConfig(kubeconfig, overrides)
+	if err != nil {
+		return nil, err
 	}
+	addRequestHeaders(rconfig, headers)
 
-	return nil, fmt.Errorf("kubeconfig or token and server are missing")
+	return newManagedClusterClient(rconfig)
 }
 
-//Create client from kubeconfig
-func getClientFromKubeConfig(kubeconfig []byte) (client.Client, error) {
+//Create rest config from kubeconfig
+func newRestConfigFromKubeConfig(kubeconfig []byte, overrides *clientcmd.ConfigOverrides) (*rest.Config, error) {
 	config, err := clientcmd.Load(kubeconfig)
 	if err != nil {
+		return nil, newImportError(ErrInvalidSecret, err)
+	}
+	if err := validateKubeconfigContext(config, overrides); err != nil {
 		return nil, err
 	}
+	server := kubeconfigServer(config, overrides.CurrentContext)
+	if err := validateServerURLs(server, overrides.ClusterInfo.Server); err != nil {
+		return nil, newImportError(ErrInvalidSecret, err)
+	}
+
+	//Dialing a tunnel, the certificate is still the one of the server of the kubeconfig
+	if overrides.ClusterInfo.Server != "" && overrides.ClusterInfo.TLSServerName == "" {
+		tunnelOverrides := *overrides
+		tunnelOverrides.ClusterInfo.TLSServerName = serverHostname(server)
+		overrides = &tunnelOverrides
+	}
 
 	rconfig, err := clientcmd.NewDefaultClientConfig(
 		*config,
-		&clientcmd.ConfigOverrides{}).ClientConfig()
+		overrides).ClientConfig()
+	if err != nil {
+		return nil, newImportError(ErrInvalidSecret, err)
+	}
+	return rconfig, nil
+}
+
+//Create client from token and server
+func getClientFromToken(token, server string, overrides *clientcmd.ConfigOverrides, headers http.Header) (client.Client, error) {
+	return getClientFromServerAndAuth(server, &clientcmdapi.AuthInfo{
+		Token: token,
+	}, overrides, headers)
+}
+
+//Create client from a client certificate, key and server
+func getClientFromClientCertificate(
+	cert, key []byte,
+	server string,
+	overrides *clientcmd.ConfigOverrides,
+	headers http.Header) (client.Client, error) {
+	authInfo, err := newClientCertificateAuthInfo(cert, key)
 	if err != nil {
 		return nil, err
 	}
+	return getClientFromServerAndAuth(server, authInfo, overrides, headers)
+}
+
+//newClientCertificateAuthInfo returns the auth info of a client certificate, it fails if the
+//certificate and the key are not a pair
+func newClientCertificateAuthInfo(cert, key []byte) (*clientcmdapi.AuthInfo, error) {
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return nil, fmt.Errorf("%w: %s", errClientCertificateMismatch, err.Error())
+	}
+	return &clientcmdapi.AuthInfo{
+		ClientCertificateData: cert,
+		ClientKeyData:         key,
+	}, nil
+}
 
-	client, err := client.New(rconfig, client.Options{})
+//Create client from an exec credential plugin configuration and server
+func getClientFromExec(execData []byte, server string, overrides *clientcmd.ConfigOverrides, headers http.Header) (client.Client, error) {
+	execConfig, err := parseExecConfig(execData)
 	if err != nil {
 		return nil, err
 	}
+	return getClientFromServerAndAuth(server, &clientcmdapi.AuthInfo{
+		Exec: execConfig,
+	}, overrides, headers)
+}
+
+//parseExecConfig reads and validates the exec credential plugin configuration (command, args, env, apiVersion)
+func parseExecConfig(execData []byte) (*clientcmdapi.ExecConfig, error) {
+	execConfig := &clientcmdapi.ExecConfig{}
+	if err := yaml.Unmarshal(execData, execConfig); err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidExecConfig, err.Error())
+	}
+	if execConfig.Command == "" {
+		return nil, fmt.Errorf("%w: command is missing", errInvalidExecConfig)
+	}
+	if execConfig.APIVersion == "" {
+		execConfig.APIVersion = supportedExecAPIVersions[len(supportedExecAPIVersions)-1]
+	}
+	supported := false
+	for _, v := range supportedExecAPIVersions {
+		if execConfig.APIVersion == v {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, fmt.Errorf("%w: apiVersion %s is not supported", errInvalidExecConfig, execConfig.APIVersion)
+	}
+	for _, env := range execConfig.Env {
+		if env.Name == "" {
+			return nil, fmt.Errorf("%w: env entry without name", errInvalidExecConfig)
+		}
+	}
+	return execConfig, nil
+}
 
-	return client, nil
+//Create client from server and auth info, the headers are added to its requests
+func getClientFromServerAndAuth(
+	server string,
+	authInfo *clientcmdapi.AuthInfo,
+	overrides *clientcmd.ConfigOverrides,
+	headers http.Header) (client.Client, error) {
+	restConfig, err := newRestConfigFromServerAndAuth(server, authInfo, overrides)
+	if err != nil {
+		return nil, err
+	}
+	addRequestHeaders(restConfig, headers)
+	return newManagedClusterClient(restConfig)
 }
 
-//Create client from token and server
-func getClientFromToken(token, server string) (client.Client, error) {
+//Create rest config from server and auth info
+func newRestConfigFromServerAndAuth(
+	server string,
+	authInfo *clientcmdapi.AuthInfo,
+	overrides *clientcmd.ConfigOverrides) (*rest.Config, error) {
+	if err := validateServerURLs(server, overrides.ClusterInfo.Server); err != nil {
+		return nil, newImportError(ErrInvalidSecret, err)
+	}
 	//Create config
 	config := clientcmdapi.NewConfig()
 	config.Clusters["default"] = &clientcmdapi.Cluster{
-		Server:                server,
+		Server:                withDefaultScheme(server),
 		InsecureSkipTLSVerify: true,
 	}
-	config.AuthInfos["default"] = &clientcmdapi.AuthInfo{
-		Token: token,
-	}
+	config.AuthInfos["default"] = authInfo
 	config.Contexts["default"] = &clientcmdapi.Context{
 		Cluster:  "default",
 		AuthInfo: "default",
 	}
 	config.CurrentContext = "default"
 
-	clientConfig := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{})
-	restConfig, err := clientConfig.ClientConfig()
-	if err != nil {
-		return nil, err
-	}
-	clientClient, err := client.New(restConfig, client.Options{})
-	if err != nil {
-		return nil, err
-	}
-	return clientClient, nil
+	clientConfig := clientcmd.NewDefaultClientConfig(*config, overrides)
+	return clientConfig.ClientConfig()
 }
 
 func (r *ReconcileManagedCluster) updateAutoImportRetry(
@@ -168,27 +457,30 @@ func (r *ReconcileManagedCluster) updateAutoImportRetry(
 	autoImportSecret *corev1.Secret) error {
 	if autoImportSecret != nil {
 		//Decrement the autoImportRetry
-		autoImportRetry, err := strconv.Atoi(string(autoImportSecret.Data[autoImportRetryName]))
+		autoImportRetry, err := getAutoImportRetry(autoImportSecret)
 		if err != nil {
 			return err
 		}
-		klog.Infof("Retry left to import %s: %d", managedCluster.Name, autoImportRetry)
+		logSubsystemImport.V(0).Infof(correlated(managedCluster.Name, "Retry left to import %s: %d"),
+			managedCluster.Name, autoImportRetry)
+		total := getAutoImportRetryTotal(autoImportSecret, autoImportRetry)
 		autoImportRetry--
 		//Remove if negatif as a label can not start with "-", should start by a char
 		if autoImportRetry < 0 {
-			err = r.client.Delete(context.TODO(), autoImportSecret)
+			err = r.consumeAutoImportSecret(autoImportSecret)
 			if err != nil {
 				return err
 			}
 			autoImportSecret = nil
 		} else {
-			v := []byte(strconv.Itoa(autoImportRetry))
-			autoImportSecret.Data[autoImportRetryName] = v
+			setAutoImportRetry(autoImportSecret, autoImportRetry)
+			setAutoImportRetryTotal(autoImportSecret, total)
 			err := r.client.Update(context.TODO(), autoImportSecret)
 			if err != nil {
 				return err
 			}
 		}
+		return r.setCondition(managedCluster, newAutoImportRetriesCondition(autoImportRetry, total))
 	}
 	return nil
 }
@@ -199,20 +491,79 @@ func (r *ReconcileManagedCluster) importClusterWithClient(
 	autoImportSecret *corev1.Secret,
 	managedClusterClient client.Client) (reconcile.Result, error) {
 
-	klog.Infof("Importing cluster: %s", managedCluster.Name)
+	logSubsystemImport.V(0).Infof(correlated(managedCluster.Name, "Importing cluster: %s"), managedCluster.Name)
+
+	agentNamespace, err := getKlusterletNamespace(managedCluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.checkDuplicateClusterImport(managedCluster, managedClusterClient); err != nil {
+		clusterLogger(managedCluster.Name).Error(err, "Failed to check the duplicate imports")
+	}
+	if err := r.recordRemoteKubeVersion(managedCluster, managedClusterClient); err != nil {
+		clusterLogger(managedCluster.Name).Error(err, "Failed to record the kube version of the managed cluster")
+	}
+
+	//The klusterlet is already applied, only its readiness is checked until it's ready or the deadline
+	if autoImportSecret == nil || !pendingKlusterletReadiness.isPending(managedCluster.Name) {
+		if res, err := r.applyKlusterlet(managedCluster, managedClusterClient, agentNamespace); err != nil {
+			return res, err
+		}
+	}
+
+	//Confirm the klusterlet started before the auto-import is considered successful, the deployment is
+	//checked once per reconcile so a slow cluster doesn't hold the reconciles of the others
+	if timeout := klusterletReadyTimeout(); autoImportSecret != nil && timeout > 0 {
+		now := time.Now()
+		deadline := pendingKlusterletReadiness.start(managedCluster.Name, now, timeout)
+		ready, err := checkKlusterletReady(managedClusterClient, agentNamespace, timeout, deadline, now)
+		if err != nil {
+			pendingKlusterletReadiness.forget(managedCluster.Name)
+			return reconcile.Result{Requeue: true, RequeueAfter: klusterletNotReadyRequeueAfter}, err
+		}
+		if !ready {
+			if errCond := r.setConditionWaitingForKlusterletReady(managedCluster, agentNamespace); errCond != nil {
+				klog.Error(errCond)
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: klusterletReadyPollInterval}, nil
+		}
+		pendingKlusterletReadiness.forget(managedCluster.Name)
+	}
+
+	//Succeeded do not retry, then remove the autoImportRetryLabel
+	if autoImportSecret != nil {
+		if err := r.consumeAutoImportSecret(autoImportSecret); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	klog.Infof(correlated(managedCluster.Name, "Successfully imported %s"), managedCluster.Name)
+	return reconcile.Result{}, nil
+}
 
+//applyKlusterlet applies the klusterlet crds and yamls on the managed cluster
+func (r *ReconcileManagedCluster) applyKlusterlet(
+	managedCluster *clusterv1.ManagedCluster,
+	managedClusterClient client.Client,
+	agentNamespace string) (reconcile.Result, error) {
 	//Do not create SA if already exists
 	excluded := make([]string, 0)
 	sa := &corev1.ServiceAccount{}
 	if err := managedClusterClient.Get(context.TODO(),
 		types.NamespacedName{
 			Name:      "klusterlet",
-			Namespace: klusterletNamespace,
+			Namespace: agentNamespace,
 		}, sa); err == nil {
 		excluded = append(excluded, "klusterlet/service_account.yaml")
 	}
+	//Do not update the create-only manifests if already exist
+	createOnly, err := getExistingCreateOnlyManifests(managedClusterClient, managedCluster, createOnlyManifests())
+	if err != nil {
+		return reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}, err
+	}
+	excluded = append(excluded, createOnly...)
 	//Generate crds and yamls
-	crds, yamls, err := generateImportYAMLs(r.client, managedCluster, excluded)
+	crds, yamls, err := generateImportYAMLs(r.client, r.kubeClient, managedCluster, excluded)
 	if err != nil {
 		return reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}, err
 	}
@@ -238,7 +589,7 @@ func (r *ReconcileManagedCluster) importClusterWithClient(
 	//Create the crds resources
 	err = a.CreateOrUpdateInPath(".", nil, false, nil)
 	if err != nil {
-		return reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}, err
+		return reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}, classifyApplyError(err)
 	}
 
 	//Convert yamls to yaml
@@ -263,29 +614,55 @@ func (r *ReconcileManagedCluster) importClusterWithClient(
 	//Create the yamls resources
 	err = a.CreateOrUpdateInPath(".", excluded, false, nil)
 	if err != nil {
-		return reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}, err
+		return reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}, classifyApplyError(err)
 	}
 
-	//Succeeded do not retry, then remove the autoImportRetryLabel
-	if autoImportSecret != nil {
-		if err := r.client.Delete(context.TODO(), autoImportSecret); err != nil {
-			return reconcile.Result{}, err
-		}
-	}
-	klog.Infof("Successfully imported %s", managedCluster.Name)
 	return reconcile.Result{}, nil
 }
 
-func (r *ReconcileManagedCluster) managedClusterDeletion(instance *clusterv1.ManagedCluster) (reconcile.Result, error) {
-	reqLogger := log.WithValues("Instance.Namespace", instance.Namespace, "Instance.Name", instance.Name)
+func (r *ReconcileManagedCluster) managedClusterDeletion(
+	instance *clusterv1.ManagedCluster,
+) (result reconcile.Result, err error) {
+	reqLogger := log.WithValues("Instance.Namespace", instance.Namespace, "Instance.Name", instance.Name,
+		"correlationID", correlationID(instance.Name))
 	reqLogger.Info(fmt.Sprintf("Instance in Terminating: %s", instance.Name))
-	if len(filterFinalizers(instance, []string{managedClusterFinalizer, registrationFinalizer})) != 0 {
+	pendingImportVerifications.forget(instance.Name)
+	pendingKlusterletReadiness.forget(instance.Name)
+	klusterletUpgrades.forget(instance.Name)
+	hubManifestsToRetry.forget(instance.Name)
+	forgetManifestWorkConflicts(instance.Name)
+	order := finalizerOrder()
+	if isCleanedUp(instance, order) {
+		deletions.release(instance.Name)
+		return reconcile.Result{}, nil
+	}
+	if finalizerGraceTimeoutExceeded(instance, finalizerGraceTimeout(), time.Now()) {
+		res, err := r.forceRemoveFinalizer(instance)
+		if err == nil {
+			deletions.release(instance.Name)
+		}
+		return res, err
+	}
+	if len(waitedFinalizers(instance, order)) != 0 {
 		return reconcile.Result{Requeue: true, RequeueAfter: 1 * time.Minute}, nil
 	}
 
+	//Bound the cleanups running at the same time when a whole fleet is deleted, the slot is held
+	//until the finalizer is removed so the cleanups waiting on the managed cluster count too.
+	//A failed cleanup is retried with the backoff of the controller, it gives its slot back meanwhile.
+	if !deletions.acquire(instance.Name, maxConcurrentDeletions(), time.Now()) {
+		reqLogger.Info("Too many clusters being deleted, waiting for a slot")
+		return reconcile.Result{RequeueAfter: throttledRequeueAfter()}, nil
+	}
+	defer func() {
+		if err != nil {
+			deletions.release(instance.Name)
+		}
+	}()
+
 	offLine := checkOffLine(instance)
 	reqLogger.Info(fmt.Sprintf("deleteAllOtherManifestWork: %s", instance.Name))
-	err := deleteAllOtherManifestWork(r.client, instance)
+	err = deleteAllOtherManifestWork(r.client, instance)
 	if err != nil {
 		if !offLine {
 			return reconcile.Result{}, err
@@ -300,27 +677,57 @@ func (r *ReconcileManagedCluster) managedClusterDeletion(instance *clusterv1.Man
 		}
 	}
 
-	reqLogger.Info(fmt.Sprintf("deleteKlusterletManifestWorks: %s", instance.Name))
-	err = deleteKlusterletManifestWorks(r.client, instance)
-	if err != nil {
-		return reconcile.Result{}, err
+	//The other manifestworks must be removed from the managed cluster before the klusterlet is removed,
+	//as the klusterlet work agent is the one cleaning them on the managed cluster
+	if !offLine {
+		remaining, err := listAllOtherManifestWork(r.client, instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(remaining) != 0 {
+			reqLogger.Info("Waiting for the manifestworks to be deleted", "manifestworks", remaining)
+			return reconcile.Result{Requeue: true, RequeueAfter: manifestWorkDeletionRequeueAfter}, nil
+		}
 	}
 
-	if !offLine {
-		return reconcile.Result{Requeue: true, RequeueAfter: 1 * time.Minute}, nil
+	//The klusterlet provisioned externally is not removed by the controller
+	if !isKlusterletProvisionedExternally(instance) {
+		reqLogger.Info(fmt.Sprintf("deleteKlusterletManifestWorks: %s", instance.Name))
+		err = deleteKlusterletManifestWorks(r.client, instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if !offLine {
+			return reconcile.Result{Requeue: true, RequeueAfter: 1 * time.Minute}, nil
+		}
+
+		reqLogger.Info(fmt.Sprintf("evictKlusterletManifestWorks: %s", instance.Name))
+		err = evictKlusterletManifestWorks(r.client, instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
-	reqLogger.Info(fmt.Sprintf("evictKlusterletManifestWorks: %s", instance.Name))
-	err = evictKlusterletManifestWorks(r.client, instance)
-	if err != nil {
+	//The import secret in a dedicated namespace is not removed with the cluster namespace
+	if isImportSecretOutsideClusterNamespace(instance) {
+		if err := deleteImportSecret(r.client, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if err := r.deleteOwnedArtifacts(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := deleteClusterCSRs(r.client, instance); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	reqLogger.Info(fmt.Sprintf("Remove all finalizer: %s", instance.Name))
-	instance.ObjectMeta.Finalizers = nil
+	reqLogger.Info(fmt.Sprintf("Remove all finalizer: %s", instance.Name), "finalizerOrder", order)
+	instance.ObjectMeta.Finalizers = remainingFinalizers(instance, order)
 	if err := r.client.Update(context.TODO(), instance); err != nil {
 		return reconcile.Result{}, err
 	}
+	deletions.release(instance.Name)
 
 	return reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
 }
@@ -4,8 +4,20 @@ package managedcluster
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
 	operatorv1 "github.com/open-cluster-management/api/operator/v1"
@@ -19,6 +31,8 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -26,6 +40,9 @@ import (
 )
 
 func TestReconcileManagedCluster_importClusterWithClient(t *testing.T) {
+	//the fake managed cluster doesn't run the klusterlet operator, see Test_waitForKlusterletReady
+	os.Setenv(klusterletReadyTimeoutEnvVarName, "0")
+	defer os.Unsetenv(klusterletReadyTimeoutEnvVarName)
 	schemeHub := scheme.Scheme
 
 	schemeHub.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
@@ -211,3 +228,355 @@ func TestReconcileManagedCluster_importClusterWithClient(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseExecConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		execData       string
+		wantErr        bool
+		wantAPIVersion string
+	}{
+		{
+			name: "valid",
+			execData: `
+command: oidc-login
+args:
+- get-token
+env:
+- name: ISSUER
+  value: https://issuer.example.com
+apiVersion: client.authentication.k8s.io/v1alpha1
+`,
+			wantAPIVersion: "client.authentication.k8s.io/v1alpha1",
+		},
+		{
+			name:           "default apiVersion",
+			execData:       `command: oidc-login`,
+			wantAPIVersion: "client.authentication.k8s.io/v1beta1",
+		},
+		{
+			name:     "missing command",
+			execData: `args: ["get-token"]`,
+			wantErr:  true,
+		},
+		{
+			name: "unsupported apiVersion",
+			execData: `
+command: oidc-login
+apiVersion: client.authentication.k8s.io/v9
+`,
+			wantErr: true,
+		},
+		{
+			name: "env without name",
+			execData: `
+command: oidc-login
+env:
+- value: foo
+`,
+			wantErr: true,
+		},
+		{
+			name:     "not yaml",
+			execData: `{command`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExecConfig([]byte(tt.execData))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseExecConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, errInvalidExecConfig) {
+					t.Errorf("parseExecConfig() error = %v, want errInvalidExecConfig", err)
+				}
+				return
+			}
+			if got.APIVersion != tt.wantAPIVersion {
+				t.Errorf("parseExecConfig() apiVersion = %s, want %s", got.APIVersion, tt.wantAPIVersion)
+			}
+		})
+	}
+}
+
+func Test_newConfigOverrides(t *testing.T) {
+	tests := []struct {
+		name             string
+		annotations      map[string]string
+		autoImportSecret *corev1.Secret
+		want             string
+	}{
+		{
+			name: "no server name",
+			want: "",
+		},
+		{
+			name:        "server name from annotation",
+			annotations: map[string]string{tlsServerNameAnnotation: "api.annotation.com"},
+			want:        "api.annotation.com",
+		},
+		{
+			name:        "server name from auto-import-secret",
+			annotations: map[string]string{tlsServerNameAnnotation: "api.annotation.com"},
+			autoImportSecret: &corev1.Secret{
+				Data: map[string][]byte{autoImportSecretServerNameKey: []byte("api.secret.com")},
+			},
+			want: "api.secret.com",
+		},
+		{
+			name:        "empty server name in auto-import-secret",
+			annotations: map[string]string{tlsServerNameAnnotation: "api.annotation.com"},
+			autoImportSecret: &corev1.Secret{
+				Data: map[string][]byte{autoImportSecretServerNameKey: []byte("")},
+			},
+			want: "api.annotation.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mycluster",
+					Annotations: tt.annotations,
+				},
+			}
+			got := newConfigOverrides(managedCluster, tt.autoImportSecret)
+			if got.ClusterInfo.TLSServerName != tt.want {
+				t.Errorf("newConfigOverrides() TLSServerName = %s, want %s", got.ClusterInfo.TLSServerName, tt.want)
+			}
+			restConfig, err := newRestConfigFromServerAndAuth("https://10.0.0.1:6443",
+				&clientcmdapi.AuthInfo{Token: "fake-token"}, got)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if restConfig.TLSClientConfig.ServerName != tt.want {
+				t.Errorf("newRestConfigFromServerAndAuth() ServerName = %s, want %s", restConfig.TLSClientConfig.ServerName, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newConfigOverrides_dialServer(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	kubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: real
+  cluster:
+    server: https://api.real.com:6443
+contexts:
+- name: real
+  context:
+    cluster: real
+    user: admin
+current-context: real
+users:
+- name: admin
+  user:
+    token: fake-token
+`)
+	tests := []struct {
+		name           string
+		data           map[string][]byte
+		wantHost       string
+		wantServerName string
+	}{
+		{
+			name: "token without dial server",
+			data: map[string][]byte{
+				"server": []byte("https://api.real.com:6443"),
+				"token":  []byte("fake-token"),
+			},
+			wantHost:       "https://api.real.com:6443",
+			wantServerName: "",
+		},
+		{
+			name: "token with dial server",
+			data: map[string][]byte{
+				"server":                      []byte("https://api.real.com:6443"),
+				"token":                       []byte("fake-token"),
+				autoImportSecretDialServerKey: []byte("https://tunnel.hub.com:8443"),
+			},
+			wantHost:       "https://tunnel.hub.com:8443",
+			wantServerName: "api.real.com",
+		},
+		{
+			name: "token with dial server and server name",
+			data: map[string][]byte{
+				"server":                      []byte("https://api.real.com:6443"),
+				"token":                       []byte("fake-token"),
+				autoImportSecretDialServerKey: []byte("https://tunnel.hub.com:8443"),
+				autoImportSecretServerNameKey: []byte("api.sni.com"),
+			},
+			wantHost:       "https://tunnel.hub.com:8443",
+			wantServerName: "api.sni.com",
+		},
+		{
+			name: "token with IPv6 server",
+			data: map[string][]byte{
+				"server": []byte("https://[fd00::1]:6443"),
+				"token":  []byte("fake-token"),
+			},
+			wantHost:       "https://[fd00::1]:6443",
+			wantServerName: "",
+		},
+		{
+			name: "token with IPv6 dial server",
+			data: map[string][]byte{
+				"server":                      []byte("https://[fd00::1]:6443"),
+				"token":                       []byte("fake-token"),
+				autoImportSecretDialServerKey: []byte("https://[fd00::2]:8443"),
+			},
+			wantHost:       "https://[fd00::2]:8443",
+			wantServerName: "fd00::1",
+		},
+		{
+			name: "kubeconfig with dial server",
+			data: map[string][]byte{
+				"kubeconfig":                  kubeconfig,
+				autoImportSecretDialServerKey: []byte("https://tunnel.hub.com:8443"),
+			},
+			wantHost:       "https://tunnel.hub.com:8443",
+			wantServerName: "api.real.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			autoImportSecret := &corev1.Secret{Data: tt.data}
+			overrides := newConfigOverrides(managedCluster, autoImportSecret)
+			var restConfig *rest.Config
+			var err error
+			if k, ok := tt.data["kubeconfig"]; ok {
+				restConfig, err = newRestConfigFromKubeConfig(k, overrides)
+			} else {
+				restConfig, err = newRestConfigFromServerAndAuth(string(tt.data["server"]),
+					&clientcmdapi.AuthInfo{Token: string(tt.data["token"])}, overrides)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if restConfig.Host != tt.wantHost {
+				t.Errorf("Host = %s, want %s", restConfig.Host, tt.wantHost)
+			}
+			if restConfig.TLSClientConfig.ServerName != tt.wantServerName {
+				t.Errorf("ServerName = %s, want %s", restConfig.TLSClientConfig.ServerName, tt.wantServerName)
+			}
+		})
+	}
+}
+
+//newTestClientCertificate returns a self-signed client certificate and its key, PEM encoded
+func newTestClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admin"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func Test_newClientCertificateAuthInfo(t *testing.T) {
+	cert, key := newTestClientCertificate(t)
+	otherCert, _ := newTestClientCertificate(t)
+
+	t.Run("matching pair", func(t *testing.T) {
+		authInfo, err := newClientCertificateAuthInfo(cert, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		overrides := newConfigOverrides(&clusterv1.ManagedCluster{}, nil)
+		overrides.ClusterInfo.CertificateAuthorityData = otherCert
+		restConfig, err := newRestConfigFromServerAndAuth("https://10.0.0.1:6443", authInfo, overrides)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(restConfig.TLSClientConfig.CertData) != string(cert) || string(restConfig.TLSClientConfig.KeyData) != string(key) {
+			t.Errorf("the client certificate is not set in the rest config")
+		}
+		if string(restConfig.TLSClientConfig.CAData) != string(otherCert) || restConfig.TLSClientConfig.Insecure {
+			t.Errorf("the CA is not used to verify the server, insecure: %v", restConfig.TLSClientConfig.Insecure)
+		}
+	})
+	t.Run("mismatching pair", func(t *testing.T) {
+		_, err := newClientCertificateAuthInfo(otherCert, key)
+		if !errors.Is(err, ErrInvalidSecret) {
+			t.Errorf("newClientCertificateAuthInfo() error = %v, want an invalid secret error", err)
+		}
+		if got := importErrorReason(err); got != reasonClientCertificateMismatch {
+			t.Errorf("importErrorReason() = %s, want %s", got, reasonClientCertificateMismatch)
+		}
+	})
+}
+
+func Test_importErrorReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "generic error",
+			err:  fmt.Errorf("generic"),
+			want: "ManagedClusterNotImported",
+		},
+		{
+			name: "exec misconfigured",
+			err:  fmt.Errorf("%w: command is missing", errInvalidExecConfig),
+			want: reasonExecPluginMisconfigured,
+		},
+		{
+			name: "tls server name mismatch",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "https://10.0.0.1:6443",
+				Err: x509.HostnameError{Certificate: &x509.Certificate{}, Host: "10.0.0.1"},
+			},
+			want: reasonTLSServerNameMismatch,
+		},
+		{
+			name: "cluster not installed",
+			err:  newImportError(ErrClusterNotInstalled, fmt.Errorf("not installed")),
+			want: reasonClusterNotInstalled,
+		},
+		{
+			name: "invalid secret",
+			err:  newImportError(ErrInvalidSecret, fmt.Errorf("kubeconfig is missing")),
+			want: reasonInvalidImportSecret,
+		},
+		{
+			name: "unreachable",
+			err:  newImportError(ErrUnreachable, fmt.Errorf("connection refused")),
+			want: reasonManagedClusterUnreachable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := importErrorReason(tt.err); got != tt.want {
+				t.Errorf("importErrorReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,7 +9,8 @@ import (
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -20,19 +21,44 @@ import (
 // Add creates a new ManagedCluster Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	selector, err := getManagedClusterSelector()
+	if err != nil {
+		return err
+	}
+	return add(mgr, newReconciler(mgr, selector), selector)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	client := newCustomClient(mgr.GetClient(), mgr.GetAPIReader())
-	return &ReconcileManagedCluster{client: client, scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager, selector labels.Selector) reconcile.Reconciler {
+	client := newFieldManagerClient(newCustomClient(mgr.GetClient(), mgr.GetAPIReader()), fieldManager())
+	var kubeClient kubernetes.Interface
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		log.Error(err, "Unable to create the kube client, the bootstrap token audiences are not supported")
+	} else {
+		kubeClient = clientset
+	}
+	return &ReconcileManagedCluster{
+		client:     client,
+		apiReader:  mgr.GetAPIReader(),
+		kubeClient: kubeClient,
+		scheme:     mgr.GetScheme(),
+		recorder:   mgr.GetEventRecorderFor("managedcluster-import-controller"),
+		selector:   selector,
+	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, selector labels.Selector) error {
 	// Create a new controller
-	c, err := controller.New("managedcluster-controller", mgr, controller.Options{Reconciler: r})
+	// A new applier and template processor are created on each reconcile, so several clusters can be
+	// reconciled in parallel. The state kept between reconciles (deletion slots, klusterlet upgrade slots,
+	// pending verifications and readiness checks, retry backoffs, imports in flight) is in memory only,
+	// keyed by cluster name and guarded by a mutex, it is lost on restart.
+	c, err := controller.New("managedcluster-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: getEnvInt(maxConcurrentReconcilesEnvVarName, 1),
+	})
 	if err != nil {
 		return err
 	}
@@ -41,6 +67,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	err = c.Watch(
 		&source.Kind{Type: &clusterv1.ManagedCluster{}},
 		&handler.EnqueueRequestForObject{},
+		newManagedClusterSelectorPredicate(selector),
 	)
 	if err != nil {
 		return err
@@ -71,25 +98,28 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	err = c.Watch(
-		&source.Kind{Type: &hivev1.ClusterDeployment{}},
-		&handler.EnqueueRequestsFromMapFunc{
-			ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
-				return []reconcile.Request{
-					{
-						NamespacedName: types.NamespacedName{
-							Name:      obj.Meta.GetName(),
-							Namespace: obj.Meta.GetNamespace(),
-						},
-					},
-				}
-			}),
-		},
-	)
+	// Import the Hive-provisioned clusters once their ClusterDeployment is installed,
+	// the watch would fail the controller start on a hub without Hive
+	hive, err := hasClusterDeploymentCRD(mgr.GetRESTMapper())
 	if err != nil {
-		log.Error(err, "Fail to add Watch for ClusterDeployment to controller")
+		log.Error(err, "Fail to discover the ClusterDeployment CRD")
 		return err
 	}
+	if hive {
+		err = c.Watch(
+			&source.Kind{Type: &hivev1.ClusterDeployment{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: handler.ToRequestsFunc(clusterNamespaceToManagedCluster),
+			},
+			newClusterDeploymentInstalledPredicate(),
+		)
+		if err != nil {
+			log.Error(err, "Fail to add Watch for ClusterDeployment to controller")
+			return err
+		}
+	} else {
+		log.Info("The ClusterDeployment CRD is not installed, the Hive clusters are not watched")
+	}
 
 	err = c.Watch(
 		&source.Kind{Type: &rbacv1.ClusterRoleBinding{}},
@@ -103,16 +133,66 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Recreate the import secret when it is deleted or modified, only the labeled import secrets are
+	// cached, the other secrets are read from the apiserver
+	importSecrets, err := newFilteredInformerSource(mgr, &corev1.Secret{}, "", importSecretListOptions)
+	if err != nil {
+		log.Error(err, "Fail to create the import Secret informer")
+		return err
+	}
 	err = c.Watch(
-		&source.Kind{Type: &workv1.ManifestWork{}},
+		importSecrets,
 		&handler.EnqueueRequestForOwner{
 			IsController: true,
 			OwnerType:    &clusterv1.ManagedCluster{},
 		},
-		newManifestWorkSpecPredicate(),
+		newImportSecretPredicate(),
 	)
 	if err != nil {
-		log.Error(err, "Fail to add Watch for ManifestWork to controller")
+		log.Error(err, "Fail to add Watch for import Secret to controller")
+		return err
+	}
+
+	// The watches would fail the controller start on a hub without the work API, the reconciles
+	// report it with the WorkAPIUnavailable condition
+	work, err := hasManifestWorkCRD(mgr.GetRESTMapper())
+	if err != nil {
+		log.Error(err, "Fail to discover the ManifestWork CRD")
+		return err
+	}
+	if work {
+		err = c.Watch(
+			&source.Kind{Type: &workv1.ManifestWork{}},
+			&handler.EnqueueRequestForOwner{
+				IsController: true,
+				OwnerType:    &clusterv1.ManagedCluster{},
+			},
+			newManifestWorkSpecPredicate(),
+		)
+		if err != nil {
+			log.Error(err, "Fail to add Watch for ManifestWork to controller")
+			return err
+		}
+
+		// Continue the cleanup of a deleted ManagedCluster once its other manifestworks are deleted
+		err = c.Watch(
+			&source.Kind{Type: &workv1.ManifestWork{}},
+			&handler.EnqueueRequestsFromMapFunc{
+				ToRequests: handler.ToRequestsFunc(clusterNamespaceToManagedCluster),
+			},
+			newManifestWorkDeletionPredicate(),
+		)
+		if err != nil {
+			log.Error(err, "Fail to add Watch for ManifestWork deletion to controller")
+			return err
+		}
+	} else {
+		log.Info("The ManifestWork CRD is not installed, the manifestworks are not watched")
+	}
+
+	// Regenerate the import secrets and the manifestworks when the bootstrap CA rotates
+	if err := watchBootstrapCA(c, mgr, selector); err != nil {
+		log.Error(err, "Fail to add Watch for the bootstrap CA to controller")
 		return err
 	}
 	return nil
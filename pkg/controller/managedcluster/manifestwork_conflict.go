@@ -0,0 +1,115 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+const (
+	ConditionManifestWorkApplyConflict string = "ManifestWorkApplyConflict"
+	reasonManifestWorkApplyConflict    string = "ManifestWorkApplyConflict"
+	reasonManifestWorkApplied          string = "ManifestWorkApplied"
+)
+
+const defaultManifestWorkConflictThreshold = 5
+
+//manifestWorkApplyConflicts counts the manifestwork updates rejected because of a conflict,
+//a steady increase means another actor is updating the import manifestworks
+var manifestWorkApplyConflicts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "managedcluster_import_manifestwork_apply_conflicts_total",
+		Help: "Number of klusterlet manifestwork applies which failed because of a conflict",
+	},
+	[]string{"managedcluster"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(manifestWorkApplyConflicts)
+}
+
+//conflictTracker counts the consecutive conflicts per managed cluster
+type conflictTracker struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newConflictTracker() *conflictTracker {
+	return &conflictTracker{counts: map[string]int{}}
+}
+
+//record returns the number of consecutive conflicts for the cluster, a nil error resets it
+func (t *conflictTracker) record(clusterName string, err error) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil || !errors.IsConflict(err) {
+		delete(t.counts, clusterName)
+		return 0
+	}
+	t.counts[clusterName]++
+	return t.counts[clusterName]
+}
+
+//forget drops the conflicts of the cluster
+func (t *conflictTracker) forget(clusterName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.counts, clusterName)
+}
+
+var manifestWorkConflicts = newConflictTracker()
+
+//forgetManifestWorkConflicts drops the conflicts and the conflict metric of a removed cluster,
+//so the series and the entries don't grow with the churn of the clusters
+func forgetManifestWorkConflicts(clusterName string) {
+	manifestWorkConflicts.forget(clusterName)
+	manifestWorkApplyConflicts.DeleteLabelValues(clusterName)
+}
+
+//setConditionManifestWorkApplyConflict sets the condition once the manifestworks failed to be applied
+//because of a conflict more than MANIFESTWORK_CONFLICT_THRESHOLD times in a row,
+//the condition is only patched when it is raised or cleared
+func (r *ReconcileManagedCluster) setConditionManifestWorkApplyConflict(
+	managedCluster *clusterv1.ManagedCluster,
+	errIn error,
+) error {
+	if errIn != nil && errors.IsConflict(errIn) {
+		manifestWorkApplyConflicts.WithLabelValues(managedCluster.Name).Inc()
+	}
+	conflicts := manifestWorkConflicts.record(managedCluster.Name, errIn)
+	threshold := getEnvInt(manifestWorkConflictThresholdEnvVarName, defaultManifestWorkConflictThreshold)
+	current := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionManifestWorkApplyConflict)
+	switch {
+	case conflicts >= threshold:
+		if current != nil && current.Status == metav1.ConditionTrue {
+			return nil
+		}
+		message := fmt.Sprintf("The klusterlet manifestworks failed to be applied %d times in a row because of conflicts, "+
+			"another actor may be updating them: %v", conflicts, errIn)
+		r.recordEvent(managedCluster, corev1.EventTypeWarning, reasonManifestWorkApplyConflict, message)
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionManifestWorkApplyConflict,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonManifestWorkApplyConflict,
+			Message: message,
+		})
+	case errIn == nil && current != nil && current.Status == metav1.ConditionTrue:
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionManifestWorkApplyConflict,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonManifestWorkApplied,
+			Message: "The klusterlet manifestworks are applied",
+		})
+	}
+	return nil
+}
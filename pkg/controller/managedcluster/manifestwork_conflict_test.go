@@ -0,0 +1,108 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_conflictTracker(t *testing.T) {
+	conflict := errors.NewConflict(workv1.SchemeGroupVersion.WithResource("manifestworks").GroupResource(), "mycluster-klusterlet", fmt.Errorf("modified"))
+	tracker := newConflictTracker()
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "first conflict", err: conflict, want: 1},
+		{name: "second conflict", err: conflict, want: 2},
+		{name: "other error resets", err: fmt.Errorf("boom"), want: 0},
+		{name: "conflict again", err: conflict, want: 1},
+		{name: "success resets", err: nil, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tracker.record("mycluster", tt.err); got != tt.want {
+				t.Errorf("record() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_forgetManifestWorkConflicts(t *testing.T) {
+	conflict := errors.NewConflict(workv1.SchemeGroupVersion.WithResource("manifestworks").GroupResource(), "removed-klusterlet", fmt.Errorf("modified"))
+	manifestWorkConflicts.record("removed", conflict)
+	manifestWorkApplyConflicts.WithLabelValues("removed").Inc()
+
+	forgetManifestWorkConflicts("removed")
+
+	if got := manifestWorkConflicts.record("removed", conflict); got != 1 {
+		t.Errorf("record() after forget = %d, want 1", got)
+	}
+	manifestWorkConflicts.forget("removed")
+	if manifestWorkApplyConflicts.DeleteLabelValues("removed") {
+		t.Error("expected the conflict metric of the removed cluster to be deleted")
+	}
+}
+
+func Test_setConditionManifestWorkApplyConflict(t *testing.T) {
+	os.Setenv(manifestWorkConflictThresholdEnvVarName, "2")
+	defer os.Unsetenv(manifestWorkConflictThresholdEnvVarName)
+
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "conflictcluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	conflict := errors.NewConflict(workv1.SchemeGroupVersion.WithResource("manifestworks").GroupResource(), "conflictcluster-klusterlet", fmt.Errorf("modified"))
+
+	steps := []struct {
+		name        string
+		err         error
+		wantPresent bool
+		wantStatus  metav1.ConditionStatus
+	}{
+		{name: "below the threshold", err: conflict, wantPresent: false},
+		{name: "threshold reached", err: conflict, wantPresent: true, wantStatus: metav1.ConditionTrue},
+		{name: "still conflicting", err: conflict, wantPresent: true, wantStatus: metav1.ConditionTrue},
+		{name: "applied", err: nil, wantPresent: true, wantStatus: metav1.ConditionFalse},
+	}
+	for _, step := range steps {
+		if err := r.setConditionManifestWorkApplyConflict(managedCluster, step.err); err != nil {
+			t.Errorf("%s: setConditionManifestWorkApplyConflict() error = %v", step.name, err)
+			return
+		}
+		got := &clusterv1.ManagedCluster{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: managedCluster.Name}, got); err != nil {
+			t.Error(err)
+			return
+		}
+		c := meta.FindStatusCondition(got.Status.Conditions, ConditionManifestWorkApplyConflict)
+		if (c != nil) != step.wantPresent {
+			t.Errorf("%s: expected condition present %v, got %v", step.name, step.wantPresent, got.Status.Conditions)
+			continue
+		}
+		if c != nil && c.Status != step.wantStatus {
+			t.Errorf("%s: expected condition status %s, got %s", step.name, step.wantStatus, c.Status)
+		}
+	}
+}
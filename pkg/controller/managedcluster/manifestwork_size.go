@@ -0,0 +1,217 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+)
+
+const (
+	ConditionManifestWorkSizeExceeded string = "ManifestWorkSizeExceeded"
+	reasonManifestWorkTooLarge        string = "ManifestWorkTooLarge"
+	reasonManifestWorkSizeWithinLimit string = "ManifestWorkSizeWithinLimit"
+)
+
+//defaultManifestWorkMaxSize leaves room under the 1.5MiB etcd limit for the status written by the work agent
+const defaultManifestWorkMaxSize = 1024 * 1024
+
+//manifestWorkPartInfix separates the name of a split manifestwork from its part index
+const manifestWorkPartInfix = "-part-"
+
+//manifestWorkTooLargeError is returned when a manifest alone doesn't fit in a manifestwork
+type manifestWorkTooLargeError struct {
+	name    string
+	size    int
+	maxSize int
+}
+
+func (e *manifestWorkTooLargeError) Error() string {
+	return fmt.Sprintf("a manifest of the manifestwork %s is %d bytes and can't fit in a manifestwork of %d bytes, "+
+		"increase %s", e.name, e.size, e.maxSize, manifestWorkMaxSizeEnvVarName)
+}
+
+func isManifestWorkTooLarge(err error) bool {
+	var tooLarge *manifestWorkTooLargeError
+	return errors.As(err, &tooLarge)
+}
+
+func manifestWorkMaxSize() int {
+	return getEnvInt(manifestWorkMaxSizeEnvVarName, defaultManifestWorkMaxSize)
+}
+
+func manifestWorkSize(mw *workv1.ManifestWork) (int, error) {
+	b, err := json.Marshal(mw)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func manifestWorkPartName(name string, index int) string {
+	if index == 0 {
+		return name
+	}
+	return name + manifestWorkPartInfix + strconv.Itoa(index)
+}
+
+//splitManifestWork splits the manifests of the manifestwork across several manifestworks when its
+//serialized size is over maxSize. The first part keeps the name of the manifestwork.
+func splitManifestWork(mw *workv1.ManifestWork, maxSize int) ([]*workv1.ManifestWork, error) {
+	size, err := manifestWorkSize(mw)
+	if err != nil {
+		return nil, err
+	}
+	if size <= maxSize {
+		return []*workv1.ManifestWork{mw}, nil
+	}
+	empty := mw.DeepCopy()
+	empty.Spec.Workload.Manifests = nil
+	baseSize, err := manifestWorkSize(empty)
+	if err != nil {
+		return nil, err
+	}
+	//room for the manifests field and the part name suffix
+	baseSize += len(`,"manifests":[]`) + len(manifestWorkPartInfix) + 4
+
+	parts := make([][]workv1.Manifest, 0)
+	current := make([]workv1.Manifest, 0)
+	currentSize := baseSize
+	for _, m := range mw.Spec.Workload.Manifests {
+		//the raw manifest and its separator
+		mSize := len(m.Raw) + 1
+		if baseSize+mSize > maxSize {
+			return nil, &manifestWorkTooLargeError{name: mw.Name, size: mSize, maxSize: maxSize}
+		}
+		if currentSize+mSize > maxSize {
+			parts = append(parts, current)
+			current = make([]workv1.Manifest, 0)
+			currentSize = baseSize
+		}
+		current = append(current, m)
+		currentSize += mSize
+	}
+	parts = append(parts, current)
+
+	log.Info("Splitting the manifestwork", "name", mw.Name, "size", size, "maxSize", maxSize, "parts", len(parts))
+	mws := make([]*workv1.ManifestWork, len(parts))
+	for i, manifests := range parts {
+		part := empty.DeepCopy()
+		part.Name = manifestWorkPartName(mw.Name, i)
+		part.Spec.Workload.Manifests = manifests
+		mws[i] = part
+	}
+	return mws, nil
+}
+
+//createOrUpdateSplitManifestWork applies the manifestwork, split in parts if too large,
+//and deletes the parts not needed anymore. It returns the first part.
+func createOrUpdateSplitManifestWork(
+	c client.Client,
+	scheme *runtime.Scheme,
+	managedCluster *clusterv1.ManagedCluster,
+	mw *workv1.ManifestWork,
+) (*workv1.ManifestWork, error) {
+	//the metadata set on apply is part of the size
+	if err := controllerutil.SetControllerReference(managedCluster, mw, scheme); err != nil {
+		return nil, err
+	}
+	getPropagatedMetadata(managedCluster).apply(mw)
+	setTemplatesVersion(mw)
+	setOwnerLabels(mw, managedCluster)
+	parts, err := splitManifestWork(mw, manifestWorkMaxSize())
+	if err != nil {
+		return nil, err
+	}
+	var first *workv1.ManifestWork
+	for i, part := range parts {
+		applied, err := createOrUpdateManifestWork(c, scheme, managedCluster, part)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			first = applied
+		}
+	}
+	staleParts, err := listManifestWorkParts(c, mw.Name, mw.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range staleParts {
+		index, _ := strconv.Atoi(strings.TrimPrefix(name, mw.Name+manifestWorkPartInfix))
+		if index < len(parts) {
+			continue
+		}
+		if err := deleteManifestWork(c, name, mw.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	return first, nil
+}
+
+//listManifestWorkParts returns the names of the additional parts of a split manifestwork
+func listManifestWorkParts(c client.Client, name, namespace string) ([]string, error) {
+	mws := &workv1.ManifestWorkList{}
+	if err := c.List(context.TODO(), mws, &client.ListOptions{Namespace: namespace}); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, mw := range mws.Items {
+		if isManifestWorkPart(mw.Name, name) {
+			names = append(names, mw.Name)
+		}
+	}
+	return names, nil
+}
+
+//isManifestWorkPart returns true if name is an additional part of the manifestwork named base
+func isManifestWorkPart(name, base string) bool {
+	index := strings.TrimPrefix(name, base+manifestWorkPartInfix)
+	if index == name {
+		return false
+	}
+	_, err := strconv.Atoi(index)
+	return err == nil
+}
+
+//setConditionManifestWorkSizeExceeded sets the condition when a manifest is too large to be applied,
+//the condition is only patched when it is raised or cleared
+func (r *ReconcileManagedCluster) setConditionManifestWorkSizeExceeded(
+	managedCluster *clusterv1.ManagedCluster,
+	errIn error,
+) error {
+	current := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionManifestWorkSizeExceeded)
+	switch {
+	case isManifestWorkTooLarge(errIn):
+		if current != nil && current.Status == metav1.ConditionTrue && current.Message == errIn.Error() {
+			return nil
+		}
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionManifestWorkSizeExceeded,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonManifestWorkTooLarge,
+			Message: errIn.Error(),
+		})
+	case errIn == nil && current != nil && current.Status == metav1.ConditionTrue:
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionManifestWorkSizeExceeded,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonManifestWorkSizeWithinLimit,
+			Message: "The klusterlet manifestworks are within the size limit",
+		})
+	}
+	return nil
+}
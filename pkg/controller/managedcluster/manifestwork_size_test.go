@@ -0,0 +1,231 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+//newSizedManifest returns a manifest whose raw JSON is size bytes
+func newSizedManifest(size int) workv1.Manifest {
+	prefix := `{"data":"`
+	suffix := `"}`
+	return workv1.Manifest{
+		RawExtension: runtime.RawExtension{
+			Raw: []byte(prefix + strings.Repeat("a", size-len(prefix)-len(suffix)) + suffix),
+		},
+	}
+}
+
+func newSizedManifestWork(name string, sizes ...int) *workv1.ManifestWork {
+	manifests := make([]workv1.Manifest, len(sizes))
+	for i, size := range sizes {
+		manifests[i] = newSizedManifest(size)
+	}
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "mycluster",
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: manifests,
+			},
+		},
+	}
+}
+
+func Test_splitManifestWork(t *testing.T) {
+	mw := newSizedManifestWork("mycluster-klusterlet", 100, 100, 100)
+	size, err := manifestWorkSize(mw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		maxSize   int
+		wantParts int
+		wantErr   bool
+	}{
+		{
+			name:      "under the limit",
+			maxSize:   size + 1,
+			wantParts: 1,
+		},
+		{
+			name:      "at the limit",
+			maxSize:   size,
+			wantParts: 1,
+		},
+		{
+			name:      "one byte over the limit",
+			maxSize:   size - 1,
+			wantParts: 2,
+		},
+		{
+			name:      "one manifest per part",
+			maxSize:   size - 150,
+			wantParts: 3,
+		},
+		{
+			name:    "manifest too large",
+			maxSize: 200,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitManifestWork(mw, tt.maxSize)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("splitManifestWork() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !isManifestWorkTooLarge(err) {
+					t.Errorf("expected a manifestWorkTooLargeError, got %v", err)
+				}
+				return
+			}
+			if len(got) != tt.wantParts {
+				t.Errorf("splitManifestWork() = %d parts, want %d", len(got), tt.wantParts)
+				return
+			}
+			manifests := 0
+			for i, part := range got {
+				if part.Name != manifestWorkPartName(mw.Name, i) {
+					t.Errorf("part %d name = %s", i, part.Name)
+				}
+				partSize, err := manifestWorkSize(part)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if partSize > tt.maxSize {
+					t.Errorf("part %d size = %d, over the limit %d", i, partSize, tt.maxSize)
+				}
+				manifests += len(part.Spec.Workload.Manifests)
+			}
+			if manifests != len(mw.Spec.Workload.Manifests) {
+				t.Errorf("the parts contain %d manifests, want %d", manifests, len(mw.Spec.Workload.Manifests))
+			}
+		})
+	}
+}
+
+func Test_createOrUpdateSplitManifestWork(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	name := managedCluster.Name + manifestWorkNamePostfix
+	c := fake.NewFakeClientWithScheme(testscheme, managedCluster)
+
+	large := newSizedManifestWork(name, 1000, 1000, 1000)
+	os.Setenv(manifestWorkMaxSizeEnvVarName, strconv.Itoa(2000))
+	defer os.Unsetenv(manifestWorkMaxSizeEnvVarName)
+	if _, err := createOrUpdateSplitManifestWork(c, testscheme, managedCluster, large); err != nil {
+		t.Errorf("createOrUpdateSplitManifestWork() error = %v", err)
+		return
+	}
+	for i := 0; i < 3; i++ {
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: manifestWorkPartName(name, i), Namespace: "mycluster"},
+			&workv1.ManifestWork{}); err != nil {
+			t.Errorf("expected the part %d to be created, got %v", i, err)
+		}
+	}
+
+	small := newSizedManifestWork(name, 100)
+	if _, err := createOrUpdateSplitManifestWork(c, testscheme, managedCluster, small); err != nil {
+		t.Errorf("createOrUpdateSplitManifestWork() error = %v", err)
+		return
+	}
+	mws := &workv1.ManifestWorkList{}
+	if err := c.List(context.TODO(), mws, &client.ListOptions{Namespace: "mycluster"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(mws.Items) != 1 || mws.Items[0].Name != name {
+		t.Errorf("expected the stale parts to be deleted, got %d manifestworks", len(mws.Items))
+	}
+}
+
+func Test_isKlusterletManifestWork(t *testing.T) {
+	base := "mycluster" + manifestWorkNamePostfix
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: base, want: true},
+		{name: base + manifestWorkCRDSPostfix, want: true},
+		{name: base + manifestWorkPartInfix + "1", want: true},
+		{name: base + manifestWorkCRDSPostfix + manifestWorkPartInfix + "2", want: true},
+		{name: base + manifestWorkPartInfix + "x", want: false},
+		{name: "mycluster-addon", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isKlusterletManifestWork(tt.name, base); got != tt.want {
+				t.Errorf("isKlusterletManifestWork() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setConditionManifestWorkSizeExceeded(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "sizecluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	tooLarge := &manifestWorkTooLargeError{name: "sizecluster-klusterlet", size: 2000, maxSize: 1000}
+	steps := []struct {
+		name        string
+		err         error
+		wantPresent bool
+		wantStatus  metav1.ConditionStatus
+	}{
+		{name: "applied", wantPresent: false},
+		{name: "other error", err: fmt.Errorf("boom"), wantPresent: false},
+		{name: "too large", err: fmt.Errorf("apply: %w", tooLarge), wantPresent: true, wantStatus: metav1.ConditionTrue},
+		{name: "fixed", wantPresent: true, wantStatus: metav1.ConditionFalse},
+	}
+	for _, step := range steps {
+		if err := r.setConditionManifestWorkSizeExceeded(managedCluster, step.err); err != nil {
+			t.Errorf("%s: setConditionManifestWorkSizeExceeded() error = %v", step.name, err)
+			return
+		}
+		c := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionManifestWorkSizeExceeded)
+		if (c != nil) != step.wantPresent {
+			t.Errorf("%s: expected condition present %v, got %v", step.name, step.wantPresent, managedCluster.Status.Conditions)
+			continue
+		}
+		if c != nil && c.Status != step.wantStatus {
+			t.Errorf("%s: expected status %s, got %s", step.name, step.wantStatus, c.Status)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const reasonNamespaceConflict string = "NamespaceConflict"
+
+//namespaceConflictRequeueAfter is the delay before checking again a conflicting namespace,
+//the namespaces are not watched
+const namespaceConflictRequeueAfter = 1 * time.Minute
+
+//hiveClusterDeploymentLabel labels the provision and deprovision jobs and pods run by hive in the
+//namespace of the ClusterDeployment, they are not a conflict
+const hiveClusterDeploymentLabel = "hive.openshift.io/cluster-deployment-name"
+
+//getNamespaceConflict returns the workload found in a cluster namespace not labeled by this controller,
+//an empty string if the namespace is labeled or holds only import contents. The registration and the
+//import never run a workload in the cluster namespace and hive only runs its labeled jobs and pods, any
+//other workload or service means the namespace is used by something else and the controller must neither
+//adopt nor delete it.
+func (r *ReconcileManagedCluster) getNamespaceConflict(ns *corev1.Namespace) (string, error) {
+	if _, ok := ns.GetLabels()[clusterLabel]; ok {
+		return "", nil
+	}
+	//The workloads of the hub are not cached, the namespace is checked only until it is labeled
+	var reader client.Reader = r.client
+	if r.apiReader != nil {
+		reader = r.apiReader
+	}
+	notHive, err := labels.Parse("!" + hiveClusterDeploymentLabel)
+	if err != nil {
+		return "", err
+	}
+	workloads := []struct {
+		kind string
+		list runtime.Object
+	}{
+		{kind: "Deployment", list: &appsv1.DeploymentList{}},
+		{kind: "StatefulSet", list: &appsv1.StatefulSetList{}},
+		{kind: "DaemonSet", list: &appsv1.DaemonSetList{}},
+		{kind: "CronJob", list: &batchv1beta1.CronJobList{}},
+		{kind: "Job", list: &batchv1.JobList{}},
+		{kind: "Pod", list: &corev1.PodList{}},
+		{kind: "Service", list: &corev1.ServiceList{}},
+	}
+	for _, workload := range workloads {
+		if err := reader.List(context.TODO(), workload.list, client.InNamespace(ns.Name),
+			client.MatchingLabelsSelector{Selector: notHive}, client.Limit(1)); err != nil {
+			return "", err
+		}
+		items, err := meta.ExtractList(workload.list)
+		if err != nil {
+			return "", err
+		}
+		if len(items) == 0 {
+			continue
+		}
+		accessor, err := meta.Accessor(items[0])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s/%s", workload.kind, ns.Name, accessor.GetName()), nil
+	}
+	return "", nil
+}
+
+//reportNamespaceConflict stops the import of a ManagedCluster whose name collides with a namespace used by
+//something else, the namespace is not labeled so it is not deleted with the cluster
+func (r *ReconcileManagedCluster) reportNamespaceConflict(
+	managedCluster *clusterv1.ManagedCluster,
+	workload string,
+) (reconcile.Result, error) {
+	message := fmt.Sprintf("The namespace %s is not labeled %s and holds the %s, it is not used by an imported cluster",
+		managedCluster.Name, clusterLabel, workload)
+	log.Info(message, "managedcluster", managedCluster.Name)
+	r.recordEvent(managedCluster, corev1.EventTypeWarning, reasonNamespaceConflict, message)
+	if err := r.setCondition(managedCluster, metav1.Condition{
+		Type:    importConditionType(),
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonNamespaceConflict,
+		Message: message,
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true, RequeueAfter: namespaceConflictRequeueAfter}, nil
+}
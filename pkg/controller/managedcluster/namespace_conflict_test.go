@@ -0,0 +1,259 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileManagedCluster_getNamespaceConflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		objects []runtime.Object
+		want    string
+	}{
+		{
+			name: "empty namespace",
+			want: "",
+		},
+		{
+			name: "import contents",
+			objects: []runtime.Object{
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "auto-import-secret", Namespace: "mycluster"}},
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "mycluster-bootstrap-sa", Namespace: "mycluster"}},
+			},
+			want: "",
+		},
+		{
+			name: "deployment",
+			objects: []runtime.Object{
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "mycluster"}},
+			},
+			want: "Deployment mycluster/app",
+		},
+		{
+			name: "statefulset",
+			objects: []runtime.Object{
+				&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "mycluster"}},
+			},
+			want: "StatefulSet mycluster/db",
+		},
+		{
+			name: "pod",
+			objects: []runtime.Object{
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "mycluster"}},
+			},
+			want: "Pod mycluster/debug",
+		},
+		{
+			name: "job",
+			objects: []runtime.Object{
+				&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: "mycluster"}},
+			},
+			want: "Job mycluster/migration",
+		},
+		{
+			name: "cronjob",
+			objects: []runtime.Object{
+				&batchv1beta1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "mycluster"}},
+			},
+			want: "CronJob mycluster/backup",
+		},
+		{
+			name: "service",
+			objects: []runtime.Object{
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "mycluster"}},
+			},
+			want: "Service mycluster/web",
+		},
+		{
+			name: "hive provision job",
+			objects: []runtime.Object{
+				&batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+					Name:      "mycluster-0-provision",
+					Namespace: "mycluster",
+					Labels:    map[string]string{hiveClusterDeploymentLabel: "mycluster"},
+				}},
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					Name:      "mycluster-0-provision-abcde",
+					Namespace: "mycluster",
+					Labels:    map[string]string{hiveClusterDeploymentLabel: "mycluster"},
+				}},
+			},
+			want: "",
+		},
+		{
+			name: "workload of another namespace",
+			objects: []runtime.Object{
+				&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "other"}},
+			},
+			want: "",
+		},
+		{
+			name:   "labeled namespace",
+			labels: map[string]string{clusterLabel: "mycluster"},
+			objects: []runtime.Object{
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "mycluster"}},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "mycluster",
+					Labels: tt.labels,
+				},
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(scheme.Scheme, append(tt.objects, ns)...),
+				scheme: scheme.Scheme,
+			}
+			got, err := r.getNamespaceConflict(ns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("getNamespaceConflict() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_Reconcile_namespaceConflict(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "mycluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster, ns, deployment),
+		scheme: testscheme,
+	}
+	res, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "mycluster"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequeueAfter != namespaceConflictRequeueAfter {
+		t.Errorf("Reconcile() RequeueAfter = %s, want %s", res.RequeueAfter, namespaceConflictRequeueAfter)
+	}
+	mc := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(mc.Status.Conditions, ManagedClusterImportSucceeded)
+	if c == nil || c.Reason != reasonNamespaceConflict {
+		t.Errorf("expected the condition reason %s, got %v", reasonNamespaceConflict, c)
+	}
+	gotNs := &corev1.Namespace{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, gotNs); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gotNs.Labels[clusterLabel]; ok {
+		t.Errorf("expected the namespace not to be labeled, got %v", gotNs.Labels)
+	}
+
+	//The namespace is kept when the cluster is deleted
+	mc.Finalizers = nil
+	if err := r.client.Update(context.TODO(), mc); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.client.Delete(context.TODO(), mc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "mycluster"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, &corev1.Namespace{}); err != nil {
+		t.Errorf("expected the namespace to be kept, got %v", err)
+	}
+}
+
+func TestReconcileManagedCluster_deleteNamespace_notAdopted(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(hivev1.SchemeGroupVersion, &hivev1.ClusterDeployment{})
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-credentials",
+			Namespace: "mycluster",
+		},
+	}
+	tests := []struct {
+		name   string
+		labels map[string]string
+		wantNS bool
+	}{
+		{
+			//a pre-existing namespace holding only a secret has no workload, it is still not adopted
+			name:   "namespace not labeled with only a secret",
+			wantNS: true,
+		},
+		{
+			name:   "namespace labeled for another cluster",
+			labels: map[string]string{clusterLabel: "othercluster"},
+			wantNS: true,
+		},
+		{
+			name:   "namespace labeled for the cluster",
+			labels: map[string]string{clusterLabel: "mycluster"},
+			wantNS: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "mycluster",
+					Labels: tt.labels,
+				},
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, ns, secret.DeepCopy()),
+				scheme: testscheme,
+			}
+			if err := r.deleteNamespace("mycluster"); err != nil {
+				t.Fatalf("deleteNamespace() unexpected error %v", err)
+			}
+			err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, &corev1.Namespace{})
+			if tt.wantNS && err != nil {
+				t.Errorf("expected the namespace to be kept, got %v", err)
+			}
+			if !tt.wantNS && !errors.IsNotFound(err) {
+				t.Errorf("expected the namespace to be deleted, got %v", err)
+			}
+		})
+	}
+}
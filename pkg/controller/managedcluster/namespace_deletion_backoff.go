@@ -0,0 +1,56 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	namespaceDeletionBackoffBase = 1 * time.Minute
+	namespaceDeletionBackoffCap  = 15 * time.Minute
+)
+
+//namespaceDeletionBackoff computes the delays before retrying a failed cluster namespace deletion,
+//the delays double on each failure up to the cap and are jittered so the retries of the clusters
+//failing together (mass deletion) spread over time
+type namespaceDeletionBackoff struct {
+	mu       sync.Mutex
+	failures map[string]int
+	base     time.Duration
+	cap      time.Duration
+}
+
+var namespaceDeletionRetries = &namespaceDeletionBackoff{
+	failures: map[string]int{},
+	base:     namespaceDeletionBackoffBase,
+	cap:      namespaceDeletionBackoffCap,
+}
+
+//next records a failure for the namespace and returns the delay before the next attempt,
+//between half and the full exponential delay
+func (b *namespaceDeletionBackoff) next(namespace string) time.Duration {
+	b.mu.Lock()
+	failures := b.failures[namespace]
+	b.failures[namespace] = failures + 1
+	b.mu.Unlock()
+
+	delay := b.base
+	for i := 0; i < failures && delay < b.cap; i++ {
+		delay *= 2
+	}
+	if delay > b.cap {
+		delay = b.cap
+	}
+	return wait.Jitter(delay/2, 1.0)
+}
+
+//reset forgets the failures of the namespace once it's deleted
+func (b *namespaceDeletionBackoff) reset(namespace string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.failures, namespace)
+}
@@ -0,0 +1,40 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_namespaceDeletionBackoff(t *testing.T) {
+	b := &namespaceDeletionBackoff{
+		failures: map[string]int{},
+		base:     time.Minute,
+		cap:      10 * time.Minute,
+	}
+	wantMax := []time.Duration{
+		time.Minute,
+		2 * time.Minute,
+		4 * time.Minute,
+		8 * time.Minute,
+		10 * time.Minute,
+		10 * time.Minute,
+	}
+	for i, max := range wantMax {
+		got := b.next("cluster1")
+		if got < max/2 || got > max {
+			t.Errorf("attempt %d: next() = %s, want between %s and %s", i, got, max/2, max)
+		}
+	}
+
+	//the failures are counted per namespace
+	if got := b.next("cluster2"); got > time.Minute {
+		t.Errorf("next() of another namespace = %s, want at most 1m", got)
+	}
+
+	b.reset("cluster1")
+	if got := b.next("cluster1"); got > time.Minute {
+		t.Errorf("next() after reset = %s, want at most 1m", got)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//namespaceDeletionBlockedAnnotation records on the cluster namespace the resource blocking its deletion
+//once the ManagedCluster is gone, as no condition can be set on a deleted ManagedCluster
+const namespaceDeletionBlockedAnnotation = "import.open-cluster-management.io/namespace-deletion-blocked-by"
+
+const (
+	reasonNamespaceDeletionBlocked   = "NamespaceDeletionBlocked"
+	reasonNamespaceDeletionUnblocked = "NamespaceDeletionUnblocked"
+)
+
+//reportNamespaceDeletionBlocked records a Warning event on the cluster namespace and annotates it with
+//the blocker, for example "ClusterDeployment mycluster/mycluster"
+func (r *ReconcileManagedCluster) reportNamespaceDeletionBlocked(ns *corev1.Namespace, blocker string) error {
+	r.recordEvent(ns, corev1.EventTypeWarning, reasonNamespaceDeletionBlocked,
+		fmt.Sprintf("The deletion of the namespace is blocked by the %s, it is deleted once the %s is gone",
+			blocker, blocker))
+	if ns.GetAnnotations()[namespaceDeletionBlockedAnnotation] == blocker {
+		return nil
+	}
+	patch := client.MergeFrom(ns.DeepCopy())
+	annotations := ns.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[namespaceDeletionBlockedAnnotation] = blocker
+	ns.SetAnnotations(annotations)
+	return r.client.Patch(context.TODO(), ns, patch)
+}
+
+//reportNamespaceDeletionUnblocked records a Normal event on the cluster namespace if its deletion was
+//blocked
+func (r *ReconcileManagedCluster) reportNamespaceDeletionUnblocked(ns *corev1.Namespace) {
+	blocker, ok := ns.GetAnnotations()[namespaceDeletionBlockedAnnotation]
+	if !ok {
+		return
+	}
+	r.recordEvent(ns, corev1.EventTypeNormal, reasonNamespaceDeletionUnblocked,
+		fmt.Sprintf("The %s is gone, deleting the namespace", blocker))
+}
@@ -0,0 +1,73 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileManagedCluster_deleteNamespace_blocked(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(hivev1.SchemeGroupVersion, &hivev1.ClusterDeployment{})
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "mycluster",
+			Labels: map[string]string{clusterLabel: "mycluster"},
+		},
+	}
+	clusterDeployment := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "mycluster",
+			Namespace:  "mycluster",
+			Finalizers: []string{managedClusterFinalizer},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileManagedCluster{
+		client:   fake.NewFakeClientWithScheme(testscheme, ns, clusterDeployment),
+		scheme:   testscheme,
+		recorder: recorder,
+	}
+
+	//Blocked by the ClusterDeployment
+	if err := r.deleteNamespace("mycluster"); err == nil {
+		t.Fatal("expected an error as the ClusterDeployment exists")
+	}
+	got := &corev1.Namespace{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if v := got.GetAnnotations()[namespaceDeletionBlockedAnnotation]; v != "ClusterDeployment mycluster/mycluster" {
+		t.Errorf("annotation = %q, want the ClusterDeployment", v)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, reasonNamespaceDeletionBlocked) {
+		t.Errorf("expected a %s event, got %q", reasonNamespaceDeletionBlocked, event)
+	}
+
+	//Unblocked once the ClusterDeployment is gone
+	if err := r.client.Delete(context.TODO(), clusterDeployment); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.deleteNamespace("mycluster"); err != nil {
+		t.Fatalf("deleteNamespace() unexpected error %v", err)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, reasonNamespaceDeletionUnblocked) {
+		t.Errorf("expected a %s event, got %q", reasonNamespaceDeletionUnblocked, event)
+	}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, &corev1.Namespace{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the namespace to be deleted, got %v", err)
+	}
+}
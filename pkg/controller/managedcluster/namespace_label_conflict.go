@@ -0,0 +1,50 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const reasonClusterNamespaceLabelConflict string = "ClusterNamespaceLabelConflict"
+
+//clusterNamespaceLabelConflictRequeueAfter is the delay before checking again a conflicting namespace,
+//the namespaces are not watched
+const clusterNamespaceLabelConflictRequeueAfter = 1 * time.Minute
+
+//getClusterNamespaceLabelConflict returns the cluster the namespace is labeled for if it's not the
+//ManagedCluster, an empty string if the label is missing or matches
+func getClusterNamespaceLabelConflict(ns *corev1.Namespace, managedCluster *clusterv1.ManagedCluster) string {
+	owner, ok := ns.GetLabels()[clusterLabel]
+	if !ok || owner == managedCluster.Name {
+		return ""
+	}
+	return owner
+}
+
+//reportClusterNamespaceLabelConflict stops the import of a ManagedCluster whose namespace is labeled for
+//another cluster, the label is not overwritten as the namespace may be reused by mistake
+func (r *ReconcileManagedCluster) reportClusterNamespaceLabelConflict(
+	managedCluster *clusterv1.ManagedCluster,
+	owner string,
+) (reconcile.Result, error) {
+	message := fmt.Sprintf("The namespace %s is labeled %s=%s, it belongs to another cluster",
+		managedCluster.Name, clusterLabel, owner)
+	log.Info(message, "managedcluster", managedCluster.Name)
+	r.recordEvent(managedCluster, corev1.EventTypeWarning, reasonClusterNamespaceLabelConflict, message)
+	if err := r.setCondition(managedCluster, metav1.Condition{
+		Type:    importConditionType(),
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonClusterNamespaceLabelConflict,
+		Message: message,
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true, RequeueAfter: clusterNamespaceLabelConflictRequeueAfter}, nil
+}
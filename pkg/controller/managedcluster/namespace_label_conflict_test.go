@@ -0,0 +1,91 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileManagedCluster_Reconcile_namespaceLabelConflict(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		wantConf string
+	}{
+		{
+			name:     "label missing",
+			wantConf: "",
+		},
+		{
+			name:     "label of the cluster",
+			labels:   map[string]string{clusterLabel: "mycluster"},
+			wantConf: "",
+		},
+		{
+			name:     "label of another cluster",
+			labels:   map[string]string{clusterLabel: "othercluster"},
+			wantConf: "othercluster",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mycluster",
+				},
+			}
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "mycluster",
+					Labels: tt.labels,
+				},
+			}
+			if got := getClusterNamespaceLabelConflict(ns, managedCluster); got != tt.wantConf {
+				t.Errorf("getClusterNamespaceLabelConflict() = %s, want %s", got, tt.wantConf)
+			}
+			if tt.wantConf == "" {
+				return
+			}
+
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, managedCluster, ns),
+				scheme: testscheme,
+			}
+			res, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "mycluster"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.RequeueAfter != clusterNamespaceLabelConflictRequeueAfter {
+				t.Errorf("Reconcile() RequeueAfter = %s, want %s", res.RequeueAfter, clusterNamespaceLabelConflictRequeueAfter)
+			}
+			mc := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+				t.Fatal(err)
+			}
+			c := meta.FindStatusCondition(mc.Status.Conditions, ManagedClusterImportSucceeded)
+			if c == nil || c.Reason != reasonClusterNamespaceLabelConflict {
+				t.Errorf("expected the condition reason %s, got %v", reasonClusterNamespaceLabelConflict, c)
+			}
+			gotNs := &corev1.Namespace{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, gotNs); err != nil {
+				t.Fatal(err)
+			}
+			if gotNs.Labels[clusterLabel] != tt.wantConf {
+				t.Errorf("the namespace label was overwritten: %s", gotNs.Labels[clusterLabel])
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+//namespaceLabelsAnnotation records the keys of the cluster namespace labels set from NAMESPACE_LABELS,
+//only these labels are updated or removed when the configuration changes
+const namespaceLabelsAnnotation = "import.open-cluster-management.io/namespace-labels"
+
+//getNamespaceLabels returns the labels ensured on the cluster namespaces, the clusterLabel is reserved
+func getNamespaceLabels() map[string]string {
+	labels := parsePropagatedMetadata(namespaceLabelsEnvVarName, os.Getenv(namespaceLabelsEnvVarName), true)
+	delete(labels, clusterLabel)
+	return labels
+}
+
+//mergeNamespaceLabels adds the clusterLabel and the configured labels to the cluster namespace. A label
+//set by someone else is never overwritten, the configured labels previously set by the controller are
+//updated or removed. It returns true if the namespace changed.
+func mergeNamespaceLabels(ns *corev1.Namespace, clusterName string, configured map[string]string) bool {
+	changed := false
+	labels := ns.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	if _, ok := labels[clusterLabel]; !ok {
+		labels[clusterLabel] = clusterName
+		changed = true
+	}
+
+	owned := make(map[string]bool)
+	for _, k := range strings.Split(ns.GetAnnotations()[namespaceLabelsAnnotation], ",") {
+		if k != "" {
+			owned[k] = true
+		}
+	}
+	keys := make([]string, 0, len(configured))
+	for k, v := range configured {
+		current, ok := labels[k]
+		if ok && !owned[k] {
+			continue
+		}
+		if !ok || current != v {
+			labels[k] = v
+			changed = true
+		}
+		keys = append(keys, k)
+	}
+	for k := range owned {
+		if _, ok := configured[k]; ok {
+			continue
+		}
+		if _, ok := labels[k]; ok {
+			delete(labels, k)
+			changed = true
+		}
+	}
+	ns.SetLabels(labels)
+
+	sort.Strings(keys)
+	annotations := ns.GetAnnotations()
+	if value := strings.Join(keys, ","); value != annotations[namespaceLabelsAnnotation] {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		if value == "" {
+			delete(annotations, namespaceLabelsAnnotation)
+		} else {
+			annotations[namespaceLabelsAnnotation] = value
+		}
+		ns.SetAnnotations(annotations)
+		changed = true
+	}
+	return changed
+}
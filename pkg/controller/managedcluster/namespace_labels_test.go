@@ -0,0 +1,136 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getNamespaceLabels(t *testing.T) {
+	os.Setenv(namespaceLabelsEnvVarName,
+		`{"pod-security.kubernetes.io/enforce":"baseline","`+clusterLabel+`":"intruder","bad key":"v"}`)
+	defer os.Unsetenv(namespaceLabelsEnvVarName)
+	want := map[string]string{"pod-security.kubernetes.io/enforce": "baseline"}
+	if got := getNamespaceLabels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("getNamespaceLabels() = %v, want %v", got, want)
+	}
+}
+
+func Test_mergeNamespaceLabels(t *testing.T) {
+	tests := []struct {
+		name            string
+		labels          map[string]string
+		annotations     map[string]string
+		configured      map[string]string
+		wantChanged     bool
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "cluster label added",
+			wantChanged:     true,
+			wantLabels:      map[string]string{clusterLabel: "cluster"},
+			wantAnnotations: nil,
+		},
+		{
+			name:            "up to date",
+			labels:          map[string]string{clusterLabel: "cluster"},
+			wantChanged:     false,
+			wantLabels:      map[string]string{clusterLabel: "cluster"},
+			wantAnnotations: nil,
+		},
+		{
+			name:        "configured labels added",
+			labels:      map[string]string{clusterLabel: "cluster", "team": "a"},
+			configured:  map[string]string{"pod-security.kubernetes.io/enforce": "baseline", "netpol": "cluster"},
+			wantChanged: true,
+			wantLabels: map[string]string{clusterLabel: "cluster", "team": "a",
+				"pod-security.kubernetes.io/enforce": "baseline", "netpol": "cluster"},
+			wantAnnotations: map[string]string{
+				namespaceLabelsAnnotation: "netpol,pod-security.kubernetes.io/enforce"},
+		},
+		{
+			name: "configured labels up to date",
+			labels: map[string]string{clusterLabel: "cluster",
+				"pod-security.kubernetes.io/enforce": "baseline"},
+			annotations: map[string]string{namespaceLabelsAnnotation: "pod-security.kubernetes.io/enforce"},
+			configured:  map[string]string{"pod-security.kubernetes.io/enforce": "baseline"},
+			wantChanged: false,
+			wantLabels: map[string]string{clusterLabel: "cluster",
+				"pod-security.kubernetes.io/enforce": "baseline"},
+			wantAnnotations: map[string]string{namespaceLabelsAnnotation: "pod-security.kubernetes.io/enforce"},
+		},
+		{
+			name: "configured label updated",
+			labels: map[string]string{clusterLabel: "cluster",
+				"pod-security.kubernetes.io/enforce": "baseline"},
+			annotations: map[string]string{namespaceLabelsAnnotation: "pod-security.kubernetes.io/enforce"},
+			configured:  map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+			wantChanged: true,
+			wantLabels: map[string]string{clusterLabel: "cluster",
+				"pod-security.kubernetes.io/enforce": "restricted"},
+			wantAnnotations: map[string]string{namespaceLabelsAnnotation: "pod-security.kubernetes.io/enforce"},
+		},
+		{
+			name:            "user label kept",
+			labels:          map[string]string{clusterLabel: "cluster", "pod-security.kubernetes.io/enforce": "privileged"},
+			configured:      map[string]string{"pod-security.kubernetes.io/enforce": "baseline"},
+			wantChanged:     false,
+			wantLabels:      map[string]string{clusterLabel: "cluster", "pod-security.kubernetes.io/enforce": "privileged"},
+			wantAnnotations: nil,
+		},
+		{
+			name:            "cluster label of another cluster kept",
+			labels:          map[string]string{clusterLabel: "other"},
+			wantChanged:     false,
+			wantLabels:      map[string]string{clusterLabel: "other"},
+			wantAnnotations: nil,
+		},
+		{
+			name:            "label removed from the configuration",
+			labels:          map[string]string{clusterLabel: "cluster", "netpol": "cluster", "team": "a"},
+			annotations:     map[string]string{namespaceLabelsAnnotation: "netpol", "other": "kept"},
+			wantChanged:     true,
+			wantLabels:      map[string]string{clusterLabel: "cluster", "team": "a"},
+			wantAnnotations: map[string]string{"other": "kept"},
+		},
+		{
+			name:            "label removed by the user",
+			labels:          map[string]string{clusterLabel: "cluster"},
+			annotations:     map[string]string{namespaceLabelsAnnotation: "netpol"},
+			configured:      map[string]string{"netpol": "cluster"},
+			wantChanged:     true,
+			wantLabels:      map[string]string{clusterLabel: "cluster", "netpol": "cluster"},
+			wantAnnotations: map[string]string{namespaceLabelsAnnotation: "netpol"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster",
+					Labels:      tt.labels,
+					Annotations: tt.annotations,
+				},
+			}
+			if got := mergeNamespaceLabels(ns, "cluster", tt.configured); got != tt.wantChanged {
+				t.Errorf("mergeNamespaceLabels() = %v, want %v", got, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(ns.Labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", ns.Labels, tt.wantLabels)
+			}
+			annotations := ns.Annotations
+			if len(annotations) == 0 {
+				annotations = nil
+			}
+			if !reflect.DeepEqual(annotations, tt.wantAnnotations) {
+				t.Errorf("annotations = %v, want %v", ns.Annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
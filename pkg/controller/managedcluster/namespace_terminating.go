@@ -0,0 +1,56 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const reasonWaitingForNamespaceTermination string = "WaitingForNamespaceTermination"
+
+//reasonClusterNamespaceTerminating was set by the previous versions while the namespace was terminating,
+//it is still cleared once the namespace is recreated
+const reasonClusterNamespaceTerminating string = "ClusterNamespaceTerminating"
+
+//isNamespaceTerminating returns true if the creation of a resource was rejected because its namespace
+//is being deleted, older apiservers only report it in the message of the forbidden error
+func isNamespaceTerminating(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		return true
+	}
+	return errors.IsForbidden(err) && strings.Contains(err.Error(), "because it is being terminated")
+}
+
+//waitForClusterNamespaceTermination reports the cluster namespace is terminating and requeues the
+//ManagedCluster with the rate limiter backoff, the import resumes once the namespace is recreated
+func (r *ReconcileManagedCluster) waitForClusterNamespaceTermination(
+	managedCluster *clusterv1.ManagedCluster,
+	errIn error,
+) (reconcile.Result, error) {
+	message := fmt.Sprintf("The namespace %s is terminating, waiting for it to be recreated", managedCluster.Name)
+	if errIn != nil {
+		message = fmt.Sprintf("%s: %s", message, errIn.Error())
+	}
+	if logSubsystemNamespace.V(2) {
+		log.Info(message, "managedcluster", managedCluster.Name)
+	}
+	if err := r.setCondition(managedCluster, metav1.Condition{
+		Type:    importConditionType(),
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonWaitingForNamespaceTermination,
+		Message: message,
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
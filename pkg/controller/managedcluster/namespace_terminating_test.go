@@ -0,0 +1,103 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_isNamespaceTerminating(t *testing.T) {
+	withCause := errors.NewForbidden(schema.GroupResource{Resource: "serviceaccounts"}, "mycluster-bootstrap-sa",
+		fmt.Errorf("unable to create new content in namespace mycluster because it is being terminated"))
+	withCause.ErrStatus.Details.Causes = []metav1.StatusCause{{Type: corev1.NamespaceTerminatingCause}}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "no error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "namespace terminating cause",
+			err:  withCause,
+			want: true,
+		},
+		{
+			name: "forbidden with the terminating message",
+			err: errors.NewForbidden(schema.GroupResource{Resource: "serviceaccounts"}, "mycluster-bootstrap-sa",
+				fmt.Errorf("unable to create new content in namespace mycluster because it is being terminated")),
+			want: true,
+		},
+		{
+			name: "other forbidden error",
+			err: errors.NewForbidden(schema.GroupResource{Resource: "serviceaccounts"}, "mycluster-bootstrap-sa",
+				fmt.Errorf("user cannot create serviceaccounts")),
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("because it is being terminated"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNamespaceTerminating(tt.err); got != tt.want {
+				t.Errorf("isNamespaceTerminating() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_waitForClusterNamespaceTermination(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	result, err := r.waitForClusterNamespaceTermination(managedCluster, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Requeue {
+		t.Errorf("expected a requeue")
+	}
+	mc := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, mc); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(mc.Status.Conditions, ManagedClusterImportSucceeded)
+	if c == nil || c.Reason != reasonWaitingForNamespaceTermination {
+		t.Errorf("expected the condition reason %s, got %v", reasonWaitingForNamespaceTermination, c)
+	}
+
+	//the condition is removed once the namespace is recreated
+	if err := r.clearConditionImportWaiting(mc, reasonWaitingForClusterNamespace,
+		reasonWaitingForNamespaceTermination); err != nil {
+		t.Fatal(err)
+	}
+	if meta.FindStatusCondition(mc.Status.Conditions, ManagedClusterImportSucceeded) != nil {
+		t.Errorf("expected the condition to be removed")
+	}
+}
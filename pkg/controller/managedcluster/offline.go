@@ -0,0 +1,35 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//IsOffline returns true if the ManagedCluster has to be imported with the import secret or the
+//auto-import rather than with the klusterlet manifestworks: its Available condition is missing, False
+//or Unknown. A cluster whose Available condition turned False or Unknown less than gracePeriod before
+//now is still online, so a short disconnection doesn't switch the import path. The availability-override
+//annotation takes precedence when it is allowed.
+func IsOffline(managedCluster *clusterv1.ManagedCluster, gracePeriod time.Duration, now time.Time) bool {
+	if offLine, ok := availabilityOverride(managedCluster); ok {
+		return offLine
+	}
+	available := meta.FindStatusCondition(managedCluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	if available == nil {
+		return true
+	}
+	if available.Status != metav1.ConditionUnknown && available.Status != metav1.ConditionFalse {
+		return false
+	}
+	return gracePeriod <= 0 || !now.Before(available.LastTransitionTime.Add(gracePeriod))
+}
+
+//checkOffLine returns true if the ManagedCluster is offline with the OFFLINE_GRACE_PERIOD of the controller
+func checkOffLine(managedCluster *clusterv1.ManagedCluster) bool {
+	return IsOffline(managedCluster, getEnvDuration(offlineGracePeriodEnvVarName, 0), time.Now())
+}
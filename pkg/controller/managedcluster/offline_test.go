@@ -0,0 +1,123 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsOffline(t *testing.T) {
+	now := time.Now()
+	newManagedCluster := func(status metav1.ConditionStatus, transition time.Time) *clusterv1.ManagedCluster {
+		mc := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "mycluster",
+			},
+		}
+		if status != "" {
+			mc.Status.Conditions = []metav1.Condition{
+				{
+					Type:               clusterv1.ManagedClusterConditionAvailable,
+					Status:             status,
+					LastTransitionTime: metav1.NewTime(transition),
+				},
+			}
+		}
+		return mc
+	}
+
+	tests := []struct {
+		name           string
+		managedCluster *clusterv1.ManagedCluster
+		gracePeriod    time.Duration
+		want           bool
+	}{
+		{
+			name:           "no condition",
+			managedCluster: newManagedCluster("", now),
+			want:           true,
+		},
+		{
+			name:           "no condition within the grace period",
+			managedCluster: newManagedCluster("", now),
+			gracePeriod:    time.Minute,
+			want:           true,
+		},
+		{
+			name:           "unknown",
+			managedCluster: newManagedCluster(metav1.ConditionUnknown, now.Add(-time.Hour)),
+			want:           true,
+		},
+		{
+			name:           "false",
+			managedCluster: newManagedCluster(metav1.ConditionFalse, now.Add(-time.Hour)),
+			want:           true,
+		},
+		{
+			name:           "true",
+			managedCluster: newManagedCluster(metav1.ConditionTrue, now.Add(-time.Hour)),
+			want:           false,
+		},
+		{
+			name:           "unknown within the grace period",
+			managedCluster: newManagedCluster(metav1.ConditionUnknown, now.Add(-30*time.Second)),
+			gracePeriod:    time.Minute,
+			want:           false,
+		},
+		{
+			name:           "false within the grace period",
+			managedCluster: newManagedCluster(metav1.ConditionFalse, now.Add(-30*time.Second)),
+			gracePeriod:    time.Minute,
+			want:           false,
+		},
+		{
+			name:           "false after the grace period",
+			managedCluster: newManagedCluster(metav1.ConditionFalse, now.Add(-2*time.Minute)),
+			gracePeriod:    time.Minute,
+			want:           true,
+		},
+		{
+			name:           "false at the end of the grace period",
+			managedCluster: newManagedCluster(metav1.ConditionFalse, now.Add(-time.Minute)),
+			gracePeriod:    time.Minute,
+			want:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOffline(tt.managedCluster, tt.gracePeriod, now); got != tt.want {
+				t.Errorf("IsOffline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkOffLine_gracePeriod(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:               clusterv1.ManagedClusterConditionAvailable,
+					Status:             metav1.ConditionUnknown,
+					LastTransitionTime: metav1.NewTime(time.Now()),
+				},
+			},
+		},
+	}
+	if !checkOffLine(managedCluster) {
+		t.Error("expected the cluster to be offline without grace period")
+	}
+	os.Setenv(offlineGracePeriodEnvVarName, "5m")
+	defer os.Unsetenv(offlineGracePeriodEnvVarName)
+	if checkOffLine(managedCluster) {
+		t.Error("expected the cluster to be online within the grace period")
+	}
+}
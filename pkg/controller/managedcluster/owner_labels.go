@@ -0,0 +1,104 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//The owner labels identify the import artifacts (import secret, manifestworks) of a cluster in any namespace,
+//the artifacts outside the cluster namespace are not removed with it and are deleted by label
+const (
+	ownerClusterNameLabel = "import.open-cluster-management.io/owner-cluster-name"
+	ownerClusterUIDLabel  = "import.open-cluster-management.io/owner-cluster-uid"
+)
+
+//ownerLabels returns the owner labels of the artifacts of the cluster
+func ownerLabels(managedCluster *clusterv1.ManagedCluster) map[string]string {
+	return map[string]string{
+		ownerClusterNameLabel: managedCluster.Name,
+		ownerClusterUIDLabel:  string(managedCluster.UID),
+	}
+}
+
+//setOwnerLabels sets the owner labels of the cluster on the object, it returns true if they changed
+func setOwnerLabels(obj metav1.Object, managedCluster *clusterv1.ManagedCluster) bool {
+	labels := obj.GetLabels()
+	changed := false
+	for k, v := range ownerLabels(managedCluster) {
+		if current, ok := labels[k]; ok && current == v {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[k] = v
+		changed = true
+	}
+	if changed {
+		obj.SetLabels(labels)
+	}
+	return changed
+}
+
+//deleteOwnedArtifacts deletes the import secrets and the manifestworks labeled for the cluster outside
+//of the cluster namespace, the ones of the cluster namespace are removed with it. The secrets are listed
+//without cache so the controller doesn't cache the secrets of all namespaces.
+func (r *ReconcileManagedCluster) deleteOwnedArtifacts(managedCluster *clusterv1.ManagedCluster) error {
+	selector := client.MatchingLabels(ownerLabels(managedCluster))
+
+	var reader client.Reader = r.client
+	if r.apiReader != nil {
+		reader = r.apiReader
+	}
+	secrets := &corev1.SecretList{}
+	if err := reader.List(context.TODO(), secrets, selector); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if err := r.deleteOwnedArtifact(managedCluster, "Secret", &secrets.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	manifestWorks := &workv1.ManifestWorkList{}
+	if err := r.client.List(context.TODO(), manifestWorks, selector); err != nil {
+		return err
+	}
+	for i := range manifestWorks.Items {
+		if err := r.deleteOwnedArtifact(managedCluster, "ManifestWork", &manifestWorks.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//ownedArtifact is an import artifact, a secret or a manifestwork
+type ownedArtifact interface {
+	metav1.Object
+	runtime.Object
+}
+
+func (r *ReconcileManagedCluster) deleteOwnedArtifact(
+	managedCluster *clusterv1.ManagedCluster,
+	kind string,
+	obj ownedArtifact,
+) error {
+	if obj.GetNamespace() == managedCluster.Name {
+		return nil
+	}
+	log.Info("Deleting the import artifact of the cluster", "managedcluster", managedCluster.Name,
+		"kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+	if err := r.client.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
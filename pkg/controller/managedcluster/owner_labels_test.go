@@ -0,0 +1,119 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_setOwnerLabels(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+			UID:  "uid1",
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{clusterLabel: "cluster1", ownerClusterUIDLabel: "previous-uid"},
+		},
+	}
+	if !setOwnerLabels(secret, managedCluster) {
+		t.Error("expected the owner labels to change")
+	}
+	want := map[string]string{
+		clusterLabel:          "cluster1",
+		ownerClusterNameLabel: "cluster1",
+		ownerClusterUIDLabel:  "uid1",
+	}
+	for k, v := range want {
+		if secret.Labels[k] != v {
+			t.Errorf("expected the label %s=%s, got %v", k, v, secret.Labels)
+		}
+	}
+	if setOwnerLabels(secret, managedCluster) {
+		t.Error("expected the owner labels to be unchanged")
+	}
+}
+
+func TestReconcileManagedCluster_deleteOwnedArtifacts(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+			UID:  "uid1",
+		},
+	}
+	otherCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+			UID:  "uid0",
+		},
+	}
+	newSecret := func(name, namespace string, owner *clusterv1.ManagedCluster) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+		if owner != nil {
+			setOwnerLabels(secret, owner)
+		}
+		return secret
+	}
+	mw := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1-klusterlet",
+			Namespace: "other",
+		},
+	}
+	setOwnerLabels(mw, managedCluster)
+
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme,
+			newSecret("owned", "import-secrets", managedCluster),
+			newSecret("in-cluster-namespace", "cluster1", managedCluster),
+			newSecret("not-owned", "import-secrets", nil),
+			newSecret("previous-cluster", "other", otherCluster),
+			mw,
+		),
+		scheme: testscheme,
+	}
+	if err := r.deleteOwnedArtifacts(managedCluster); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		namespace string
+		deleted   bool
+	}{
+		{name: "owned", namespace: "import-secrets", deleted: true},
+		{name: "in-cluster-namespace", namespace: "cluster1", deleted: false},
+		{name: "not-owned", namespace: "import-secrets", deleted: false},
+		{name: "previous-cluster", namespace: "other", deleted: false},
+	} {
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: tt.name, Namespace: tt.namespace}, &corev1.Secret{})
+		if errors.IsNotFound(err) != tt.deleted {
+			t.Errorf("secret %s/%s: expected deleted %t, got %v", tt.namespace, tt.name, tt.deleted, err)
+		}
+	}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: mw.Name, Namespace: mw.Namespace}, &workv1.ManifestWork{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the manifestwork to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"strconv"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//pausedAnnotation set to "true" on a ManagedCluster freezes its import, only the deletion is handled
+const pausedAnnotation = "import.open-cluster-management.io/paused"
+
+const (
+	ConditionReconciliationPaused string = "ReconciliationPaused"
+	reasonReconciliationPaused    string = "ReconciliationPaused"
+	reasonReconciliationResumed   string = "ReconciliationResumed"
+)
+
+func isPaused(managedCluster *clusterv1.ManagedCluster) bool {
+	v, ok := managedCluster.GetAnnotations()[pausedAnnotation]
+	if !ok {
+		return false
+	}
+	paused, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Info("Invalid paused annotation, the reconciliation is not paused",
+			"managedcluster", managedCluster.Name, "value", v)
+		return false
+	}
+	return paused
+}
+
+//setConditionPaused reports if the reconciliation is paused, the condition is only set
+//when the cluster is paused or resumed after being paused
+func (r *ReconcileManagedCluster) setConditionPaused(managedCluster *clusterv1.ManagedCluster, paused bool) error {
+	current := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionReconciliationPaused)
+	wasPaused := current != nil && current.Status == metav1.ConditionTrue
+	switch {
+	case paused && !wasPaused:
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionReconciliationPaused,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonReconciliationPaused,
+			Message: "The import is paused by the annotation " + pausedAnnotation,
+		})
+	case !paused && wasPaused:
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionReconciliationPaused,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonReconciliationResumed,
+			Message: "The import is reconciled",
+		})
+	}
+	return nil
+}
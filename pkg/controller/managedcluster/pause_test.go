@@ -0,0 +1,116 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileManagedCluster_Reconcile_paused(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pausedcluster",
+			Annotations: map[string]string{pausedAnnotation: "true"},
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: managedCluster.Name}})
+	if err != nil {
+		t.Errorf("Reconcile() error = %v", err)
+		return
+	}
+	if got.Requeue {
+		t.Errorf("Reconcile() = %v, a paused cluster must not be requeued", got)
+	}
+	instance := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: managedCluster.Name}, instance); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(instance.Finalizers) != 0 {
+		t.Errorf("expected no finalizer on a paused cluster, got %v", instance.Finalizers)
+	}
+	if !meta.IsStatusConditionTrue(instance.Status.Conditions, ConditionReconciliationPaused) {
+		t.Errorf("expected condition %s to be true, got %v", ConditionReconciliationPaused, instance.Status.Conditions)
+	}
+}
+
+func Test_setConditionPaused(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pausecondition",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+	steps := []struct {
+		name        string
+		paused      bool
+		wantPresent bool
+		wantReason  string
+	}{
+		{name: "never paused", paused: false, wantPresent: false},
+		{name: "paused", paused: true, wantPresent: true, wantReason: reasonReconciliationPaused},
+		{name: "resumed", paused: false, wantPresent: true, wantReason: reasonReconciliationResumed},
+	}
+	for _, step := range steps {
+		if err := r.setConditionPaused(managedCluster, step.paused); err != nil {
+			t.Errorf("%s: setConditionPaused() error = %v", step.name, err)
+			return
+		}
+		c := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionReconciliationPaused)
+		if (c != nil) != step.wantPresent {
+			t.Errorf("%s: expected condition present %v, got %v", step.name, step.wantPresent, managedCluster.Status.Conditions)
+			continue
+		}
+		if c != nil && c.Reason != step.wantReason {
+			t.Errorf("%s: expected reason %s, got %s", step.name, step.wantReason, c.Reason)
+		}
+	}
+}
+
+func Test_isPaused(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{name: "no annotation", want: false},
+		{name: "paused", annotations: map[string]string{pausedAnnotation: "true"}, want: true},
+		{name: "not paused", annotations: map[string]string{pausedAnnotation: "false"}, want: false},
+		{name: "invalid", annotations: map[string]string{pausedAnnotation: "yes please"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster",
+					Annotations: tt.annotations,
+				},
+			}
+			if got := isPaused(managedCluster); got != tt.want {
+				t.Errorf("isPaused() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"encoding/json"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+)
+
+const (
+	//propagatedLabelsAnnotation is a JSON object of labels added to the resources generated for the cluster
+	propagatedLabelsAnnotation = "import.open-cluster-management.io/propagated-labels"
+	//propagatedAnnotationsAnnotation is a JSON object of annotations added to the resources generated for the cluster
+	propagatedAnnotationsAnnotation = "import.open-cluster-management.io/propagated-annotations"
+)
+
+//propagatedMetadata are the labels and annotations added to the import secret, the manifestworks
+//and the bootstrap service account and RBAC
+type propagatedMetadata struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+//getPropagatedMetadata merges the metadata set on the controller with the one set on the ManagedCluster,
+//the ManagedCluster values take precedence
+func getPropagatedMetadata(managedCluster *clusterv1.ManagedCluster) propagatedMetadata {
+	m := propagatedMetadata{
+		labels:      parsePropagatedMetadata(propagatedLabelsEnvVarName, os.Getenv(propagatedLabelsEnvVarName), true),
+		annotations: parsePropagatedMetadata(propagatedAnnotationsEnvVarName, os.Getenv(propagatedAnnotationsEnvVarName), false),
+	}
+	annotations := managedCluster.GetAnnotations()
+	for k, v := range parsePropagatedMetadata(propagatedLabelsAnnotation, annotations[propagatedLabelsAnnotation], true) {
+		m.labels[k] = v
+	}
+	for k, v := range parsePropagatedMetadata(propagatedAnnotationsAnnotation, annotations[propagatedAnnotationsAnnotation], false) {
+		m.annotations[k] = v
+	}
+	return m
+}
+
+//parsePropagatedMetadata parses a JSON object of string values, invalid keys or label values are skipped
+func parsePropagatedMetadata(source, v string, isLabel bool) map[string]string {
+	result := make(map[string]string)
+	if v == "" {
+		return result
+	}
+	values := make(map[string]string)
+	if err := json.Unmarshal([]byte(v), &values); err != nil {
+		log.Info("Invalid propagated metadata, ignoring it", "source", source, "error", err.Error())
+		return result
+	}
+	for k, v := range values {
+		if errs := validation.IsQualifiedName(k); len(errs) != 0 {
+			log.Info("Invalid propagated metadata key, skipping it", "source", source, "key", k, "errors", errs)
+			continue
+		}
+		if isLabel {
+			if errs := validation.IsValidLabelValue(v); len(errs) != 0 {
+				log.Info("Invalid propagated label value, skipping it", "source", source, "key", k, "errors", errs)
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+//apply adds the propagated metadata to the object, the existing keys are never overwritten
+//so the labels and annotations required by the controller are kept. It returns true if the object changed.
+func (m propagatedMetadata) apply(obj metav1.Object) bool {
+	labels, labelsChanged := mergeMissing(obj.GetLabels(), m.labels)
+	annotations, annotationsChanged := mergeMissing(obj.GetAnnotations(), m.annotations)
+	if labelsChanged {
+		obj.SetLabels(labels)
+	}
+	if annotationsChanged {
+		obj.SetAnnotations(annotations)
+	}
+	return labelsChanged || annotationsChanged
+}
+
+//mergeMissing adds to dst the keys of src missing in dst
+func mergeMissing(dst, src map[string]string) (map[string]string, bool) {
+	changed := false
+	for k, v := range src {
+		if _, ok := dst[k]; ok {
+			continue
+		}
+		if dst == nil {
+			dst = make(map[string]string)
+		}
+		dst[k] = v
+		changed = true
+	}
+	return dst, changed
+}
@@ -0,0 +1,140 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/open-cluster-management/applier/pkg/templateprocessor"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+func Test_getPropagatedMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		envLabels       string
+		envAnnotations  string
+		annotations     map[string]string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "nothing set",
+			wantLabels:      map[string]string{},
+			wantAnnotations: map[string]string{},
+		},
+		{
+			name:            "controller config",
+			envLabels:       `{"cost-center":"1234"}`,
+			envAnnotations:  `{"example.com/owner":"team a"}`,
+			wantLabels:      map[string]string{"cost-center": "1234"},
+			wantAnnotations: map[string]string{"example.com/owner": "team a"},
+		},
+		{
+			name:      "cluster annotation takes precedence",
+			envLabels: `{"cost-center":"1234","env":"prod"}`,
+			annotations: map[string]string{
+				propagatedLabelsAnnotation:      `{"cost-center":"5678"}`,
+				propagatedAnnotationsAnnotation: `{"example.com/owner":"team b"}`,
+			},
+			wantLabels:      map[string]string{"cost-center": "5678", "env": "prod"},
+			wantAnnotations: map[string]string{"example.com/owner": "team b"},
+		},
+		{
+			name: "invalid entries skipped",
+			annotations: map[string]string{
+				propagatedLabelsAnnotation:      `{"cost-center":"not a label value","bad key!":"v","env":"prod"}`,
+				propagatedAnnotationsAnnotation: `not json`,
+			},
+			wantLabels:      map[string]string{"env": "prod"},
+			wantAnnotations: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(propagatedLabelsEnvVarName, tt.envLabels)
+			defer os.Unsetenv(propagatedLabelsEnvVarName)
+			os.Setenv(propagatedAnnotationsEnvVarName, tt.envAnnotations)
+			defer os.Unsetenv(propagatedAnnotationsEnvVarName)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster",
+					Annotations: tt.annotations,
+				},
+			}
+			got := getPropagatedMetadata(managedCluster)
+			if !reflect.DeepEqual(got.labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", got.labels, tt.wantLabels)
+			}
+			if !reflect.DeepEqual(got.annotations, tt.wantAnnotations) {
+				t.Errorf("annotations = %v, want %v", got.annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
+
+func Test_propagatedMetadata_apply(t *testing.T) {
+	m := propagatedMetadata{
+		labels:      map[string]string{"cost-center": "1234", clusterLabel: "intruder"},
+		annotations: map[string]string{"example.com/owner": "team a"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cluster-import",
+			Labels: map[string]string{clusterLabel: "cluster"},
+		},
+	}
+	if !m.apply(secret) {
+		t.Errorf("apply() = false, expected the object to change")
+	}
+	wantLabels := map[string]string{"cost-center": "1234", clusterLabel: "cluster"}
+	if !reflect.DeepEqual(secret.Labels, wantLabels) {
+		t.Errorf("labels = %v, want %v", secret.Labels, wantLabels)
+	}
+	if secret.Annotations["example.com/owner"] != "team a" {
+		t.Errorf("annotations = %v, expected the propagated annotation", secret.Annotations)
+	}
+	if m.apply(secret) {
+		t.Errorf("apply() = true, expected no change on the second apply")
+	}
+}
+
+func Test_hubManifestsPropagatedMetadata(t *testing.T) {
+	os.Setenv(propagatedAnnotationsEnvVarName, `{"example.com/note":"a \"quoted\": value"}`)
+	defer os.Unsetenv(propagatedAnnotationsEnvVarName)
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mycluster",
+			Annotations: map[string]string{
+				propagatedLabelsAnnotation: `{"cost-center":"1234"}`,
+			},
+		},
+	}
+	tp, err := templateprocessor.NewTemplateProcessor(bindata.NewBindataReader(), &templateprocessor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := tp.TemplateResource(
+		"hub/managedcluster/manifests/managedcluster-service-account.yaml",
+		newHubManifestsValues(managedCluster))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := yaml.Unmarshal(result, sa); err != nil {
+		t.Fatal(err)
+	}
+	if sa.Labels["cost-center"] != "1234" {
+		t.Errorf("labels = %v, expected the propagated label", sa.Labels)
+	}
+	if sa.Annotations["example.com/note"] != `a "quoted": value` {
+		t.Errorf("annotations = %v, expected the propagated annotation", sa.Annotations)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const reasonProtectedNamespace = "ProtectedNamespace"
+
+//defaultProtectedNamespaces are the system and hub namespaces a ManagedCluster can't be named as,
+//a trailing "*" matches a prefix
+const defaultProtectedNamespaces = "default,kube-system,kube-public,kube-node-lease,openshift,openshift-*," +
+	"open-cluster-management,open-cluster-management-*"
+
+//protectedNamespaces returns the denylist of PROTECTED_NAMESPACES or the default one
+func protectedNamespaces() []string {
+	v := os.Getenv(protectedNamespacesEnvVarName)
+	if v == "" {
+		v = defaultProtectedNamespaces
+	}
+	namespaces := make([]string, 0)
+	for _, namespace := range strings.Split(v, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+//isProtectedNamespace returns true if the namespace is in the denylist, the controller must neither
+//label nor delete it
+func isProtectedNamespace(namespace string) bool {
+	for _, protected := range protectedNamespaces() {
+		if strings.HasSuffix(protected, "*") {
+			if strings.HasPrefix(namespace, strings.TrimSuffix(protected, "*")) {
+				return true
+			}
+			continue
+		}
+		if namespace == protected {
+			return true
+		}
+	}
+	return false
+}
+
+//reportProtectedNamespace sets the import condition of a ManagedCluster named as a protected namespace,
+//it is not imported
+func (r *ReconcileManagedCluster) reportProtectedNamespace(managedCluster *clusterv1.ManagedCluster) error {
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:   importConditionType(),
+		Status: metav1.ConditionFalse,
+		Reason: reasonProtectedNamespace,
+		Message: fmt.Sprintf("The cluster namespace %s is a protected namespace, the cluster is not imported",
+			managedCluster.Name),
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_isProtectedNamespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		namespace string
+		want      bool
+	}{
+		{
+			name:      "default denylist",
+			namespace: "kube-system",
+			want:      true,
+		},
+		{
+			name:      "default denylist prefix",
+			namespace: "openshift-config",
+			want:      true,
+		},
+		{
+			name:      "cluster namespace",
+			namespace: "cluster1",
+			want:      false,
+		},
+		{
+			name:      "custom denylist",
+			env:       "restricted, team-*",
+			namespace: "team-a",
+			want:      true,
+		},
+		{
+			name:      "custom denylist replaces the default one",
+			env:       "restricted",
+			namespace: "kube-system",
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(protectedNamespacesEnvVarName, tt.env)
+			defer os.Unsetenv(protectedNamespacesEnvVarName)
+			if got := isProtectedNamespace(tt.namespace); got != tt.want {
+				t.Errorf("isProtectedNamespace(%s) = %t, want %t", tt.namespace, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_Reconcile_protectedNamespace(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kube-system",
+		},
+	}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kube-system",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster, ns),
+		scheme: testscheme,
+	}
+
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "kube-system"}}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "kube-system"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Finalizers) != 0 {
+		t.Errorf("expected no finalizer on the protected cluster, got %v", got.Finalizers)
+	}
+	c := meta.FindStatusCondition(got.Status.Conditions, importConditionType())
+	if c == nil || c.Reason != reasonProtectedNamespace {
+		t.Errorf("expected the reason %s, got %v", reasonProtectedNamespace, got.Status.Conditions)
+	}
+	gotNs := &corev1.Namespace{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "kube-system"}, gotNs); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gotNs.Labels[clusterLabel]; ok {
+		t.Errorf("expected the protected namespace not to be labeled, got %v", gotNs.Labels)
+	}
+
+	//The namespace is not deleted with the cluster
+	if err := r.deleteNamespace("kube-system"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "kube-system"}, &corev1.Namespace{}); err != nil {
+		t.Errorf("expected the protected namespace to be kept, got %v", err)
+	}
+}
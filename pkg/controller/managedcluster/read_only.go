@@ -0,0 +1,49 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	libgometav1 "github.com/open-cluster-management/library-go/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+//isReadOnly returns true if the controller observes the ManagedClusters without mutating anything,
+//to run it in shadow mode against a production hub
+func isReadOnly() bool {
+	return getEnvBool(readOnlyEnvVarName, false)
+}
+
+//reconcileReadOnly logs the writes the reconcile of the ManagedCluster would have done
+func (r *ReconcileManagedCluster) reconcileReadOnly(reqLogger logr.Logger, managedCluster *clusterv1.ManagedCluster) error {
+	if managedCluster.DeletionTimestamp != nil {
+		reqLogger.Info("Read-only, would clean up the deleted ManagedCluster")
+		return nil
+	}
+	if !libgometav1.HasFinalizer(managedCluster, managedClusterFinalizer) {
+		reqLogger.Info("Read-only, would add the finalizer", "finalizer", managedClusterFinalizer)
+	}
+	if _, ok := managedCluster.GetLabels()["name"]; !ok {
+		reqLogger.Info("Read-only, would add the name label", "name", managedCluster.Name)
+	}
+
+	ns := &corev1.Namespace{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: managedCluster.Name}, ns)
+	switch {
+	case errors.IsNotFound(err):
+		reqLogger.Info("Read-only, the cluster namespace doesn't exist")
+	case err != nil:
+		return err
+	default:
+		if _, ok := ns.GetLabels()[clusterLabel]; !ok {
+			reqLogger.Info("Read-only, would label the cluster namespace", "label", clusterLabel)
+		}
+	}
+	reqLogger.Info("Read-only, would import the cluster")
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileManagedCluster_Reconcile_readOnly(t *testing.T) {
+	os.Setenv(readOnlyEnvVarName, "true")
+	defer os.Unsetenv(readOnlyEnvVarName)
+
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "shadow",
+		},
+	}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "shadow",
+		},
+	}
+	orphanNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "orphan",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster, ns, orphanNs),
+		scheme: testscheme,
+	}
+
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "shadow"}}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "shadow"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Finalizers) != 0 {
+		t.Errorf("expected no finalizer, got %v", got.Finalizers)
+	}
+	if len(got.Labels) != 0 {
+		t.Errorf("expected no label, got %v", got.Labels)
+	}
+	if len(got.Status.Conditions) != 0 {
+		t.Errorf("expected no condition, got %v", got.Status.Conditions)
+	}
+	gotNs := &corev1.Namespace{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "shadow"}, gotNs); err != nil {
+		t.Fatal(err)
+	}
+	if len(gotNs.Labels) != 0 {
+		t.Errorf("expected the namespace not to be labeled, got %v", gotNs.Labels)
+	}
+
+	//The namespace of a deleted cluster is kept
+	if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "orphan"}}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "orphan"}, &corev1.Namespace{}); err != nil {
+		t.Errorf("expected the namespace to be kept, got %v", err)
+	}
+}
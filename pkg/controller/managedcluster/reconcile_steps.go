@@ -0,0 +1,159 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	ConditionImportStepsCompleted string = "ImportStepsCompleted"
+	reasonImportStepsCompleted    string = "ImportStepsCompleted"
+	reasonImportStepFailed        string = "ImportStepFailed"
+)
+
+//The reconcile steps, named after the function doing the step
+const (
+	stepAddFinalizer                = "addFinalizer"
+	stepEnsureClusterNamespace      = "ensureClusterNamespace"
+	stepCreateServiceAccount        = "createServiceAccount"
+	stepRotateBootstrapToken        = "rotateBootstrapToken"
+	stepRepairBootstrapRBAC         = "repairBootstrapRBAC"
+	stepGenerateImportYAMLs         = "generateImportYAMLs"
+	stepApplyHubManifests           = "applyHubManifests"
+	stepCreateOrUpdateImportSecret  = "createOrUpdateImportSecret"
+	stepDeleteKlusterletSyncSets    = "deleteKlusterletSyncSets"
+	stepCreateOrUpdateManifestWorks = "createOrUpdateManifestWorks"
+	stepToBeImported                = "toBeImported"
+	stepImportCluster               = "importCluster"
+)
+
+//reconcilePhaseDuration times each step of the reconciles, to find the steps dominating
+//the reconcile latency under load
+var reconcilePhaseDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "managedcluster_import_reconcile_phase_duration_seconds",
+		Help:    "Duration of the steps of the ManagedCluster reconciles",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+	},
+	[]string{"phase"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcilePhaseDuration)
+}
+
+//reconcileSteps tracks the progress of a reconcile, the ManagedCluster is only set once the
+//reconcile goes through the import steps (not deleted nor paused)
+type reconcileSteps struct {
+	managedCluster *clusterv1.ManagedCluster
+	current        string
+	lastCompleted  string
+	completed      bool
+	err            error
+	//currentStarted is when the current step started, now and observe default to the clock
+	//and the reconcilePhaseDuration histogram
+	currentStarted time.Time
+	now            func() time.Time
+	observe        func(step string, d time.Duration)
+}
+
+//start marks the current step as completed and starts the next one
+func (s *reconcileSteps) start(step string) {
+	now := s.clock()
+	if s.current != "" {
+		s.lastCompleted = s.current
+		s.observeCurrent(now)
+	}
+	s.current = step
+	s.currentStarted = now
+}
+
+//stop records the duration of the current step when the reconcile returns before the end of the steps
+func (s *reconcileSteps) stop() {
+	if s.current != "" {
+		s.observeCurrent(s.clock())
+	}
+}
+
+func (s *reconcileSteps) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+func (s *reconcileSteps) observeCurrent(now time.Time) {
+	d := now.Sub(s.currentStarted)
+	if s.observe != nil {
+		s.observe(s.current, d)
+		return
+	}
+	reconcilePhaseDuration.WithLabelValues(s.current).Observe(d.Seconds())
+}
+
+//done marks the current step as completed and the reconcile as successful
+func (s *reconcileSteps) done() {
+	s.start("")
+	s.completed = true
+}
+
+//fail records an error of the current step which is not returned by the reconcile
+func (s *reconcileSteps) fail(err error) {
+	s.err = err
+}
+
+//condition returns the condition summarizing the steps, nil if the reconcile stopped
+//without error before the end of the steps (waiting on something)
+func (s *reconcileSteps) condition(errIn error) *metav1.Condition {
+	if errIn == nil {
+		errIn = s.err
+	}
+	lastCompleted := s.lastCompleted
+	if lastCompleted == "" {
+		lastCompleted = "none"
+	}
+	switch {
+	case errIn != nil && s.current != "":
+		return &metav1.Condition{
+			Type:   ConditionImportStepsCompleted,
+			Status: metav1.ConditionFalse,
+			Reason: reasonImportStepFailed,
+			Message: fmt.Sprintf("Failed at %s (last completed step: %s): %s",
+				s.current, lastCompleted, errIn.Error()),
+		}
+	case errIn == nil && s.completed:
+		return &metav1.Condition{
+			Type:    ConditionImportStepsCompleted,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonImportStepsCompleted,
+			Message: fmt.Sprintf("All steps completed (last completed step: %s)", lastCompleted),
+		}
+	}
+	return nil
+}
+
+//setConditionImportSteps records the last completed step and the failing step on the ManagedCluster,
+//the condition is only patched when it changes
+func (r *ReconcileManagedCluster) setConditionImportSteps(steps *reconcileSteps, errIn error) error {
+	if steps.managedCluster == nil {
+		return nil
+	}
+	newCondition := steps.condition(errIn)
+	if newCondition == nil {
+		return nil
+	}
+	c := meta.FindStatusCondition(steps.managedCluster.Status.Conditions, ConditionImportStepsCompleted)
+	if c != nil && c.Status == newCondition.Status && c.Reason == newCondition.Reason &&
+		c.Message == newCondition.Message {
+		return nil
+	}
+	return r.setCondition(steps.managedCluster, *newCondition)
+}
@@ -0,0 +1,194 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_reconcileSteps_condition(t *testing.T) {
+	tests := []struct {
+		name        string
+		run         func(s *reconcileSteps)
+		err         error
+		wantNil     bool
+		wantStatus  metav1.ConditionStatus
+		wantMessage string
+	}{
+		{
+			name: "failed at the first step",
+			run: func(s *reconcileSteps) {
+				s.start(stepAddFinalizer)
+			},
+			err:         fmt.Errorf("boom"),
+			wantStatus:  metav1.ConditionFalse,
+			wantMessage: "Failed at addFinalizer (last completed step: none): boom",
+		},
+		{
+			name: "failed at createOrUpdateManifestWorks",
+			run: func(s *reconcileSteps) {
+				s.start(stepGenerateImportYAMLs)
+				s.start(stepCreateOrUpdateImportSecret)
+				s.start(stepDeleteKlusterletSyncSets)
+				s.start(stepCreateOrUpdateManifestWorks)
+			},
+			err:         fmt.Errorf("conflict"),
+			wantStatus:  metav1.ConditionFalse,
+			wantMessage: "Failed at createOrUpdateManifestWorks (last completed step: deleteKlusterletSyncSets): conflict",
+		},
+		{
+			name: "failure not returned",
+			run: func(s *reconcileSteps) {
+				s.start(stepToBeImported)
+				s.start(stepImportCluster)
+				s.fail(fmt.Errorf("unreachable"))
+			},
+			wantStatus:  metav1.ConditionFalse,
+			wantMessage: "Failed at importCluster (last completed step: toBeImported): unreachable",
+		},
+		{
+			name: "waiting",
+			run: func(s *reconcileSteps) {
+				s.start(stepEnsureClusterNamespace)
+			},
+			wantNil: true,
+		},
+		{
+			name: "completed",
+			run: func(s *reconcileSteps) {
+				s.start(stepCreateOrUpdateImportSecret)
+				s.start(stepCreateOrUpdateManifestWorks)
+				s.done()
+			},
+			wantStatus:  metav1.ConditionTrue,
+			wantMessage: "All steps completed (last completed step: createOrUpdateManifestWorks)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &reconcileSteps{}
+			tt.run(s)
+			got := s.condition(tt.err)
+			if (got == nil) != tt.wantNil {
+				t.Errorf("condition() = %v, wantNil %v", got, tt.wantNil)
+				return
+			}
+			if got == nil {
+				return
+			}
+			if got.Status != tt.wantStatus || got.Message != tt.wantMessage {
+				t.Errorf("condition() = %s %q, want %s %q", got.Status, got.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func Test_setConditionImportSteps(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "stepscluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+		scheme: testscheme,
+	}
+
+	//Not set as long as the ManagedCluster is not tracked
+	if err := r.setConditionImportSteps(&reconcileSteps{}, fmt.Errorf("boom")); err != nil {
+		t.Errorf("setConditionImportSteps() error = %v", err)
+	}
+
+	steps := &reconcileSteps{managedCluster: managedCluster}
+	steps.start(stepGenerateImportYAMLs)
+	if err := r.setConditionImportSteps(steps, fmt.Errorf("invalid template")); err != nil {
+		t.Errorf("setConditionImportSteps() error = %v", err)
+		return
+	}
+	c := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionImportStepsCompleted)
+	if c == nil || c.Reason != reasonImportStepFailed || !strings.Contains(c.Message, stepGenerateImportYAMLs) {
+		t.Errorf("expected the failed step %s in the condition, got %v", stepGenerateImportYAMLs, c)
+	}
+
+	steps = &reconcileSteps{managedCluster: managedCluster}
+	steps.start(stepGenerateImportYAMLs)
+	steps.done()
+	if err := r.setConditionImportSteps(steps, nil); err != nil {
+		t.Errorf("setConditionImportSteps() error = %v", err)
+		return
+	}
+	if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionImportStepsCompleted) {
+		t.Errorf("expected condition %s to be true, got %v", ConditionImportStepsCompleted, managedCluster.Status.Conditions)
+	}
+}
+
+func Test_reconcileSteps_observe(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(s *reconcileSteps)
+		want []string
+	}{
+		{
+			name: "completed",
+			run: func(s *reconcileSteps) {
+				s.start(stepGenerateImportYAMLs)
+				s.start(stepApplyHubManifests)
+				s.start(stepCreateOrUpdateImportSecret)
+				s.done()
+				s.stop()
+			},
+			want: []string{"generateImportYAMLs=1s", "applyHubManifests=2s", "createOrUpdateImportSecret=3s"},
+		},
+		{
+			name: "returned at a step",
+			run: func(s *reconcileSteps) {
+				s.start(stepCreateServiceAccount)
+				s.start(stepGenerateImportYAMLs)
+				s.stop()
+			},
+			want: []string{"createServiceAccount=1s", "generateImportYAMLs=2s"},
+		},
+		{
+			name: "no step",
+			run: func(s *reconcileSteps) {
+				s.stop()
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//Each step lasts one second longer than the previous one
+			now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			elapsed := time.Duration(0)
+			var got []string
+			s := &reconcileSteps{
+				now: func() time.Time {
+					now = now.Add(elapsed)
+					elapsed += time.Second
+					return now
+				},
+				observe: func(step string, d time.Duration) {
+					got = append(got, fmt.Sprintf("%s=%s", step, d))
+				},
+			}
+			tt.run(s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("observed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//regionAnnotation is the region of the managed cluster, the klusterlet images are pulled from the
+//registry configured for it in the configmap of KLUSTERLET_REGION_REGISTRIES
+const regionAnnotation = "import.open-cluster-management.io/region"
+
+const (
+	ConditionRegionRegistryMissing    string = "ImportRegionRegistryMissing"
+	reasonRegionRegistryNotConfigured string = "RegionRegistryNotConfigured"
+	reasonRegionRegistryConfigured    string = "RegionRegistryConfigured"
+	reasonInvalidRegionRegistries     string = "InvalidRegionRegistries"
+)
+
+//getRegionRegistries returns the region to registry map of the configmap of KLUSTERLET_REGION_REGISTRIES,
+//nil if it is not set. The whole map is validated so a misconfiguration is reported for every cluster.
+func getRegionRegistries(c client.Client) (map[string]string, error) {
+	v := os.Getenv(klusterletRegionRegistriesEnvVarName)
+	if v == "" {
+		return nil, nil
+	}
+	nsn, err := parseNamespacedName(v)
+	if err != nil {
+		return nil, err
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(context.TODO(), nsn, cm); err != nil {
+		return nil, err
+	}
+
+	regions := make([]string, 0, len(cm.Data))
+	for region := range cm.Data {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	for _, region := range regions {
+		if err := validateRegistry(cm.Data[region]); err != nil {
+			return nil, newConfigError(reasonInvalidRegionRegistries,
+				"invalid region registries: region %s in %s: %v", region, v, err)
+		}
+	}
+	return cm.Data, nil
+}
+
+//validateRegistry checks the registry is a host with an optional port and path, without scheme or tag
+func validateRegistry(registry string) error {
+	switch {
+	case registry == "":
+		return fmt.Errorf("the registry is empty")
+	case strings.ContainsAny(registry, " \t\r\n"):
+		return fmt.Errorf("the registry %q contains a whitespace", registry)
+	case strings.Contains(registry, "://"):
+		return fmt.Errorf("the registry %q must not have a scheme", registry)
+	}
+	for _, segment := range strings.Split(registry, "/") {
+		if segment == "" {
+			return fmt.Errorf("the registry %q has an empty path segment", registry)
+		}
+	}
+	return nil
+}
+
+//getRegionRegistry returns the registry of the region of the managed cluster, "" when the cluster
+//has no region, when the region registries are not configured or when its region has no registry, the
+//default images are then used. found is false only when the configured map has no registry for the region.
+func getRegionRegistry(c client.Client, managedCluster *clusterv1.ManagedCluster) (registry string, found bool, err error) {
+	region := managedCluster.GetAnnotations()[regionAnnotation]
+	if region == "" || os.Getenv(klusterletRegionRegistriesEnvVarName) == "" {
+		return "", true, nil
+	}
+	registries, err := getRegionRegistries(c)
+	if err != nil {
+		return "", false, err
+	}
+	registry, found = registries[region]
+	return registry, found, nil
+}
+
+//overrideImageRegistry replaces the registry and the repository path of the image, the last path
+//segment with the tag or the digest is kept
+func overrideImageRegistry(image, registry string) string {
+	if registry == "" {
+		return image
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	return registry + "/" + name
+}
+
+//setConditionRegionRegistry raises the condition when the region of the cluster has no registry configured
+//and the default images are used, the condition is only patched when it is raised or cleared
+func (r *ReconcileManagedCluster) setConditionRegionRegistry(managedCluster *clusterv1.ManagedCluster) error {
+	_, found, err := getRegionRegistry(r.client, managedCluster)
+	if err != nil {
+		return err
+	}
+	current := meta.FindStatusCondition(managedCluster.Status.Conditions, ConditionRegionRegistryMissing)
+	if !found {
+		message := fmt.Sprintf("No registry is configured for the region %q, the default images are used",
+			managedCluster.GetAnnotations()[regionAnnotation])
+		if current != nil && current.Status == metav1.ConditionTrue && current.Message == message {
+			return nil
+		}
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionRegionRegistryMissing,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonRegionRegistryNotConfigured,
+			Message: message,
+		})
+	}
+	if current != nil && current.Status == metav1.ConditionTrue {
+		return r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionRegionRegistryMissing,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonRegionRegistryConfigured,
+			Message: "The images are pulled from the registry of the region of the cluster",
+		})
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestRegionRegistries(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "region-registries",
+			Namespace: "hub",
+		},
+		Data: data,
+	}
+}
+
+func Test_getRegionRegistry(t *testing.T) {
+	defer os.Unsetenv(klusterletRegionRegistriesEnvVarName)
+
+	registries := newTestRegionRegistries(map[string]string{
+		"eu-west": "mirror.eu.example.com/ocm",
+		"us-east": "mirror.us.example.com:5000/ocm",
+	})
+	tests := []struct {
+		name         string
+		env          string
+		region       string
+		objs         []runtime.Object
+		wantRegistry string
+		wantFound    bool
+		wantErr      bool
+		wantInv      bool
+	}{
+		{
+			name:      "no region",
+			env:       "hub/region-registries",
+			wantFound: true,
+		},
+		{
+			name:      "region without map",
+			env:       "",
+			region:    "eu-west",
+			wantFound: true,
+		},
+		{
+			name:    "configmap not found",
+			env:     "hub/region-registries",
+			region:  "eu-west",
+			wantErr: true,
+		},
+		{
+			name:         "region found",
+			env:          "hub/region-registries",
+			region:       "us-east",
+			objs:         []runtime.Object{registries},
+			wantRegistry: "mirror.us.example.com:5000/ocm",
+			wantFound:    true,
+		},
+		{
+			name:      "region not found",
+			env:       "hub/region-registries",
+			region:    "ap-south",
+			objs:      []runtime.Object{registries},
+			wantFound: false,
+		},
+		{
+			name:   "invalid registry of another region",
+			env:    "hub/region-registries",
+			region: "eu-west",
+			objs: []runtime.Object{newTestRegionRegistries(map[string]string{
+				"eu-west": "mirror.eu.example.com/ocm",
+				"us-east": "https://mirror.us.example.com",
+			})},
+			wantErr: true,
+			wantInv: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(klusterletRegionRegistriesEnvVarName, tt.env)
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mycluster",
+				},
+			}
+			if tt.region != "" {
+				managedCluster.Annotations = map[string]string{regionAnnotation: tt.region}
+			}
+			c := fake.NewFakeClientWithScheme(scheme.Scheme, tt.objs...)
+			registry, found, err := getRegionRegistry(c, managedCluster)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getRegionRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (configErrorReason(err) == reasonInvalidRegionRegistries) != tt.wantInv {
+				t.Errorf("configErrorReason() = %q, want %v", configErrorReason(err), tt.wantInv)
+			}
+			if err != nil {
+				return
+			}
+			if registry != tt.wantRegistry || found != tt.wantFound {
+				t.Errorf("getRegionRegistry() = %q, %v, want %q, %v", registry, found, tt.wantRegistry, tt.wantFound)
+			}
+		})
+	}
+}
+
+func Test_validateRegistry(t *testing.T) {
+	tests := []struct {
+		registry string
+		wantErr  bool
+	}{
+		{registry: "mirror.example.com", wantErr: false},
+		{registry: "mirror.example.com:5000/ocm/images", wantErr: false},
+		{registry: "", wantErr: true},
+		{registry: "mirror.example.com/ ocm", wantErr: true},
+		{registry: "https://mirror.example.com", wantErr: true},
+		{registry: "mirror.example.com/ocm/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.registry, func(t *testing.T) {
+			if err := validateRegistry(tt.registry); (err != nil) != tt.wantErr {
+				t.Errorf("validateRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_overrideImageRegistry(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		registry string
+		want     string
+	}{
+		{
+			name:     "no registry",
+			image:    "quay.io/open-cluster-management/registration:2.2.0",
+			registry: "",
+			want:     "quay.io/open-cluster-management/registration:2.2.0",
+		},
+		{
+			name:     "tag",
+			image:    "quay.io/open-cluster-management/registration:2.2.0",
+			registry: "mirror.example.com:5000/ocm",
+			want:     "mirror.example.com:5000/ocm/registration:2.2.0",
+		},
+		{
+			name:     "digest",
+			image:    "quay.io/open-cluster-management/work@sha256:0123",
+			registry: "mirror.example.com",
+			want:     "mirror.example.com/work@sha256:0123",
+		},
+		{
+			name:     "image without registry",
+			image:    "registration-operator:latest",
+			registry: "mirror.example.com",
+			want:     "mirror.example.com/registration-operator:latest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overrideImageRegistry(tt.image, tt.registry); got != tt.want {
+				t.Errorf("overrideImageRegistry() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_setConditionRegionRegistry(t *testing.T) {
+	os.Setenv(klusterletRegionRegistriesEnvVarName, "hub/region-registries")
+	defer os.Unsetenv(klusterletRegionRegistriesEnvVarName)
+
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mycluster",
+			Annotations: map[string]string{regionAnnotation: "ap-south"},
+		},
+	}
+	registries := newTestRegionRegistries(map[string]string{"eu-west": "mirror.eu.example.com/ocm"})
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, managedCluster, registries),
+		scheme: testscheme,
+	}
+
+	getCondition := func() *metav1.Condition {
+		got := &clusterv1.ManagedCluster{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, got); err != nil {
+			t.Fatal(err)
+		}
+		return meta.FindStatusCondition(got.Status.Conditions, ConditionRegionRegistryMissing)
+	}
+
+	if err := r.setConditionRegionRegistry(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	if c := getCondition(); c == nil || c.Status != metav1.ConditionTrue {
+		t.Errorf("expected the condition to be raised, got %v", c)
+	}
+
+	managedCluster.Annotations[regionAnnotation] = "eu-west"
+	if err := r.setConditionRegionRegistry(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	if c := getCondition(); c == nil || c.Status != metav1.ConditionFalse {
+		t.Errorf("expected the condition to be cleared, got %v", c)
+	}
+
+	//Without region registries the region annotation is a no-op
+	os.Unsetenv(klusterletRegionRegistriesEnvVarName)
+	managedCluster.Annotations[regionAnnotation] = "ap-south"
+	if err := r.setConditionRegionRegistry(managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	if c := getCondition(); c == nil || c.Status != metav1.ConditionFalse {
+		t.Errorf("expected the condition not to be raised without region registries, got %v", c)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//remoteKubeVersionAnnotation records the kube version of the managed cluster read during the import,
+//before the registration agent reports it
+const remoteKubeVersionAnnotation = "import.open-cluster-management.io/remote-kube-version"
+
+//serverVersioner is implemented by the managed cluster clients able to read the server version
+type serverVersioner interface {
+	ServerVersion() (*version.Info, error)
+}
+
+//managedClusterClient is the client of a managed cluster, it keeps its rest config to reach
+//the non resource endpoints
+type managedClusterClient struct {
+	client.Client
+	config *rest.Config
+}
+
+var _ serverVersioner = &managedClusterClient{}
+
+//newManagedClusterClient returns the client of the managed cluster for the rest config
+func newManagedClusterClient(config *rest.Config) (client.Client, error) {
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &managedClusterClient{Client: newFieldManagerClient(c, fieldManager()), config: config}, nil
+}
+
+//ServerVersion returns the version of the API server of the managed cluster
+func (c *managedClusterClient) ServerVersion() (*version.Info, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(c.config)
+	if err != nil {
+		return nil, err
+	}
+	return discoveryClient.ServerVersion()
+}
+
+//recordRemoteKubeVersion records the kube version of the managed cluster on the ManagedCluster, it is
+//best-effort, nothing is recorded if the client can't read it
+func (r *ReconcileManagedCluster) recordRemoteKubeVersion(
+	managedCluster *clusterv1.ManagedCluster,
+	managedClusterClient client.Client,
+) error {
+	versioner, ok := managedClusterClient.(serverVersioner)
+	if !ok {
+		return nil
+	}
+	info, err := versioner.ServerVersion()
+	if err != nil || info == nil || info.GitVersion == "" {
+		clusterLogger(managedCluster.Name).Info("Unable to read the kube version of the managed cluster",
+			"error", fmt.Sprint(err))
+		return nil
+	}
+	if managedCluster.GetAnnotations()[remoteKubeVersionAnnotation] == info.GitVersion {
+		return nil
+	}
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	annotations := managedCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[remoteKubeVersionAnnotation] = info.GitVersion
+	managedCluster.SetAnnotations(annotations)
+	return r.client.Patch(context.TODO(), managedCluster, patch)
+}
@@ -0,0 +1,94 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeVersionedClient struct {
+	client.Client
+	info *version.Info
+	err  error
+}
+
+func (c *fakeVersionedClient) ServerVersion() (*version.Info, error) {
+	return c.info, c.err
+}
+
+func TestReconcileManagedCluster_recordRemoteKubeVersion(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	remoteClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+	tests := []struct {
+		name           string
+		annotation     string
+		remoteClient   client.Client
+		wantAnnotation string
+	}{
+		{
+			name:           "client without version",
+			remoteClient:   remoteClient,
+			wantAnnotation: "",
+		},
+		{
+			name:           "discovery error",
+			remoteClient:   &fakeVersionedClient{Client: remoteClient, err: fmt.Errorf("unreachable")},
+			wantAnnotation: "",
+		},
+		{
+			name:           "discovery error keeps the recorded version",
+			annotation:     "v1.19.0",
+			remoteClient:   &fakeVersionedClient{Client: remoteClient, err: fmt.Errorf("unreachable")},
+			wantAnnotation: "v1.19.0",
+		},
+		{
+			name:           "version recorded",
+			remoteClient:   &fakeVersionedClient{Client: remoteClient, info: &version.Info{GitVersion: "v1.20.4"}},
+			wantAnnotation: "v1.20.4",
+		},
+		{
+			name:           "version updated",
+			annotation:     "v1.19.0",
+			remoteClient:   &fakeVersionedClient{Client: remoteClient, info: &version.Info{GitVersion: "v1.20.4"}},
+			wantAnnotation: "v1.20.4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			}
+			if tt.annotation != "" {
+				managedCluster.Annotations = map[string]string{remoteKubeVersionAnnotation: tt.annotation}
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+				scheme: testscheme,
+			}
+			if err := r.recordRemoteKubeVersion(managedCluster, tt.remoteClient); err != nil {
+				t.Fatalf("recordRemoteKubeVersion() error = %v", err)
+			}
+			got := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if v := got.GetAnnotations()[remoteKubeVersionAnnotation]; v != tt.wantAnnotation {
+				t.Errorf("annotation = %q, want %q", v, tt.wantAnnotation)
+			}
+		})
+	}
+}
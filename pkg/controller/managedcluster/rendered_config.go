@@ -0,0 +1,203 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	"github.com/open-cluster-management/managedcluster-import-controller/pkg/bindata"
+)
+
+//renderedConfigHashAnnotation is the hash of the configuration applied by the last successful reconcile,
+//the hub manifests, the import secret and the klusterlet manifestworks are not applied again while
+//it doesn't change
+const renderedConfigHashAnnotation = "import.open-cluster-management.io/rendered-config-hash"
+
+var (
+	templatesVersionOnce sync.Once
+	templatesVersion     string
+)
+
+//getTemplatesVersion returns the hash of the templates embedded in the controller
+func getTemplatesVersion() string {
+	templatesVersionOnce.Do(func() {
+		names := bindata.AssetNames()
+		sort.Strings(names)
+		h := sha256.New()
+		for _, name := range names {
+			b, err := bindata.Asset(name)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(h, "%s=%x;", name, b)
+		}
+		templatesVersion = fmt.Sprintf("%x", h.Sum(nil))
+	})
+	return templatesVersion
+}
+
+//renderedConfigHash returns the hash of everything the reconcile applies for the cluster: the templates,
+//the hub manifests values and the rendered klusterlet yamls which embed the bootstrap kubeconfig
+func renderedConfigHash(
+	managedCluster *clusterv1.ManagedCluster,
+	hubValues hubManifestsValues,
+	crds []*unstructured.Unstructured,
+	yamls []*unstructured.Unstructured,
+) (string, error) {
+	b, err := json.Marshal(struct {
+		ClusterName      string
+		TemplatesVersion string
+		ApplyStrategy    manifestWorkApplyStrategy
+		HubValues        hubManifestsValues
+		CRDs             []*unstructured.Unstructured
+		YAMLs            []*unstructured.Unstructured
+	}{
+		ClusterName:      managedCluster.Name,
+		TemplatesVersion: getTemplatesVersion(),
+		ApplyStrategy:    getManifestWorkApplyStrategy(),
+		HubValues:        hubValues,
+		CRDs:             crds,
+		YAMLs:            yamls,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+//appliedConfigFingerprint combines the hash of the configuration with the state of the applied resources,
+//a deleted import secret, bootstrap service account, bootstrap RBAC or manifestwork, a modified import
+//secret or bootstrap resource (new resourceVersion) or a manifestwork modified by someone else (new
+//generation), changes the fingerprint. It returns false if a resource is missing. The manifestworks are ignored when
+//skipManifestWorks is true.
+func appliedConfigFingerprint(
+	c client.Client,
+	managedCluster *clusterv1.ManagedCluster,
+	configHash string,
+	skipManifestWorks bool,
+) (string, bool, error) {
+	secretNsN, err := importSecretNsN(managedCluster)
+	if err != nil {
+		return "", false, err
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), secretNsN, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	fingerprint := fmt.Sprintf("%s/%s", configHash, secret.ResourceVersion)
+	saNsN, err := bootstrapServiceAccountNsN(managedCluster)
+	if err != nil {
+		return "", false, err
+	}
+	rbacName := bootstrapClusterRoleBindingName(managedCluster)
+	hubObjects := []struct {
+		key types.NamespacedName
+		obj runtime.Object
+	}{
+		{key: saNsN, obj: &corev1.ServiceAccount{}},
+		{key: types.NamespacedName{Name: rbacName}, obj: &rbacv1.ClusterRole{}},
+		{key: types.NamespacedName{Name: rbacName}, obj: &rbacv1.ClusterRoleBinding{}},
+	}
+	for _, hubObject := range hubObjects {
+		if err := c.Get(context.TODO(), hubObject.key, hubObject.obj); err != nil {
+			if errors.IsNotFound(err) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		accessor, err := meta.Accessor(hubObject.obj)
+		if err != nil {
+			return "", false, err
+		}
+		fingerprint = fmt.Sprintf("%s/%s", fingerprint, accessor.GetResourceVersion())
+	}
+	if skipManifestWorks {
+		fingerprint += "/offline"
+		return fmt.Sprintf("%x", sha256.Sum256([]byte(fingerprint))), true, nil
+	}
+	mwNsN, err := manifestWorkNsN(managedCluster)
+	if err != nil {
+		return "", false, err
+	}
+	for _, name := range []string{mwNsN.Name + manifestWorkCRDSPostfix, mwNsN.Name} {
+		mw := &workv1.ManifestWork{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: mwNsN.Namespace}, mw); err != nil {
+			if errors.IsNotFound(err) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		fingerprint = fmt.Sprintf("%s/%d", fingerprint, mw.Generation)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(fingerprint))), true, nil
+}
+
+//skipsManifestWorks returns true if the reconcile doesn't apply the klusterlet manifestworks,
+//their state is then not part of the fingerprint
+func skipsManifestWorks(managedCluster *clusterv1.ManagedCluster) bool {
+	return checkOffLine(managedCluster) || isKlusterletProvisionedExternally(managedCluster)
+}
+
+//isRenderedConfigApplied returns true if the configuration was already applied by a previous reconcile
+//and the applied resources are unchanged since, the reconcile can then skip applying them
+func (r *ReconcileManagedCluster) isRenderedConfigApplied(
+	managedCluster *clusterv1.ManagedCluster,
+	configHash string,
+) (bool, error) {
+	if !getEnvBool(reconcileFastPathEnvVarName, true) {
+		return false, nil
+	}
+	stored, ok := managedCluster.GetAnnotations()[renderedConfigHashAnnotation]
+	if !ok {
+		return false, nil
+	}
+	fingerprint, found, err := appliedConfigFingerprint(r.client, managedCluster, configHash, skipsManifestWorks(managedCluster))
+	if err != nil || !found {
+		return false, err
+	}
+	return stored == fingerprint, nil
+}
+
+//recordRenderedConfigApplied stores the fingerprint of the applied configuration on the ManagedCluster
+func (r *ReconcileManagedCluster) recordRenderedConfigApplied(
+	managedCluster *clusterv1.ManagedCluster,
+	configHash string,
+) error {
+	if !getEnvBool(reconcileFastPathEnvVarName, true) {
+		return nil
+	}
+	fingerprint, found, err := appliedConfigFingerprint(r.client, managedCluster, configHash, skipsManifestWorks(managedCluster))
+	if err != nil || !found {
+		return err
+	}
+	if managedCluster.GetAnnotations()[renderedConfigHashAnnotation] == fingerprint {
+		return nil
+	}
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	annotations := managedCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[renderedConfigHashAnnotation] = fingerprint
+	managedCluster.SetAnnotations(annotations)
+	return r.client.Patch(context.TODO(), managedCluster, patch)
+}
@@ -0,0 +1,306 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_renderedConfigHash(t *testing.T) {
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fastpath",
+		},
+	}
+	values := newHubManifestsValues(managedCluster)
+	yaml := func(image string) []*unstructured.Unstructured {
+		return []*unstructured.Unstructured{
+			{
+				Object: map[string]interface{}{
+					"kind": "Deployment",
+					"spec": map[string]interface{}{"image": image},
+				},
+			},
+		}
+	}
+	h1, err := renderedConfigHash(managedCluster, values, nil, yaml("registration:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := renderedConfigHash(managedCluster, values, nil, yaml("registration:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 != h2 {
+		t.Errorf("expected the same hash for the same configuration, got %s and %s", h1, h2)
+	}
+	h3, err := renderedConfigHash(managedCluster, values, nil, yaml("registration:2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 == h3 {
+		t.Error("expected a new hash when the rendered yamls change")
+	}
+	values.BootstrapLeastPrivilege = !values.BootstrapLeastPrivilege
+	h4, err := renderedConfigHash(managedCluster, values, nil, yaml("registration:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 == h4 {
+		t.Error("expected a new hash when the hub manifests values change")
+	}
+}
+
+func TestReconcileManagedCluster_isRenderedConfigApplied(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	newObjects := func() (*clusterv1.ManagedCluster, *corev1.Secret, *workv1.ManifestWork, *workv1.ManifestWork) {
+		managedCluster := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "fastpath",
+			},
+			Status: clusterv1.ManagedClusterStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   clusterv1.ManagedClusterConditionAvailable,
+						Status: metav1.ConditionTrue,
+					},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fastpath" + importSecretNamePostfix,
+				Namespace: "fastpath",
+			},
+		}
+		crds := &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "fastpath" + manifestWorkNamePostfix + manifestWorkCRDSPostfix,
+				Namespace:  "fastpath",
+				Generation: 1,
+			},
+		}
+		yamls := &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "fastpath" + manifestWorkNamePostfix,
+				Namespace:  "fastpath",
+				Generation: 1,
+			},
+		}
+		return managedCluster, secret, crds, yamls
+	}
+
+	tests := []struct {
+		name     string
+		disabled bool
+		record   bool
+		change   func(r *ReconcileManagedCluster, yamls *workv1.ManifestWork)
+		hash     string
+		want     bool
+	}{
+		{
+			name: "not recorded",
+			hash: "h1",
+			want: false,
+		},
+		{
+			name:   "recorded and unchanged",
+			record: true,
+			hash:   "h1",
+			want:   true,
+		},
+		{
+			name:   "configuration changed",
+			record: true,
+			hash:   "h2",
+			want:   false,
+		},
+		{
+			name:   "manifestwork modified",
+			record: true,
+			hash:   "h1",
+			change: func(r *ReconcileManagedCluster, yamls *workv1.ManifestWork) {
+				current := &workv1.ManifestWork{}
+				if err := r.client.Get(context.TODO(),
+					types.NamespacedName{Name: yamls.Name, Namespace: yamls.Namespace}, current); err != nil {
+					t.Fatal(err)
+				}
+				current.Generation = 2
+				if err := r.client.Update(context.TODO(), current); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: false,
+		},
+		{
+			name:   "manifestwork deleted",
+			record: true,
+			hash:   "h1",
+			change: func(r *ReconcileManagedCluster, yamls *workv1.ManifestWork) {
+				if err := r.client.Delete(context.TODO(), yamls); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: false,
+		},
+		{
+			name:   "import secret modified",
+			record: true,
+			hash:   "h1",
+			change: func(r *ReconcileManagedCluster, yamls *workv1.ManifestWork) {
+				current := &corev1.Secret{}
+				if err := r.client.Get(context.TODO(),
+					types.NamespacedName{Name: "fastpath" + importSecretNamePostfix, Namespace: "fastpath"}, current); err != nil {
+					t.Fatal(err)
+				}
+				current.Data = map[string][]byte{"import.yaml": []byte("edited")}
+				if err := r.client.Update(context.TODO(), current); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: false,
+		},
+		{
+			name:   "bootstrap ClusterRoleBinding deleted",
+			record: true,
+			hash:   "h1",
+			change: func(r *ReconcileManagedCluster, yamls *workv1.ManifestWork) {
+				crb := &rbacv1.ClusterRoleBinding{}
+				crb.SetName(bootstrapClusterRoleBindingNamePrefix + "fastpath")
+				if err := r.client.Delete(context.TODO(), crb); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: false,
+		},
+		{
+			name:   "bootstrap ClusterRole modified",
+			record: true,
+			hash:   "h1",
+			change: func(r *ReconcileManagedCluster, yamls *workv1.ManifestWork) {
+				cr := &rbacv1.ClusterRole{}
+				if err := r.client.Get(context.TODO(),
+					types.NamespacedName{Name: bootstrapClusterRoleBindingNamePrefix + "fastpath"}, cr); err != nil {
+					t.Fatal(err)
+				}
+				cr.Rules = append(cr.Rules, rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}})
+				if err := r.client.Update(context.TODO(), cr); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: false,
+		},
+		{
+			name:     "fast path disabled",
+			disabled: true,
+			record:   true,
+			hash:     "h1",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.disabled {
+				os.Setenv(reconcileFastPathEnvVarName, "false")
+				defer os.Unsetenv(reconcileFastPathEnvVarName)
+			}
+			managedCluster, secret, crds, yamls := newObjects()
+			rbacName := bootstrapClusterRoleBindingNamePrefix + "fastpath"
+			sa := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fastpath" + bootstrapServiceAccountNamePostfix,
+					Namespace: "fastpath",
+				},
+			}
+			cr := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: rbacName}}
+			crb := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: rbacName}}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, managedCluster, secret, crds, yamls, sa, cr, crb),
+				scheme: testscheme,
+			}
+			if tt.record {
+				if err := r.recordRenderedConfigApplied(managedCluster, "h1"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.change != nil {
+				tt.change(r, yamls)
+			}
+			got, err := r.isRenderedConfigApplied(managedCluster, tt.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("isRenderedConfigApplied() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileManagedCluster_Reconcile_fastPathBootstrapRBAC(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	testscheme.AddKnownTypes(workv1.SchemeGroupVersion, &workv1.ManifestWork{}, &workv1.ManifestWorkList{})
+
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: managedClusterNameReconcile,
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   clusterv1.ManagedClusterConditionAvailable,
+					Status: metav1.ConditionTrue,
+				},
+			},
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(testscheme, newImportObjects(t, managedCluster)...),
+		scheme: testscheme,
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: managedClusterNameReconcile}}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatal(err)
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.GetAnnotations()[renderedConfigHashAnnotation]; !ok {
+		t.Fatal("expected the applied configuration to be recorded")
+	}
+
+	//The deleted ClusterRoleBinding is recreated although the configuration is unchanged
+	crb := &rbacv1.ClusterRoleBinding{}
+	crb.SetName(bootstrapClusterRoleBindingName(managedCluster))
+	if err := r.client.Delete(context.TODO(), crb); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatal(err)
+	}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: crb.Name}, &rbacv1.ClusterRoleBinding{})
+	if errors.IsNotFound(err) {
+		t.Error("expected the bootstrap ClusterRoleBinding to be recreated")
+	} else if err != nil {
+		t.Fatal(err)
+	}
+}
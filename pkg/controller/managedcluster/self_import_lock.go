@@ -0,0 +1,145 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//selfImportLeaseName is the Lease of the controller namespace held by the replica importing the local-cluster
+const selfImportLeaseName = "managedcluster-import-controller-self-import"
+
+//selfImportLeaseDuration bounds the time a replica can hold the lease, so a replica stopped in the middle
+//of the self-import doesn't block the next ones
+const selfImportLeaseDuration = 5 * time.Minute
+
+//selfImportLockedRequeueAfter is the delay before retrying a self-import done by another reconcile
+const selfImportLockedRequeueAfter = 30 * time.Second
+
+var errSelfImportLocked = errors.New("the self-import is in progress in another reconcile")
+
+//selfImportInProgress prevents concurrent self-imports in the replica, the lease only identifies replicas
+var selfImportInProgress = struct {
+	sync.Mutex
+	locked bool
+}{}
+
+//selfImportHolderIdentity identifies the replica in the lease
+func selfImportHolderIdentity() string {
+	if name := os.Getenv("POD_NAME"); name != "" {
+		return name
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+//acquireSelfImportLease takes the self-import lease for the holder if it's free, expired or already held by
+//the holder. The create and update fail on concurrent changes, so only one replica gets the lease. The
+//lease is read with the reader, uncached so the leases are not watched.
+func acquireSelfImportLease(c client.Client, reader client.Reader, namespace, holder string, now time.Time) (bool, error) {
+	lease := &coordinationv1.Lease{}
+	err := reader.Get(context.TODO(), types.NamespacedName{Name: selfImportLeaseName, Namespace: namespace}, lease)
+	durationSeconds := int32(selfImportLeaseDuration.Seconds())
+	renewTime := metav1.NewMicroTime(now)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      selfImportLeaseName,
+				Namespace: namespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		err = c.Create(context.TODO(), lease)
+		if apierrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	spec := lease.Spec
+	held := spec.HolderIdentity != nil && *spec.HolderIdentity != "" && *spec.HolderIdentity != holder
+	if held && spec.RenewTime != nil && spec.LeaseDurationSeconds != nil &&
+		now.Before(spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds)*time.Second)) {
+		return false, nil
+	}
+	lease.Spec.HolderIdentity = &holder
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.AcquireTime = &renewTime
+	lease.Spec.RenewTime = &renewTime
+	err = c.Update(context.TODO(), lease)
+	if apierrors.IsConflict(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+//releaseSelfImportLease frees the lease if it's still held by the holder
+func releaseSelfImportLease(c client.Client, reader client.Reader, namespace, holder string) error {
+	lease := &coordinationv1.Lease{}
+	err := reader.Get(context.TODO(), types.NamespacedName{Name: selfImportLeaseName, Namespace: namespace}, lease)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		return nil
+	}
+	lease.Spec.HolderIdentity = nil
+	return c.Update(context.TODO(), lease)
+}
+
+//lockSelfImport guards the self-import against concurrent reconciles in the replica and in the other
+//replicas during a leader transition, it returns errSelfImportLocked if the self-import is in progress
+//elsewhere and the function releasing the lock otherwise
+func (r *ReconcileManagedCluster) lockSelfImport() (func(), error) {
+	selfImportInProgress.Lock()
+	defer selfImportInProgress.Unlock()
+	if selfImportInProgress.locked {
+		return nil, errSelfImportLocked
+	}
+
+	namespace := os.Getenv("POD_NAMESPACE")
+	holder := selfImportHolderIdentity()
+	var reader client.Reader = r.client
+	if r.apiReader != nil {
+		reader = r.apiReader
+	}
+	if namespace != "" {
+		acquired, err := acquireSelfImportLease(r.client, reader, namespace, holder, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if !acquired {
+			return nil, errSelfImportLocked
+		}
+	}
+	selfImportInProgress.locked = true
+
+	return func() {
+		if namespace != "" {
+			if err := releaseSelfImportLease(r.client, reader, namespace, holder); err != nil {
+				log.Error(err, "Failed to release the self-import lease")
+			}
+		}
+		selfImportInProgress.Lock()
+		selfImportInProgress.locked = false
+		selfImportInProgress.Unlock()
+	}, nil
+}
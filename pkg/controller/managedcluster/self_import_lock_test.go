@@ -0,0 +1,181 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_acquireSelfImportLease_concurrent(t *testing.T) {
+	c := fake.NewFakeClientWithScheme(scheme.Scheme)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	results := make(chan bool, 2)
+	for _, holder := range []string{"replica-a", "replica-b"} {
+		wg.Add(1)
+		go func(holder string) {
+			defer wg.Done()
+			acquired, err := acquireSelfImportLease(c, c, "test", holder, now)
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			results <- acquired
+		}(holder)
+	}
+	wg.Wait()
+	close(results)
+
+	acquired := 0
+	for result := range results {
+		if result {
+			acquired++
+		}
+	}
+	if acquired != 1 {
+		t.Errorf("expected exactly one replica to acquire the lease, got %d", acquired)
+	}
+}
+
+func Test_acquireSelfImportLease(t *testing.T) {
+	durationSeconds := int32(selfImportLeaseDuration.Seconds())
+	now := time.Now()
+	newLease := func(holder string, renewTime time.Time) *coordinationv1.Lease {
+		renew := metav1.NewMicroTime(renewTime)
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      selfImportLeaseName,
+				Namespace: "test",
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &durationSeconds,
+				RenewTime:            &renew,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		lease    *coordinationv1.Lease
+		holder   string
+		acquired bool
+	}{
+		{
+			name:     "no lease",
+			holder:   "replica-a",
+			acquired: true,
+		},
+		{
+			name:     "held by another replica",
+			lease:    newLease("replica-b", now.Add(-time.Minute)),
+			holder:   "replica-a",
+			acquired: false,
+		},
+		{
+			name:     "expired",
+			lease:    newLease("replica-b", now.Add(-2*selfImportLeaseDuration)),
+			holder:   "replica-a",
+			acquired: true,
+		},
+		{
+			name:     "held by the replica",
+			lease:    newLease("replica-a", now.Add(-time.Minute)),
+			holder:   "replica-a",
+			acquired: true,
+		},
+		{
+			name:     "released",
+			lease:    newLease("", now.Add(-time.Minute)),
+			holder:   "replica-a",
+			acquired: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(scheme.Scheme)
+			if tt.lease != nil {
+				if err := c.Create(context.TODO(), tt.lease); err != nil {
+					t.Fatal(err)
+				}
+			}
+			acquired, err := acquireSelfImportLease(c, c, "test", tt.holder, now)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if acquired != tt.acquired {
+				t.Errorf("expected acquired %t, got %t", tt.acquired, acquired)
+			}
+			if !acquired {
+				return
+			}
+			if err := releaseSelfImportLease(c, c, "test", tt.holder); err != nil {
+				t.Fatal(err)
+			}
+			lease := &coordinationv1.Lease{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Name: selfImportLeaseName, Namespace: "test"}, lease); err != nil {
+				t.Fatal(err)
+			}
+			if lease.Spec.HolderIdentity != nil {
+				t.Errorf("expected the lease to be released, got holder %s", *lease.Spec.HolderIdentity)
+			}
+		})
+	}
+}
+
+func Test_lockSelfImport(t *testing.T) {
+	r := &ReconcileManagedCluster{client: fake.NewFakeClientWithScheme(scheme.Scheme)}
+
+	unlock, err := r.lockSelfImport()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := r.lockSelfImport(); err != errSelfImportLocked {
+		t.Errorf("expected %v, got %v", errSelfImportLocked, err)
+	}
+	unlock()
+	unlock, err = r.lockSelfImport()
+	if err != nil {
+		t.Fatalf("unexpected error %v after the unlock", err)
+	}
+	unlock()
+}
+
+func TestReconcileManagedCluster_lockSelfImport_uncachedLease(t *testing.T) {
+	os.Setenv("POD_NAMESPACE", "test")
+	defer os.Unsetenv("POD_NAMESPACE")
+
+	c := fake.NewFakeClientWithScheme(scheme.Scheme)
+	reader := &fakeAPIReader{Reader: c}
+	r := &ReconcileManagedCluster{
+		client:    c,
+		apiReader: reader,
+		scheme:    scheme.Scheme,
+	}
+	unlock, err := r.lockSelfImport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+
+	//The leases are read from the apiserver, they are not cached by the manager
+	if len(reader.gets) != 2 {
+		t.Errorf("expected the lease to be read twice with the api reader, got %d reads", len(reader.gets))
+	}
+	for _, obj := range reader.gets {
+		if _, ok := obj.(*coordinationv1.Lease); !ok {
+			t.Errorf("expected a lease read, got %T", obj)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"strconv"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//ConditionSelfManaged marks a ManagedCluster as being the hub itself (local-cluster)
+const ConditionSelfManaged string = "SelfManaged"
+
+const (
+	reasonSelfManaged    string = "ClusterIsSelfManaged"
+	reasonNotSelfManaged string = "ClusterIsNotSelfManaged"
+)
+
+//IsSelfManaged returns true if the SelfManagedLabel is set to a true value, the webhooks use it
+//to recognize the hub cluster
+func IsSelfManaged(managedCluster *clusterv1.ManagedCluster) bool {
+	v, ok := managedCluster.GetLabels()[SelfManagedLabel]
+	if !ok {
+		return false
+	}
+	selfManaged, err := strconv.ParseBool(v)
+	return err == nil && selfManaged
+}
+
+func newSelfManagedCondition(managedCluster *clusterv1.ManagedCluster) metav1.Condition {
+	if IsSelfManaged(managedCluster) {
+		return metav1.Condition{
+			Type:    ConditionSelfManaged,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonSelfManaged,
+			Message: "The managed cluster is the hub cluster",
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionSelfManaged,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonNotSelfManaged,
+		Message: "The managed cluster is not the hub cluster",
+	}
+}
+
+//setConditionSelfManaged patches the SelfManaged condition only when it changes
+func (r *ReconcileManagedCluster) setConditionSelfManaged(managedCluster *clusterv1.ManagedCluster) error {
+	condition := newSelfManagedCondition(managedCluster)
+	if meta.IsStatusConditionPresentAndEqual(managedCluster.Status.Conditions, condition.Type, condition.Status) {
+		return nil
+	}
+	return r.setCondition(managedCluster, condition)
+}
@@ -0,0 +1,72 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_setConditionSelfManaged(t *testing.T) {
+	testscheme := scheme.Scheme
+	testscheme.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   metav1.ConditionStatus
+	}{
+		{
+			name: "no label",
+			want: metav1.ConditionFalse,
+		},
+		{
+			name:   "local-cluster true",
+			labels: map[string]string{SelfManagedLabel: "true"},
+			want:   metav1.ConditionTrue,
+		},
+		{
+			name:   "local-cluster false",
+			labels: map[string]string{SelfManagedLabel: "false"},
+			want:   metav1.ConditionFalse,
+		},
+		{
+			name:   "local-cluster invalid",
+			labels: map[string]string{SelfManagedLabel: "maybe"},
+			want:   metav1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			managedCluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "mycluster",
+					Labels: tt.labels,
+				},
+			}
+			r := &ReconcileManagedCluster{
+				client: fake.NewFakeClientWithScheme(testscheme, managedCluster),
+				scheme: testscheme,
+			}
+			if err := r.setConditionSelfManaged(managedCluster); err != nil {
+				t.Errorf("setConditionSelfManaged() error = %v", err)
+				return
+			}
+			got := &clusterv1.ManagedCluster{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "mycluster"}, got); err != nil {
+				t.Error(err)
+				return
+			}
+			if !meta.IsStatusConditionPresentAndEqual(got.Status.Conditions, ConditionSelfManaged, tt.want) {
+				t.Errorf("expected condition %s to be %s, got %v", ConditionSelfManaged, tt.want, got.Status.Conditions)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+//parseServerURL parses the URL of a kube API server. An IPv6 literal host must be enclosed in brackets,
+//for example https://[fd00::1]:6443, as without them its last group can't be told apart from the port.
+func parseServerURL(server string) (*url.URL, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Count(u.Host, ":") > 1 && !strings.HasPrefix(u.Host, "[") {
+		return nil, fmt.Errorf("the IPv6 address of %q must be enclosed in brackets", server)
+	}
+	return u, nil
+}
+
+//withDefaultScheme returns the server prefixed with https:// if it has no scheme, like the rest client
+//does for a host:port server, for example [fd00::1]:6443
+func withDefaultScheme(server string) string {
+	if server == "" || strings.Contains(server, "://") {
+		return server
+	}
+	return "https://" + server
+}
+
+//validateServerURLs checks the servers of the managed cluster used to build its rest config
+func validateServerURLs(servers ...string) error {
+	for _, server := range servers {
+		if server == "" {
+			continue
+		}
+		if _, err := parseServerURL(withDefaultScheme(server)); err != nil {
+			return fmt.Errorf("invalid server URL: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_serverHostname(t *testing.T) {
+	tests := []struct {
+		name   string
+		server string
+		want   string
+	}{
+		{
+			name:   "hostname",
+			server: "https://api.example.com:6443",
+			want:   "api.example.com",
+		},
+		{
+			name:   "IPv4",
+			server: "https://10.0.0.1:6443",
+			want:   "10.0.0.1",
+		},
+		{
+			name:   "IPv6",
+			server: "https://[fd00::1]:6443",
+			want:   "fd00::1",
+		},
+		{
+			name:   "IPv6 without port",
+			server: "https://[2001:db8::1]",
+			want:   "2001:db8::1",
+		},
+		{
+			name:   "IPv6 without scheme",
+			server: "[fd00::1]:6443",
+			want:   "fd00::1",
+		},
+		{
+			name:   "IPv6 without brackets",
+			server: "https://fd00::1:6443",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serverHostname(tt.server); got != tt.want {
+				t.Errorf("serverHostname() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newRestConfigFromServerAndAuth_IPv6(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   string
+		wantHost string
+		wantErr  bool
+	}{
+		{
+			name:     "IPv6",
+			server:   "https://[fd00::1]:6443",
+			wantHost: "https://[fd00::1]:6443",
+		},
+		{
+			name:     "IPv6 with zone",
+			server:   "https://[fe80::1%25eth0]:6443",
+			wantHost: "https://[fe80::1%25eth0]:6443",
+		},
+		{
+			name:     "IPv6 without scheme",
+			server:   "[fd00::1]:6443",
+			wantHost: "https://[fd00::1]:6443",
+		},
+		{
+			name:    "IPv6 without brackets",
+			server:  "https://fd00::1:6443",
+			wantErr: true,
+		},
+		{
+			name:    "IPv6 without scheme and brackets",
+			server:  "fd00::1:6443",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restConfig, err := newRestConfigFromServerAndAuth(tt.server,
+				&clientcmdapi.AuthInfo{Token: "fake-token"}, &clientcmd.ConfigOverrides{})
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSecret) {
+					t.Errorf("expected an invalid secret error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if restConfig.Host != tt.wantHost {
+				t.Errorf("Host = %s, want %s", restConfig.Host, tt.wantHost)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"fmt"
+	"time"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	ConditionWorkAPIUnavailable string = "WorkAPIUnavailable"
+	reasonWorkAPIUnavailable    string = "WorkAPIUnavailable"
+	reasonWorkAPIAvailable      string = "WorkAPIAvailable"
+)
+
+//workAPIUnavailableRequeueAfter is the delay before applying the manifestworks again
+//once the work API is unavailable
+const workAPIUnavailableRequeueAfter = 1 * time.Minute
+
+//isWorkAPIUnavailable returns true if the error is due to the ManifestWork kind not being served
+//by the hub, the work API is not installed or its discovery failed
+func isWorkAPIUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) ||
+		discovery.IsGroupDiscoveryFailedError(err) || apierrors.IsServiceUnavailable(err)
+}
+
+//hasManifestWorkCRD returns true if the ManifestWork kind is served by the hub
+func hasManifestWorkCRD(mapper meta.RESTMapper) (bool, error) {
+	gvk := workv1.GroupVersion.WithKind("ManifestWork")
+	if _, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+//reportWorkAPIUnavailable sets the WorkAPIUnavailable condition and requeues the ManagedCluster,
+//the condition is only patched when it is raised
+func (r *ReconcileManagedCluster) reportWorkAPIUnavailable(
+	managedCluster *clusterv1.ManagedCluster,
+	errIn error,
+) (reconcile.Result, error) {
+	clusterLogger(managedCluster.Name).Info("The work API is unavailable, the klusterlet manifestworks can't be applied",
+		"error", errIn.Error(), "requeueAfter", workAPIUnavailableRequeueAfter.String())
+	if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionWorkAPIUnavailable) {
+		message := fmt.Sprintf("The work API (%s) is unavailable on the hub, the klusterlet manifestworks "+
+			"can't be applied: %v", workv1.GroupVersion.String(), errIn)
+		r.recordEvent(managedCluster, corev1.EventTypeWarning, reasonWorkAPIUnavailable, message)
+		if err := r.setCondition(managedCluster, metav1.Condition{
+			Type:    ConditionWorkAPIUnavailable,
+			Status:  metav1.ConditionTrue,
+			Reason:  reasonWorkAPIUnavailable,
+			Message: message,
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{RequeueAfter: workAPIUnavailableRequeueAfter}, nil
+}
+
+//clearConditionWorkAPIUnavailable sets the WorkAPIUnavailable condition to False once the manifestworks
+//are applied, the condition is only patched if it was raised
+func (r *ReconcileManagedCluster) clearConditionWorkAPIUnavailable(managedCluster *clusterv1.ManagedCluster) error {
+	if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, ConditionWorkAPIUnavailable) {
+		return nil
+	}
+	return r.setCondition(managedCluster, metav1.Condition{
+		Type:    ConditionWorkAPIUnavailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonWorkAPIAvailable,
+		Message: "The klusterlet manifestworks are applied",
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	workv1 "github.com/open-cluster-management/api/work/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_isWorkAPIUnavailable(t *testing.T) {
+	gvk := workv1.GroupVersion.WithKind("ManifestWork")
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "no error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "no kind match",
+			err:  &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}},
+			want: true,
+		},
+		{
+			name: "not registered",
+			err:  runtime.NewNotRegisteredErrForKind("test", gvk),
+			want: true,
+		},
+		{
+			name: "discovery failed",
+			err: &discovery.ErrGroupDiscoveryFailed{
+				Groups: map[schema.GroupVersion]error{workv1.GroupVersion: fmt.Errorf("unavailable")},
+			},
+			want: true,
+		},
+		{
+			name: "service unavailable",
+			err:  errors.NewServiceUnavailable("the work API is down"),
+			want: true,
+		},
+		{
+			name: "not found",
+			err:  errors.NewNotFound(schema.GroupResource{Group: workv1.GroupName, Resource: "manifestworks"}, "test"),
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("connection refused"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isWorkAPIUnavailable(tt.err); got != tt.want {
+				t.Errorf("isWorkAPIUnavailable() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hasManifestWorkCRD(t *testing.T) {
+	noWork := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	noWork.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	work := meta.NewDefaultRESTMapper([]schema.GroupVersion{workv1.GroupVersion})
+	work.Add(workv1.GroupVersion.WithKind("ManifestWork"), meta.RESTScopeNamespace)
+
+	if got, err := hasManifestWorkCRD(noWork); err != nil || got {
+		t.Errorf("expected no ManifestWork CRD, got %t, %v", got, err)
+	}
+	if got, err := hasManifestWorkCRD(work); err != nil || !got {
+		t.Errorf("expected the ManifestWork CRD, got %t, %v", got, err)
+	}
+}
+
+func TestReconcileManagedCluster_reportWorkAPIUnavailable(t *testing.T) {
+	//The client of a hub without the work API
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	s.AddKnownTypes(clusterv1.SchemeGroupVersion, &clusterv1.ManagedCluster{})
+	managedCluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+	}
+	r := &ReconcileManagedCluster{
+		client: fake.NewFakeClientWithScheme(s, managedCluster),
+		scheme: s,
+	}
+
+	_, _, err := createOrUpdateManifestWorks(r.client, s, managedCluster, nil, nil, nil)
+	if !isWorkAPIUnavailable(err) {
+		t.Fatalf("expected the work API to be unavailable, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		result, err := r.reportWorkAPIUnavailable(managedCluster, err)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.RequeueAfter != workAPIUnavailableRequeueAfter {
+			t.Errorf("expected a requeue after %s, got %s", workAPIUnavailableRequeueAfter, result.RequeueAfter)
+		}
+	}
+	got := &clusterv1.ManagedCluster{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, ConditionWorkAPIUnavailable) {
+		t.Fatalf("expected the %s condition, got %v", ConditionWorkAPIUnavailable, got.Status.Conditions)
+	}
+
+	if err := r.clearConditionWorkAPIUnavailable(got); err != nil {
+		t.Fatal(err)
+	}
+	c := meta.FindStatusCondition(got.Status.Conditions, ConditionWorkAPIUnavailable)
+	if c == nil || c.Status != metav1.ConditionFalse || c.Reason != reasonWorkAPIAvailable {
+		t.Errorf("expected the %s condition to be cleared, got %v", ConditionWorkAPIUnavailable, c)
+	}
+}
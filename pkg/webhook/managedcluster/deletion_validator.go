@@ -0,0 +1,70 @@
+// Copyright Contributors to the Open Cluster Management project
+
+//Package managedcluster contains the admission webhooks for the ManagedCluster
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	managedclustercontroller "github.com/open-cluster-management/managedcluster-import-controller/pkg/controller/managedcluster"
+)
+
+const (
+	//DeletionValidatorPath is the path on which the deletion validating webhook is served
+	DeletionValidatorPath = "/validate-managedcluster-deletion"
+	//ConfirmLocalClusterDeletionAnnotation must be set to "true" on the local-cluster ManagedCluster
+	//to allow its deletion
+	ConfirmLocalClusterDeletionAnnotation = "import.open-cluster-management.io/confirm-local-cluster-deletion"
+)
+
+var log = logf.Log.WithName("webhook_managedcluster")
+
+// Add registers the ManagedCluster webhooks on the manager webhook server
+func Add(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(DeletionValidatorPath, &webhook.Admission{Handler: &DeletionValidator{}})
+	return nil
+}
+
+// DeletionValidator denies the deletion of the local-cluster ManagedCluster
+// unless the deletion is confirmed with an annotation
+type DeletionValidator struct{}
+
+// blank assignment to verify that DeletionValidator implements admission.Handler
+var _ admission.Handler = &DeletionValidator{}
+
+// Handle validates the admission request
+func (v *DeletionValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Delete {
+		return admission.Allowed("")
+	}
+	//Before kubernetes 1.15 the deleted object is not sent
+	if len(req.OldObject.Raw) == 0 {
+		log.Info("No object in the delete request, allowing", "name", req.Name)
+		return admission.Allowed("")
+	}
+	managedCluster := &clusterv1.ManagedCluster{}
+	if err := json.Unmarshal(req.OldObject.Raw, managedCluster); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !managedclustercontroller.IsSelfManaged(managedCluster) {
+		return admission.Allowed("")
+	}
+	if confirmed, _ := strconv.ParseBool(managedCluster.GetAnnotations()[ConfirmLocalClusterDeletionAnnotation]); confirmed {
+		log.Info("Deletion of the local-cluster confirmed", "name", managedCluster.Name)
+		return admission.Allowed("")
+	}
+	return admission.Denied(fmt.Sprintf(
+		"the ManagedCluster %s is the hub cluster, annotate it with %s=true to confirm its deletion",
+		managedCluster.Name, ConfirmLocalClusterDeletionAnnotation))
+}
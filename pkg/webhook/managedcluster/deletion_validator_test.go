@@ -0,0 +1,99 @@
+// Copyright Contributors to the Open Cluster Management project
+
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	clusterv1 "github.com/open-cluster-management/api/cluster/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	managedclustercontroller "github.com/open-cluster-management/managedcluster-import-controller/pkg/controller/managedcluster"
+)
+
+func TestDeletionValidator_Handle(t *testing.T) {
+	newRequest := func(operation admissionv1beta1.Operation, labels, annotations map[string]string) admission.Request {
+		managedCluster := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "local-cluster",
+				Labels:      labels,
+				Annotations: annotations,
+			},
+		}
+		raw, err := json.Marshal(managedCluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return admission.Request{
+			AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Name:      managedCluster.Name,
+				Operation: operation,
+				OldObject: runtime.RawExtension{Raw: raw},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		req         admission.Request
+		wantAllowed bool
+	}{
+		{
+			name:        "update of the local-cluster",
+			req:         newRequest(admissionv1beta1.Update, map[string]string{managedclustercontroller.SelfManagedLabel: "true"}, nil),
+			wantAllowed: true,
+		},
+		{
+			name:        "delete of a managed cluster",
+			req:         newRequest(admissionv1beta1.Delete, nil, nil),
+			wantAllowed: true,
+		},
+		{
+			name:        "delete of a managed cluster with local-cluster false",
+			req:         newRequest(admissionv1beta1.Delete, map[string]string{managedclustercontroller.SelfManagedLabel: "false"}, nil),
+			wantAllowed: true,
+		},
+		{
+			name:        "delete of the local-cluster",
+			req:         newRequest(admissionv1beta1.Delete, map[string]string{managedclustercontroller.SelfManagedLabel: "true"}, nil),
+			wantAllowed: false,
+		},
+		{
+			name: "delete of the local-cluster not confirmed",
+			req: newRequest(admissionv1beta1.Delete,
+				map[string]string{managedclustercontroller.SelfManagedLabel: "true"},
+				map[string]string{ConfirmLocalClusterDeletionAnnotation: "false"}),
+			wantAllowed: false,
+		},
+		{
+			name: "delete of the local-cluster confirmed",
+			req: newRequest(admissionv1beta1.Delete,
+				map[string]string{managedclustercontroller.SelfManagedLabel: "true"},
+				map[string]string{ConfirmLocalClusterDeletionAnnotation: "true"}),
+			wantAllowed: true,
+		},
+		{
+			name: "delete without object",
+			req: admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Name:      "local-cluster",
+					Operation: admissionv1beta1.Delete,
+				},
+			},
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &DeletionValidator{}
+			got := v.Handle(context.TODO(), tt.req)
+			if got.Allowed != tt.wantAllowed {
+				t.Errorf("DeletionValidator.Handle() allowed = %v, want %v, result %v", got.Allowed, tt.wantAllowed, got.Result)
+			}
+		})
+	}
+}